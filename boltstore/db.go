@@ -0,0 +1,201 @@
+package boltstore
+
+import (
+	"encoding/binary"
+	"errors"
+	"imperials/maps"
+	"log"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	MetaBucket       = "meta"
+	ServersBucket    = "servers"
+	GamesBucket      = "games"
+	JournalsBucket   = "journals"
+	GameStatesBucket = "game_states"
+	UsersBucket      = "users"
+	UsernamesBucket  = "users_by_username"
+	EmailsBucket     = "users_by_email"
+	MapsBucket       = "maps"
+	RatingsBucket    = "ratings"
+
+	schemaVersionKey = "schema_version"
+)
+
+// Schema migrations, applied in order on open.
+// The index of a migration plus one is the schema version it produces.
+// Never edit or reorder existing entries, only append.
+var migrations = []func(tx *bolt.Tx) error{
+	// 1: Create buckets
+	func(tx *bolt.Tx) error {
+		for _, name := range []string{
+			ServersBucket,
+			GamesBucket,
+			JournalsBucket,
+			GameStatesBucket,
+			UsersBucket,
+			UsernamesBucket,
+			EmailsBucket,
+			MapsBucket,
+		} {
+			if _, err := tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+
+	// 2: Seed the official base map
+	func(tx *bolt.Tx) error {
+		defn := maps.GetBaseMap()
+		return putRecord(tx.Bucket([]byte(MapsBucket)), []byte(defn.Name), &boltMap{
+			Name:      defn.Name,
+			Official:  true,
+			Defn:      defn,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		})
+	},
+
+	// 3: Seed the official seafarers map
+	func(tx *bolt.Tx) error {
+		defn := maps.GetSeafarersMap()
+		return putRecord(tx.Bucket([]byte(MapsBucket)), []byte(defn.Name), &boltMap{
+			Name:      defn.Name,
+			Official:  true,
+			Defn:      defn,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		})
+	},
+
+	// 4: Add the rivers to the base map
+	func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(MapsBucket))
+		defn := maps.GetBaseMap()
+
+		var m boltMap
+		if err := getRecord(bucket, []byte(defn.Name), &m); err != nil {
+			return err
+		}
+
+		m.Defn = defn
+		m.UpdatedAt = time.Now()
+		return putRecord(bucket, []byte(defn.Name), &m)
+	},
+
+	// 5: Start the version history of the maps
+	func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(MapsBucket))
+
+		updated := make(map[string]*boltMap)
+		err := bucket.ForEach(func(k, v []byte) error {
+			var m boltMap
+			if err := getRecord(bucket, k, &m); err != nil {
+				return err
+			}
+
+			m.Version = 1
+			m.Versions = []*boltMapVersion{{Version: 1, Defn: m.Defn, CreatedAt: m.UpdatedAt}}
+			updated[string(k)] = &m
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// Keys cannot be written while iterating over the bucket
+		for k, m := range updated {
+			if err := putRecord(bucket, []byte(k), m); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+
+	// 6: Create the ratings bucket, keyed by user then mode and players
+	func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(RatingsBucket))
+		return err
+	},
+}
+
+// Open the database file at path, creating it if needed,
+// and bring its schema up to date.
+func Open(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	log.Println("Opened database", path)
+	return &BoltStore{db: db}, nil
+}
+
+func (ds *BoltStore) Close() error {
+	return ds.db.Close()
+}
+
+func migrate(db *bolt.DB) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		meta, err := tx.CreateBucketIfNotExists([]byte(MetaBucket))
+		if err != nil {
+			return err
+		}
+
+		version := uint64(0)
+		if v := meta.Get([]byte(schemaVersionKey)); v != nil {
+			version = btoi(v)
+		}
+
+		if version > uint64(len(migrations)) {
+			return errors.New("database schema is newer than this server")
+		}
+
+		for i := version; i < uint64(len(migrations)); i++ {
+			if err := migrations[i](tx); err != nil {
+				return err
+			}
+			log.Println("Migrated database to schema version", i+1)
+		}
+
+		return meta.Put([]byte(schemaVersionKey), itob(uint64(len(migrations))))
+	})
+}
+
+func itob(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+	return b
+}
+
+func btoi(b []byte) uint64 {
+	return binary.BigEndian.Uint64(b)
+}
+
+func getRecord(b *bolt.Bucket, key []byte, v interface{}) error {
+	data := b.Get(key)
+	if data == nil {
+		return errNotFound
+	}
+	return msgpack.Unmarshal(data, v)
+}
+
+func putRecord(b *bolt.Bucket, key []byte, v interface{}) error {
+	data, err := msgpack.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return b.Put(key, data)
+}
+
+var errNotFound = errors.New("not found")
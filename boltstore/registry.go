@@ -0,0 +1,195 @@
+package boltstore
+
+import (
+	"errors"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Time without a heartbeat after which a server is gone
+const serverExpiry = time.Minute
+
+type (
+	// BoltRegistry is the embedded counterpart of mango.MangoRegistry.
+	// It shares its database with the BoltStore so that ReadUser sees its users.
+	BoltRegistry struct {
+		Store *BoltStore
+	}
+)
+
+func (br *BoltRegistry) Init() {}
+
+func (br *BoltRegistry) Register(url, region string) error {
+	// Local servers without SERVER_URL have nothing to register
+	if url == "" {
+		return nil
+	}
+
+	err := br.Store.db.Update(func(tx *bolt.Tx) error {
+		servers := tx.Bucket([]byte(ServersBucket))
+
+		s := boltServer{URL: url, CreatedAt: time.Now()}
+		getRecord(servers, []byte(url), &s)
+		s.Region = region
+		return putRecord(servers, []byte(url), &s)
+	})
+	if err != nil {
+		return err
+	}
+
+	return br.Heartbeat(url)
+}
+
+func (br *BoltRegistry) Heartbeat(url string) error {
+	if url == "" {
+		return nil
+	}
+
+	return br.Store.db.Update(func(tx *bolt.Tx) error {
+		servers := tx.Bucket([]byte(ServersBucket))
+
+		s := boltServer{URL: url, CreatedAt: time.Now()}
+		getRecord(servers, []byte(url), &s)
+		s.UpdatedAt = time.Now()
+		return putRecord(servers, []byte(url), &s)
+	})
+}
+
+// Region of each server by url, like the servers collection
+// servers expire without a heartbeat
+func (br *BoltRegistry) GetServerRegions() (map[string]string, error) {
+	regions := make(map[string]string)
+	err := br.Store.db.View(func(tx *bolt.Tx) error {
+		servers := tx.Bucket([]byte(ServersBucket))
+		return servers.ForEach(func(k, v []byte) error {
+			var s boltServer
+			if err := getRecord(servers, k, &s); err != nil {
+				return err
+			}
+			if time.Since(s.UpdatedAt) < serverExpiry {
+				regions[s.URL] = s.Region
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return regions, nil
+}
+
+func (br *BoltRegistry) CreateUser(id, username string) error {
+	return br.CreateUserWithEmail(id, username, username+"@imperials.app")
+}
+
+func (br *BoltRegistry) CreateUserWithEmail(id, username, email string) error {
+	return br.Store.db.Update(func(tx *bolt.Tx) error {
+		users := tx.Bucket([]byte(UsersBucket))
+		usernames := tx.Bucket([]byte(UsernamesBucket))
+		emails := tx.Bucket([]byte(EmailsBucket))
+
+		// Mirror the unique indexes of the users table
+		if users.Get([]byte(id)) != nil {
+			return errors.New("duplicate user id")
+		}
+		if usernames.Get([]byte(username)) != nil {
+			return errors.New("duplicate username")
+		}
+		if emails.Get([]byte(email)) != nil {
+			return errors.New("duplicate email")
+		}
+
+		err := putRecord(users, []byte(id), &boltUser{
+			ID:        id,
+			Username:  username,
+			Email:     email,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+			Games:     make([]uint64, 0),
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := usernames.Put([]byte(username), []byte(id)); err != nil {
+			return err
+		}
+		return emails.Put([]byte(email), []byte(id))
+	})
+}
+
+func (br *BoltRegistry) CheckIfUserExists(id string) (bool, error) {
+	exists := false
+	br.Store.db.View(func(tx *bolt.Tx) error {
+		exists = tx.Bucket([]byte(UsersBucket)).Get([]byte(id)) != nil
+		return nil
+	})
+
+	if !exists {
+		return false, errors.New("user not found")
+	}
+	return true, nil
+}
+
+func (br *BoltRegistry) CheckIfUserEmailExists(email string) (map[string]interface{}, error) {
+	var u boltUser
+	err := br.Store.db.View(func(tx *bolt.Tx) error {
+		id := tx.Bucket([]byte(EmailsBucket)).Get([]byte(email))
+		if id == nil {
+			return errNotFound
+		}
+		return getRecord(tx.Bucket([]byte(UsersBucket)), id, &u)
+	})
+	if err != nil {
+		return nil, errors.New("email not found")
+	}
+
+	return u.toMap(), nil
+}
+
+func (br *BoltRegistry) UpdateUsername(id, username string) error {
+	return br.Store.db.Update(func(tx *bolt.Tx) error {
+		usernames := tx.Bucket([]byte(UsernamesBucket))
+		if owner := usernames.Get([]byte(username)); owner != nil && string(owner) != id {
+			return errors.New("duplicate username")
+		}
+
+		var old string
+		err := updateUser(tx, id, func(u *boltUser) {
+			old = u.Username
+			u.Username = username
+		})
+		if err != nil || old == "" {
+			return err
+		}
+
+		if err := usernames.Delete([]byte(old)); err != nil {
+			return err
+		}
+		return usernames.Put([]byte(username), []byte(id))
+	})
+}
+
+func (br *BoltRegistry) UpdateEmail(id, email string) error {
+	return br.Store.db.Update(func(tx *bolt.Tx) error {
+		emails := tx.Bucket([]byte(EmailsBucket))
+		if owner := emails.Get([]byte(email)); owner != nil && string(owner) != id {
+			return errors.New("duplicate email")
+		}
+
+		var old string
+		err := updateUser(tx, id, func(u *boltUser) {
+			old = u.Email
+			u.Email = email
+		})
+		if err != nil || old == "" {
+			return err
+		}
+
+		if err := emails.Delete([]byte(old)); err != nil {
+			return err
+		}
+		return emails.Put([]byte(email), []byte(id))
+	})
+}
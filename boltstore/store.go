@@ -0,0 +1,827 @@
+package boltstore
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"imperials/entities"
+	"imperials/maps"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+	bolt "go.etcd.io/bbolt"
+)
+
+type (
+	// BoltStore persists games, users and maps in a single local database
+	// file. It implements game.Store with the same semantics as
+	// mango.MangoStore.
+	BoltStore struct {
+		db *bolt.DB
+	}
+
+	boltGame struct {
+		ID            string    `msgpack:"id"`
+		CreatedAt     time.Time `msgpack:"createdAt"`
+		UpdatedAt     time.Time `msgpack:"updatedAt"`
+		Stage         int       `msgpack:"stage"`
+		Players       int       `msgpack:"players"`
+		ActivePlayers int       `msgpack:"active_players"`
+		Host          string    `msgpack:"host"`
+		Server        string    `msgpack:"server"`
+		Private       bool      `msgpack:"private"`
+		Settings      []byte    `msgpack:"settings"`
+		StateID       uint64    `msgpack:"state_id"`
+	}
+
+	boltGameState struct {
+		CreatedAt time.Time `msgpack:"createdAt"`
+		State     []byte    `msgpack:"state"`
+		Report    []byte    `msgpack:"report,omitempty"`
+	}
+
+	boltUser struct {
+		ID        string    `msgpack:"id"`
+		Username  string    `msgpack:"username"`
+		Email     string    `msgpack:"email"`
+		CreatedAt time.Time `msgpack:"createdAt"`
+		UpdatedAt time.Time `msgpack:"updatedAt"`
+		Games     []uint64  `msgpack:"games"`
+		Started   int32     `msgpack:"started"`
+		Finished  int32     `msgpack:"finished"`
+	}
+
+	boltMap struct {
+		Name      string                  `msgpack:"name"`
+		Creator   string                  `msgpack:"creator"`
+		Official  bool                    `msgpack:"official"`
+		Private   bool                    `msgpack:"private"`
+		Version   int                     `msgpack:"version"`
+		Defn      *entities.MapDefinition `msgpack:"map"`
+		CreatedAt time.Time               `msgpack:"createdAt"`
+		UpdatedAt time.Time               `msgpack:"updatedAt"`
+
+		// Every saved version of the definition, oldest first
+		Versions []*boltMapVersion `msgpack:"versions"`
+	}
+
+	boltMapVersion struct {
+		Version   int                     `msgpack:"version"`
+		Defn      *entities.MapDefinition `msgpack:"map"`
+		CreatedAt time.Time               `msgpack:"createdAt"`
+	}
+
+	boltServer struct {
+		URL       string    `msgpack:"url"`
+		Region    string    `msgpack:"region"`
+		CreatedAt time.Time `msgpack:"createdAt"`
+		UpdatedAt time.Time `msgpack:"updatedAt"`
+	}
+)
+
+func (ds *BoltStore) Init(id string) error {
+	return ds.CreateGameIfNotExists(id)
+}
+
+func (ds *BoltStore) CreateGameIfNotExists(id string) error {
+	return ds.db.Update(func(tx *bolt.Tx) error {
+		games := tx.Bucket([]byte(GamesBucket))
+		if games.Get([]byte(id)) != nil {
+			return nil
+		}
+
+		return putRecord(games, []byte(id), &boltGame{
+			ID:        id,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+			Server:    os.Getenv("SERVER_URL"),
+		})
+	})
+}
+
+func (ds *BoltStore) TerminateGame(id string) error {
+	return ds.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket([]byte(GamesBucket)).Delete([]byte(id)); err != nil {
+			return err
+		}
+
+		err := tx.Bucket([]byte(JournalsBucket)).DeleteBucket([]byte(id))
+		if err != nil && err != bolt.ErrBucketNotFound {
+			return err
+		}
+		return nil
+	})
+}
+
+// Read, modify and write back the game with the given id
+// Missing games are ignored, like an update matching no documents
+func (ds *BoltStore) updateGame(id string, f func(g *boltGame)) error {
+	return ds.db.Update(func(tx *bolt.Tx) error {
+		games := tx.Bucket([]byte(GamesBucket))
+
+		var g boltGame
+		err := getRecord(games, []byte(id), &g)
+		if err == errNotFound {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		f(&g)
+		return putRecord(games, []byte(id), &g)
+	})
+}
+
+func (ds *BoltStore) readGame(tx *bolt.Tx, id string) (*boltGame, error) {
+	var g boltGame
+	if err := getRecord(tx.Bucket([]byte(GamesBucket)), []byte(id), &g); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+func (ds *BoltStore) WriteGameServer(id string) error {
+	return ds.updateGame(id, func(g *boltGame) {
+		g.Server = os.Getenv("SERVER_URL")
+	})
+}
+
+func (ds *BoltStore) WriteGameStarted(id string) error {
+	return ds.updateGame(id, func(g *boltGame) {
+		g.Stage = 1
+		g.UpdatedAt = time.Now()
+	})
+}
+
+func (ds *BoltStore) WriteGameFinished(id string) error {
+	return ds.updateGame(id, func(g *boltGame) {
+		g.Stage = 2
+		g.UpdatedAt = time.Now()
+	})
+}
+
+func (ds *BoltStore) WriteGameActivePlayers(id string, numPlayers int32, host string) error {
+	return ds.updateGame(id, func(g *boltGame) {
+		g.ActivePlayers = int(numPlayers)
+		g.UpdatedAt = time.Now()
+		if host != "" {
+			g.Host = host
+		}
+	})
+}
+
+func (ds *BoltStore) WriteGamePlayers(id string, numPlayers int32) error {
+	return ds.updateGame(id, func(g *boltGame) {
+		if g.Stage == 0 {
+			g.Players = int(numPlayers)
+			g.UpdatedAt = time.Now()
+		}
+	})
+}
+
+func (ds *BoltStore) WriteGamePrivacy(id string, private bool) error {
+	return ds.updateGame(id, func(g *boltGame) {
+		g.Private = private
+		g.UpdatedAt = time.Now()
+	})
+}
+
+func (ds *BoltStore) WriteGameSettings(id string, settings []byte) error {
+	return ds.updateGame(id, func(g *boltGame) {
+		if g.Stage == 0 {
+			g.Settings = settings
+			g.UpdatedAt = time.Now()
+		}
+	})
+}
+
+func (ds *BoltStore) WriteJournalEntries(id string, entries [][]byte) error {
+	return ds.db.Update(func(tx *bolt.Tx) error {
+		if _, err := ds.readGame(tx, id); err != nil {
+			return nil
+		}
+
+		journal, err := tx.Bucket([]byte(JournalsBucket)).CreateBucketIfNotExists([]byte(id))
+		if err != nil {
+			return err
+		}
+
+		for _, e := range entries {
+			seq, err := journal.NextSequence()
+			if err != nil {
+				return err
+			}
+			if err := journal.Put(itob(seq), e); err != nil {
+				return err
+			}
+		}
+
+		games := tx.Bucket([]byte(GamesBucket))
+		var g boltGame
+		if err := getRecord(games, []byte(id), &g); err != nil {
+			return err
+		}
+		g.UpdatedAt = time.Now()
+		return putRecord(games, []byte(id), &g)
+	})
+}
+
+func (ds *BoltStore) TruncateJournal(id string, length int) error {
+	return ds.db.Update(func(tx *bolt.Tx) error {
+		if _, err := ds.readGame(tx, id); err != nil {
+			return nil
+		}
+
+		journal := tx.Bucket([]byte(JournalsBucket)).Bucket([]byte(id))
+		if journal == nil {
+			return nil
+		}
+
+		// Keys are sequential, keep the first entries
+		// Deleting while iterating skips keys, so collect them first
+		keys := make([][]byte, 0)
+		i := 0
+		c := journal.Cursor()
+		for k, _ := c.First(); k != nil; k, _ = c.Next() {
+			i++
+			if i > length {
+				keys = append(keys, append([]byte{}, k...))
+			}
+		}
+
+		for _, k := range keys {
+			if err := journal.Delete(k); err != nil {
+				return err
+			}
+		}
+
+		games := tx.Bucket([]byte(GamesBucket))
+		var g boltGame
+		if err := getRecord(games, []byte(id), &g); err != nil {
+			return err
+		}
+		g.UpdatedAt = time.Now()
+		return putRecord(games, []byte(id), &g)
+	})
+}
+
+func (ds *BoltStore) ReadJournal(id string) ([][]byte, error) {
+	journalBytes := make([][]byte, 0)
+
+	err := ds.db.View(func(tx *bolt.Tx) error {
+		if _, err := ds.readGame(tx, id); err != nil {
+			return errors.New("database entry for journal not found")
+		}
+
+		journal := tx.Bucket([]byte(JournalsBucket)).Bucket([]byte(id))
+		if journal == nil {
+			return nil
+		}
+
+		// Values are only valid during the transaction
+		return journal.ForEach(func(k, v []byte) error {
+			e := make([]byte, len(v))
+			copy(e, v)
+			journalBytes = append(journalBytes, e)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return journalBytes, nil
+}
+
+func (ds *BoltStore) ReadGamePlayers(id string) (int, error) {
+	var g *boltGame
+	err := ds.db.View(func(tx *bolt.Tx) (err error) {
+		g, err = ds.readGame(tx, id)
+		return err
+	})
+	if err != nil {
+		return 0, errors.New("database entry for game not found")
+	}
+
+	return g.Players, nil
+}
+
+func (ds *BoltStore) CheckIfJournalExists(id string) (bool, error) {
+	exists := false
+	err := ds.db.View(func(tx *bolt.Tx) error {
+		if _, err := ds.readGame(tx, id); err != nil {
+			return errors.New("database entry for journal not found")
+		}
+
+		journal := tx.Bucket([]byte(JournalsBucket)).Bucket([]byte(id))
+		if journal != nil {
+			k, _ := journal.Cursor().First()
+			exists = k != nil
+		}
+		return nil
+	})
+	return exists, err
+}
+
+func (ds *BoltStore) CreateGameStateIfNotExists(id string, state []byte) error {
+	return ds.db.Update(func(tx *bolt.Tx) error {
+		g, err := ds.readGame(tx, id)
+		if err == nil && g.StateID != 0 {
+			return errors.New("game state already created")
+		}
+
+		states := tx.Bucket([]byte(GameStatesBucket))
+		sid, err := states.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		err = putRecord(states, itob(sid), &boltGameState{
+			CreatedAt: time.Now(),
+			State:     state,
+		})
+		if err != nil {
+			return err
+		}
+
+		if g != nil {
+			g.StateID = sid
+			return putRecord(tx.Bucket([]byte(GamesBucket)), []byte(id), g)
+		}
+		return nil
+	})
+}
+
+func (ds *BoltStore) WriteGameState(id string, state []byte) error {
+	return ds.db.Update(func(tx *bolt.Tx) error {
+		g, err := ds.readGame(tx, id)
+		if err != nil || g.StateID == 0 {
+			return errors.New("game state not created")
+		}
+
+		states := tx.Bucket([]byte(GameStatesBucket))
+		var s boltGameState
+		if err := getRecord(states, itob(g.StateID), &s); err != nil {
+			return err
+		}
+
+		s.State = state
+		return putRecord(states, itob(g.StateID), &s)
+	})
+}
+
+func (ds *BoltStore) ReadGameState(id string) ([]byte, error) {
+	var s boltGameState
+	err := ds.db.View(func(tx *bolt.Tx) error {
+		g, err := ds.readGame(tx, id)
+		if err != nil || g.StateID == 0 {
+			return errors.New("game state not created")
+		}
+
+		return getRecord(tx.Bucket([]byte(GameStatesBucket)), itob(g.StateID), &s)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.State, nil
+}
+
+func (ds *BoltStore) WriteGameReport(id string, report []byte) error {
+	return ds.db.Update(func(tx *bolt.Tx) error {
+		g, err := ds.readGame(tx, id)
+		if err != nil || g.StateID == 0 {
+			return errors.New("game state not created")
+		}
+
+		states := tx.Bucket([]byte(GameStatesBucket))
+		var s boltGameState
+		if err := getRecord(states, itob(g.StateID), &s); err != nil {
+			return err
+		}
+
+		s.Report = report
+		return putRecord(states, itob(g.StateID), &s)
+	})
+}
+
+func (ds *BoltStore) ReadGameReport(id string) ([]byte, error) {
+	var s boltGameState
+	err := ds.db.View(func(tx *bolt.Tx) error {
+		g, err := ds.readGame(tx, id)
+		if err != nil || g.StateID == 0 {
+			return errors.New("game state not created")
+		}
+
+		return getRecord(tx.Bucket([]byte(GameStatesBucket)), itob(g.StateID), &s)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Report == nil {
+		return nil, errors.New("game report not found")
+	}
+	return s.Report, nil
+}
+
+func (ds *BoltStore) WriteGameIdForUser(id string, userId string, settings *entities.GameSettings) error {
+	return ds.db.Update(func(tx *bolt.Tx) error {
+		g, err := ds.readGame(tx, id)
+		if err != nil || g.StateID == 0 {
+			return errors.New("game state not created")
+		}
+
+		return updateUser(tx, userId, func(u *boltUser) {
+			u.Games = append(u.Games, g.StateID)
+			u.UpdatedAt = time.Now()
+			if settings.EnableKarma {
+				u.Started++
+			}
+		})
+	})
+}
+
+func (ds *BoltStore) WriteGameCompletedForUser(id string) error {
+	return ds.db.Update(func(tx *bolt.Tx) error {
+		return updateUser(tx, id, func(u *boltUser) {
+			u.Finished++
+			u.UpdatedAt = time.Now()
+		})
+	})
+}
+
+// Ratings of users that do not exist are ignored
+func (ds *BoltStore) WriteRatings(ratings []*entities.Rating) error {
+	return ds.db.Update(func(tx *bolt.Tx) error {
+		users := tx.Bucket([]byte(UsersBucket))
+		bucket := tx.Bucket([]byte(RatingsBucket))
+		for _, r := range ratings {
+			if users.Get([]byte(r.UserID)) == nil {
+				continue
+			}
+			if err := putRecord(bucket, ratingKey(r.UserID, r.Mode, r.Players), r); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (ds *BoltStore) ReadRatings(userId string) ([]*entities.Rating, error) {
+	ratings := make([]*entities.Rating, 0)
+	err := ds.db.View(func(tx *bolt.Tx) error {
+		if tx.Bucket([]byte(UsersBucket)).Get([]byte(userId)) == nil {
+			return errors.New("database entry for user not found")
+		}
+
+		prefix := []byte(userId + "/")
+		c := tx.Bucket([]byte(RatingsBucket)).Cursor()
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			var r entities.Rating
+			if err := msgpack.Unmarshal(v, &r); err != nil {
+				return err
+			}
+			ratings = append(ratings, &r)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ratings, nil
+}
+
+// Latest games of a user, newest first, all of them without a limit
+func (ds *BoltStore) ReadUserGames(userId string, limit int) ([]*entities.UserGame, error) {
+	games := make([]*entities.UserGame, 0)
+	err := ds.db.View(func(tx *bolt.Tx) error {
+		var u boltUser
+		if err := getRecord(tx.Bucket([]byte(UsersBucket)), []byte(userId), &u); err != nil {
+			return errors.New("database entry for user not found")
+		}
+
+		states := tx.Bucket([]byte(GameStatesBucket))
+		for i := len(u.Games) - 1; i >= 0 && (limit <= 0 || len(games) < limit); i-- {
+			var s boltGameState
+			if err := getRecord(states, itob(u.Games[i]), &s); err != nil {
+				continue
+			}
+			games = append(games, &entities.UserGame{
+				State:     s.State,
+				Report:    s.Report,
+				CreatedAt: s.CreatedAt,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return games, nil
+}
+
+func (ds *BoltStore) ReadLeaderboard(mode entities.GameMode, players int, limit int) ([]*entities.Rating, error) {
+	ratings := make([]*entities.Rating, 0)
+	err := ds.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(RatingsBucket)).ForEach(func(k, v []byte) error {
+			var r entities.Rating
+			if err := msgpack.Unmarshal(v, &r); err != nil {
+				return err
+			}
+			ratings = append(ratings, &r)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entities.BestRatings(ratings, mode, players, limit), nil
+}
+
+func (ds *BoltStore) ReadUser(id string) (map[string]interface{}, error) {
+	var u boltUser
+	err := ds.db.View(func(tx *bolt.Tx) error {
+		return getRecord(tx.Bucket([]byte(UsersBucket)), []byte(id), &u)
+	})
+	if err != nil {
+		return nil, errors.New("database entry for user not found")
+	}
+
+	return u.toMap(), nil
+}
+
+func (ds *BoltStore) GetOfficalMapNames() []string {
+	names := make([]string, 0)
+	ds.forEachMap(func(m *boltMap) {
+		if m.Official {
+			names = append(names, m.Name)
+		}
+	})
+
+	sort.Strings(names)
+	return names
+}
+
+// Get all maps excluding user maps if exclude
+func (ds *BoltStore) GetAllMapNamesForUser(userId string, exclude bool) ([]string, error) {
+	var names []string
+	err := ds.forEachMap(func(m *boltMap) {
+		if !exclude && m.Creator == userId {
+			names = append(names, m.Name)
+		} else if exclude && m.Creator != userId && !m.Official && !m.Private {
+			names = append(names, m.Name)
+		}
+	})
+	if err != nil {
+		return nil, errors.New("could not get maps")
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+func (ds *BoltStore) GetMap(name string) *entities.MapDefinition {
+	var m boltMap
+	err := ds.db.View(func(tx *bolt.Tx) error {
+		return getRecord(tx.Bucket([]byte(MapsBucket)), []byte(name), &m)
+	})
+	if err != nil {
+		return nil
+	}
+
+	return m.Defn
+}
+
+func (ds *BoltStore) GetMapInfo(name string) *entities.MapInfo {
+	m, err := ds.getMap(name)
+	if err != nil {
+		return nil
+	}
+
+	info := m.toInfo()
+	info.Defn = m.Defn
+	return info
+}
+
+func (ds *BoltStore) GetMapVersion(name string, version int) *entities.MapInfo {
+	m, err := ds.getMap(name)
+	if err != nil {
+		return nil
+	}
+
+	for _, v := range m.Versions {
+		if v.Version == version {
+			info := m.toInfo()
+			info.Version = v.Version
+			info.UpdatedAt = v.CreatedAt
+			info.Defn = v.Defn
+			return info
+		}
+	}
+	return nil
+}
+
+// Versions of a map without their definitions, newest first
+func (ds *BoltStore) GetMapVersions(name string) ([]*entities.MapInfo, error) {
+	m, err := ds.getMap(name)
+	if err != nil {
+		return nil, errors.New("map not found")
+	}
+
+	versions := make([]*entities.MapInfo, 0, len(m.Versions))
+	for i := len(m.Versions) - 1; i >= 0; i-- {
+		info := m.toInfo()
+		info.Version = m.Versions[i].Version
+		info.UpdatedAt = m.Versions[i].CreatedAt
+		versions = append(versions, info)
+	}
+	return versions, nil
+}
+
+// Add or replace a map definition
+func (ds *BoltStore) WriteMap(defn *entities.MapDefinition, creator string, official bool) error {
+	if defn == nil {
+		return errors.New("map must have a name")
+	}
+
+	info := &entities.MapInfo{
+		Name:     defn.Name,
+		Creator:  creator,
+		Official: official,
+		Defn:     defn,
+	}
+	_, err := ds.CreateMapInfo(info)
+	if err == entities.ErrMapExists {
+		_, err = ds.WriteMapInfo(info)
+	}
+	return err
+}
+
+// Save the first version of a map, the name must not be taken
+func (ds *BoltStore) CreateMapInfo(info *entities.MapInfo) (*entities.MapInfo, error) {
+	if err := validateMapInfo(info); err != nil {
+		return nil, err
+	}
+
+	var saved *entities.MapInfo
+	err := ds.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(MapsBucket))
+		if bucket.Get([]byte(info.Name)) != nil {
+			return entities.ErrMapExists
+		}
+
+		now := time.Now()
+		m := boltMap{
+			Name:      info.Name,
+			Creator:   info.Creator,
+			Official:  info.Official,
+			CreatedAt: now,
+		}
+		saved = m.addVersion(info, now)
+		return putRecord(bucket, []byte(info.Name), &m)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+// Save a new version of a map of the creator in info
+// The official flag of the map is kept
+func (ds *BoltStore) WriteMapInfo(info *entities.MapInfo) (*entities.MapInfo, error) {
+	if err := validateMapInfo(info); err != nil {
+		return nil, err
+	}
+
+	var saved *entities.MapInfo
+	err := ds.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(MapsBucket))
+
+		var m boltMap
+		if err := getRecord(bucket, []byte(info.Name), &m); err == errNotFound {
+			return entities.ErrMapNotFound
+		} else if err != nil {
+			return err
+		}
+		if m.Creator != info.Creator {
+			return entities.ErrMapNotFound
+		}
+
+		saved = m.addVersion(info, time.Now())
+		return putRecord(bucket, []byte(info.Name), &m)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+func validateMapInfo(info *entities.MapInfo) error {
+	if info.Defn == nil || info.Name == "" || info.Defn.Name != info.Name {
+		return errors.New("map must have a name")
+	}
+	return maps.ValidateMapDefinition(info.Defn)
+}
+
+func (m *boltMap) addVersion(info *entities.MapInfo, now time.Time) *entities.MapInfo {
+	m.Private = info.Private
+	m.Version++
+	m.Defn = info.Defn
+	m.UpdatedAt = now
+	m.Versions = append(m.Versions, &boltMapVersion{
+		Version:   m.Version,
+		Defn:      info.Defn,
+		CreatedAt: now,
+	})
+
+	saved := m.toInfo()
+	saved.Defn = m.Defn
+	return saved
+}
+
+// Remove a map with all of its versions
+func (ds *BoltStore) DeleteMap(name string) error {
+	return ds.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(MapsBucket))
+		if bucket.Get([]byte(name)) == nil {
+			return errors.New("map not found")
+		}
+		return bucket.Delete([]byte(name))
+	})
+}
+
+func (ds *BoltStore) getMap(name string) (*boltMap, error) {
+	var m boltMap
+	err := ds.db.View(func(tx *bolt.Tx) error {
+		return getRecord(tx.Bucket([]byte(MapsBucket)), []byte(name), &m)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (ds *BoltStore) forEachMap(f func(m *boltMap)) error {
+	return ds.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(MapsBucket)).ForEach(func(k, v []byte) error {
+			var m boltMap
+			if err := getRecord(tx.Bucket([]byte(MapsBucket)), k, &m); err != nil {
+				return err
+			}
+			f(&m)
+			return nil
+		})
+	})
+}
+
+func updateUser(tx *bolt.Tx, id string, f func(u *boltUser)) error {
+	users := tx.Bucket([]byte(UsersBucket))
+
+	var u boltUser
+	err := getRecord(users, []byte(id), &u)
+	if err == errNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	f(&u)
+	return putRecord(users, []byte(id), &u)
+}
+
+func ratingKey(userId string, mode entities.GameMode, players int) []byte {
+	return []byte(fmt.Sprintf("%s/%d/%d", userId, mode, players))
+}
+
+func (m *boltMap) toInfo() *entities.MapInfo {
+	return &entities.MapInfo{
+		Name:      m.Name,
+		Creator:   m.Creator,
+		Official:  m.Official,
+		Private:   m.Private,
+		Version:   m.Version,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+func (u *boltUser) toMap() map[string]interface{} {
+	games := make([]interface{}, len(u.Games))
+	for i, g := range u.Games {
+		games[i] = g
+	}
+
+	return map[string]interface{}{
+		"id":        u.ID,
+		"username":  u.Username,
+		"email":     u.Email,
+		"createdAt": u.CreatedAt,
+		"updatedAt": u.UpdatedAt,
+		"games":     games,
+		"started":   u.Started,
+		"finished":  u.Finished,
+	}
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"imperials/entities"
+	"imperials/game"
+	"imperials/maps"
+	"imperials/memstore"
+	"imperials/render"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type (
+	// Result of one simulated game
+	GameResult struct {
+		Game   int   `json:"game"`
+		Seed   int64 `json:"seed"`
+		Winner int   `json:"winner"`
+		Turns  int   `json:"turns"`
+
+		// Strategy and difficulty of each player
+		Strategies   []string `json:"strategies"`
+		Difficulties []string `json:"difficulties"`
+
+		// Victory points of each player after every turn
+		VPCurves [][]int `json:"vp_curves"`
+
+		// Number of rolls of each total, index 0 is a roll of 2
+		Dice [11]int `json:"dice"`
+
+		Error string `json:"error,omitempty"`
+	}
+)
+
+func main() {
+	numGames := flag.Int("n", 10, "number of games to play")
+	numPlayers := flag.Int("players", 4, "number of bots in each game")
+	mode := flag.String("mode", "base", "game mode, base, ck, sea, fish, rivers, caravans, barbarians or tb")
+	bots := flag.String("bots", game.BotStrategyDefault, "comma separated bot strategies, repeated over the seats")
+	difficulty := flag.String("difficulty", entities.BotDifficultyMedium, "comma separated bot difficulties, repeated over the seats")
+	seed := flag.Int64("seed", 0, "seed of the first game, incremented for each game (0 for random)")
+	victoryPoints := flag.Int("vp", 10, "victory points to win")
+	discardLimit := flag.Int("discard", 7, "discard limit")
+	balanced := flag.Bool("balanced", false, "draw the dice from a balanced deck")
+	events := flag.Bool("events", false, "draw the rolls from the event card deck with its events")
+	mapFile := flag.String("map", "", "JSON map definition to play on instead of the base map")
+	maxTicks := flag.Int("max-ticks", 100000, "give up on a game after this many ticks")
+	format := flag.String("format", "json", "output format, json or csv")
+	out := flag.String("out", "", "output file (default stdout)")
+	verbose := flag.Bool("v", false, "show game logs")
+	board := flag.Bool("board", false, "print the final board of each game to stderr")
+	flag.Parse()
+
+	if !*verbose {
+		log.SetOutput(io.Discard)
+	}
+
+	settings := entities.GameSettings{
+		Mode:          entities.Base,
+		MapName:       "Base",
+		DiscardLimit:  int16(*discardLimit),
+		VictoryPoints: *victoryPoints,
+		MaxPlayers:    *numPlayers,
+		Speed:         entities.NormalSpeed,
+	}
+
+	var advanced entities.AdvancedSettings
+	if *balanced {
+		settings.Advanced = true
+		advanced.BalancedDice = true
+	}
+	if *events {
+		settings.Advanced = true
+		advanced.EventCards = true
+		advanced.EventCardEvents = true
+	}
+	if err := advanced.Validate(); err != nil {
+		fail(err.Error())
+	}
+
+	switch *mode {
+	case "base":
+	case "ck":
+		settings.Mode = entities.CitiesAndKnights
+	case "sea":
+		settings.Mode = entities.Seafarers
+	case "fish":
+		settings.Mode = entities.Fishermen
+	case "rivers":
+		settings.Mode = entities.Rivers
+	case "caravans":
+		settings.Mode = entities.Caravans
+	case "barbarians":
+		settings.Mode = entities.BarbarianAttack
+	case "tb":
+		settings.Mode = entities.TradersAndBarbarians
+	default:
+		fail("unknown mode " + *mode)
+	}
+
+	strategies := strings.Split(*bots, ",")
+	for _, s := range strategies {
+		if !game.IsValidBotStrategy(s) {
+			fail("unknown bot strategy " + s + ", available: " + strings.Join(game.GetBotStrategyNames(), ","))
+		}
+	}
+
+	difficulties := strings.Split(*difficulty, ",")
+	for _, d := range difficulties {
+		if !entities.IsValidBotDifficulty(d) {
+			fail("unknown bot difficulty " + d + ", available: " + strings.Join(entities.BotDifficulties, ","))
+		}
+	}
+
+	var defn *entities.MapDefinition
+	if *mapFile != "" {
+		data, err := os.ReadFile(*mapFile)
+		if err != nil {
+			fail(err.Error())
+		}
+		defn = &entities.MapDefinition{}
+		if err := json.Unmarshal(data, defn); err != nil {
+			fail("invalid map: " + err.Error())
+		}
+		if err := maps.ValidateMapDefinition(defn); err != nil {
+			fail("invalid map: " + err.Error())
+		}
+		settings.MapName = defn.Name
+	} else if settings.Mode == entities.Seafarers {
+		defn = maps.GetSeafarersMap()
+		settings.MapName = defn.Name
+	}
+
+	w := os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fail(err.Error())
+		}
+		defer f.Close()
+		w = f
+	}
+
+	store := memstore.NewMemStore()
+	results := make([]*GameResult, 0, *numGames)
+	for i := 0; i < *numGames; i++ {
+		s := settings
+		if *seed != 0 {
+			s.Seed = *seed + int64(i)
+		}
+		if defn != nil {
+			// Map generation shuffles the definition in place
+			s.MapDefn = copyMap(defn)
+		}
+
+		results = append(results, play(store, i, *numPlayers, strategies, difficulties, s, advanced, *maxTicks, *board))
+		fmt.Fprintf(os.Stderr, "\rPlayed %d/%d", i+1, *numGames)
+	}
+	fmt.Fprintln(os.Stderr)
+
+	var err error
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(results)
+	case "csv":
+		err = writeCSV(w, results, *numPlayers)
+	default:
+		fail("unknown format " + *format)
+	}
+	if err != nil {
+		fail(err.Error())
+	}
+}
+
+func play(
+	store *memstore.MemStore,
+	n, numPlayers int,
+	strategies []string,
+	difficulties []string,
+	settings entities.GameSettings,
+	advanced entities.AdvancedSettings,
+	maxTicks int,
+	board bool,
+) *GameResult {
+	id := "sim-" + strconv.Itoa(n)
+	res := &GameResult{
+		Game:         n,
+		Winner:       -1,
+		Strategies:   make([]string, numPlayers),
+		Difficulties: make([]string, numPlayers),
+		VPCurves:     make([][]int, numPlayers),
+	}
+
+	g := &game.Game{Store: store, Headless: true, Settings: settings, AdvancedSettings: advanced}
+	store.Init(id)
+	defer store.TerminateGame(id)
+
+	if _, err := g.Initialize(id, uint16(numPlayers)); err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	res.Seed = g.Settings.Seed
+
+	for i, p := range g.Players {
+		g.SetUsername(p, "Bot"+strconv.Itoa(i)+"*")
+		res.Strategies[i] = strategies[i%len(strategies)]
+		g.SetBotStrategy(p, res.Strategies[i])
+		res.Difficulties[i] = difficulties[i%len(difficulties)]
+		g.SetBotDifficulty(p, res.Difficulties[i])
+	}
+
+	err := g.Simulate(maxTicks, func(turn int) bool {
+		for _, p := range g.Players {
+			res.VPCurves[p.Order] = append(res.VPCurves[p.Order], g.GetVictoryPoints(p, false))
+		}
+		res.Turns = turn
+		return true
+	})
+	if err != nil {
+		res.Error = err.Error()
+	}
+
+	g.Lock()
+	res.Winner = g.GetWinner()
+	copy(res.Dice[:], g.DiceStats.Rolls[1:])
+	if board {
+		fmt.Fprintf(os.Stderr, "\nGame %d, seed %d\n%s", n, res.Seed, render.ASCII(g))
+	}
+	g.Terminate()
+	g.Unlock()
+
+	return res
+}
+
+// Write one row per game, VP curves are space separated
+func writeCSV(w io.Writer, results []*GameResult, numPlayers int) error {
+	cw := csv.NewWriter(w)
+
+	header := []string{"game", "seed", "winner", "turns", "strategies", "difficulties", "error"}
+	for i := 2; i <= 12; i++ {
+		header = append(header, "rolls_"+strconv.Itoa(i))
+	}
+	for i := 0; i < numPlayers; i++ {
+		header = append(header, "vp_"+strconv.Itoa(i))
+	}
+	cw.Write(header)
+
+	for _, r := range results {
+		row := []string{
+			strconv.Itoa(r.Game),
+			strconv.FormatInt(r.Seed, 10),
+			strconv.Itoa(r.Winner),
+			strconv.Itoa(r.Turns),
+			strings.Join(r.Strategies, " "),
+			strings.Join(r.Difficulties, " "),
+			r.Error,
+		}
+		for _, d := range r.Dice {
+			row = append(row, strconv.Itoa(d))
+		}
+		for _, curve := range r.VPCurves {
+			vals := make([]string, len(curve))
+			for i, v := range curve {
+				vals[i] = strconv.Itoa(v)
+			}
+			row = append(row, strings.Join(vals, " "))
+		}
+		cw.Write(row)
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func copyMap(defn *entities.MapDefinition) *entities.MapDefinition {
+	data, _ := json.Marshal(defn)
+	res := &entities.MapDefinition{}
+	json.Unmarshal(data, res)
+	return res
+}
+
+func fail(msg string) {
+	fmt.Fprintln(os.Stderr, msg)
+	os.Exit(1)
+}
@@ -25,6 +25,7 @@ const (
 	MessageTypeSpectatorList      = "spec"
 	MessageTypeError              = "err"
 	MessageTypeEndsess            = "endsess"
+	MessageTypeEventCard          = "ev"
 
 	WsMsgLocationLobby = "l"
 	WsMsgLocationGame  = "g"
@@ -1,5 +1,7 @@
 package entities
 
+import "math/rand"
+
 type (
 	Bank struct {
 		Hand                  *Hand
@@ -8,7 +10,7 @@ type (
 	}
 )
 
-func GetNewBank(gameMode GameMode) (*Bank, error) {
+func GetNewBank(gameMode GameMode, r *rand.Rand) (*Bank, error) {
 	bank := &Bank{DevelopmentCardCursor: 0}
 
 	// Create bank
@@ -21,12 +23,12 @@ func GetNewBank(gameMode GameMode) (*Bank, error) {
 	bank.DevelopmentCardOrder = make(map[CardType][]DevelopmentCardType)
 
 	// Create development card order
-	if gameMode == Base {
-		bank.DevelopmentCardOrder[0] = GenerateDevelopmentCardOrder()
+	if gameMode.UsesBaseCards() {
+		bank.DevelopmentCardOrder[0] = GenerateDevelopmentCardOrder(r)
 	} else if gameMode == CitiesAndKnights {
-		bank.DevelopmentCardOrder[CardTypePaper] = GenerateProgessCardOrder(CardTypePaper)
-		bank.DevelopmentCardOrder[CardTypeCloth] = GenerateProgessCardOrder(CardTypeCloth)
-		bank.DevelopmentCardOrder[CardTypeCoin] = GenerateProgessCardOrder(CardTypeCoin)
+		bank.DevelopmentCardOrder[CardTypePaper] = GenerateProgessCardOrder(CardTypePaper, r)
+		bank.DevelopmentCardOrder[CardTypeCloth] = GenerateProgessCardOrder(CardTypeCloth, r)
+		bank.DevelopmentCardOrder[CardTypeCoin] = GenerateProgessCardOrder(CardTypeCoin, r)
 	}
 
 	return bank, nil
@@ -2,7 +2,6 @@ package entities
 
 import (
 	"math/rand"
-	"time"
 )
 
 type (
@@ -140,31 +139,42 @@ func GetInitialCardQuantity(g GameMode, t CardType) int16 {
 	return 0
 }
 
-func GenerateDevelopmentCardOrder() []DevelopmentCardType {
+func GenerateDevelopmentCardOrder(r *rand.Rand) []DevelopmentCardType {
 	order := make([]DevelopmentCardType, 0)
 	knightQuantity, vpQuantity, roadBuildingQuantity, yearOfPlentyQuantity, monopolyQuantity := GetInitialDevelopmentCardQuantity(true)
-	developmentCards := make(map[DevelopmentCardType]int16)
-	developmentCards[DevelopmentCardKnight] = knightQuantity
-	developmentCards[DevelopmentCardVictoryPoint] = vpQuantity
-	developmentCards[DevelopmentCardRoadBuilding] = roadBuildingQuantity
-	developmentCards[DevelopmentCardYearOfPlenty] = yearOfPlentyQuantity
-	developmentCards[DevelopmentCardMonopoly] = monopolyQuantity
-
-	for dCType, dCQuantity := range developmentCards {
-		for i := int16(0); i < dCQuantity; i++ {
-			order = append(order, dCType)
+
+	// Fixed order before shuffling so the seed alone decides the deck
+	developmentCards := []DevelopmentCardDeck{
+		{Type: DevelopmentCardKnight, Quantity: knightQuantity},
+		{Type: DevelopmentCardVictoryPoint, Quantity: vpQuantity},
+		{Type: DevelopmentCardRoadBuilding, Quantity: roadBuildingQuantity},
+		{Type: DevelopmentCardYearOfPlenty, Quantity: yearOfPlentyQuantity},
+		{Type: DevelopmentCardMonopoly, Quantity: monopolyQuantity},
+	}
+
+	for _, deck := range developmentCards {
+		for i := int16(0); i < deck.Quantity; i++ {
+			order = append(order, deck.Type)
 		}
 	}
 
-	rand.Seed(time.Now().UnixNano())
-	rand.Shuffle(len(order), func(i, j int) {
+	r.Shuffle(len(order), func(i, j int) {
 		order[i], order[j] = order[j], order[i]
 	})
 
 	return order
 }
 
-func GenerateProgessCardOrder(card CardType) []DevelopmentCardType {
+func GenerateProgessCardOrder(card CardType, r *rand.Rand) []DevelopmentCardType {
+	order := GetProgressCards(card)
+	r.Shuffle(len(order), func(i, j int) {
+		order[i], order[j] = order[j], order[i]
+	})
+	return order
+}
+
+// Get the unshuffled progress cards of a deck
+func GetProgressCards(card CardType) []DevelopmentCardType {
 	order := make([]DevelopmentCardType, 0)
 
 	switch card {
@@ -232,10 +242,6 @@ func GenerateProgessCardOrder(card CardType) []DevelopmentCardType {
 		}
 	}
 
-	rand.Seed(time.Now().UnixNano())
-	rand.Shuffle(len(order), func(i, j int) {
-		order[i], order[j] = order[j], order[i]
-	})
 	return order
 }
 
@@ -69,6 +69,7 @@ func (p *Player) GetBuildLocationsKnight(g *Graph, allowUpgrade bool) []*Vertex
 	for k := range vertices {
 		keys = append(keys, k)
 	}
+	SortVertices(keys)
 	return keys
 }
 
@@ -1,5 +1,7 @@
 package entities
 
+import "math/rand"
+
 type (
 	DieRollState struct {
 		RedRoll          int            `msgpack:"r"`
@@ -13,10 +15,93 @@ type (
 	DiceStats struct {
 		Rolls      [12]int `msgpack:"r"`
 		EventRolls [6]int  `msgpack:"e"`
+
+		// Only with balanced dice
+		Deck *DiceDeck `msgpack:"dk,omitempty"`
+	}
+
+	// Deck of the 36 outcomes of two dice, drawn without replacement
+	DiceDeck struct {
+		// Outcomes left in the deck as red*6 + white, with dice from 0
+		Cards []int `msgpack:"c"`
+
+		// Draws since the last shuffle and when to shuffle again
+		Draws          int `msgpack:"d"`
+		ReshuffleAfter int `msgpack:"a"`
+		Reshuffles     int `msgpack:"s"`
 	}
 )
 
+func NewDiceDeck(reshuffleAfter int) *DiceDeck {
+	d := &DiceDeck{ReshuffleAfter: reshuffleAfter}
+	d.shuffle()
+	return d
+}
+
+func (d *DiceDeck) shuffle() {
+	d.Cards = make([]int, 36)
+	for i := range d.Cards {
+		d.Cards[i] = i
+	}
+	d.Draws = 0
+}
+
+// Pick an outcome from the deck without removing it
+// The weight of every outcome is scaled by the weight of its sum
+// Returns false if no outcome has any weight left
+func (d *DiceDeck) Draw(r *rand.Rand, weight func(sum int) float64) (int, int, bool) {
+	weights := make([]float64, len(d.Cards))
+	total := 0.0
+	for i, c := range d.Cards {
+		weights[i] = weight(c/6 + c%6 + 2)
+		total += weights[i]
+	}
+	if total <= 0 {
+		return 0, 0, false
+	}
+
+	x := r.Float64() * total
+	for i, c := range d.Cards {
+		x -= weights[i]
+		if x < 0 || i == len(d.Cards)-1 {
+			return c/6 + 1, c%6 + 1, true
+		}
+	}
+	return 0, 0, false
+}
+
+// Take a rolled outcome out of the deck, reshuffling when enough were drawn
+// Outcomes that are not in the deck any more still count as a draw
+func (d *DiceDeck) Remove(red, white int) {
+	c := (red-1)*6 + (white - 1)
+	for i, o := range d.Cards {
+		if o == c {
+			d.Cards = append(d.Cards[:i], d.Cards[i+1:]...)
+			break
+		}
+	}
+
+	d.Draws++
+	if d.Draws >= d.ReshuffleAfter || len(d.Cards) == 0 {
+		d.shuffle()
+		d.Reshuffles++
+	}
+}
+
+// Number of outcomes left in the deck for each sum, index 0 is a sum of 2
+func (d *DiceDeck) GetSums() [11]int {
+	var sums [11]int
+	for _, c := range d.Cards {
+		sums[c/6+c%6]++
+	}
+	return sums
+}
+
 type GameOverMessage struct {
 	Players []*PlayerState `msgpack:"p"`
 	Winner  uint16         `msgpack:"w"`
+	Report  *GameReport    `msgpack:"r"`
+
+	// Hidden until the game is over
+	Seed int64 `msgpack:"s"`
 }
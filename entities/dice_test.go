@@ -0,0 +1,135 @@
+package entities
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewDiceDeckSums(t *testing.T) {
+	d := NewDiceDeck(DefaultBalancedDiceReshuffle)
+	want := [11]int{1, 2, 3, 4, 5, 6, 5, 4, 3, 2, 1}
+	if d.GetSums() != want {
+		t.Fatalf("sums of a new deck are %v, want %v", d.GetSums(), want)
+	}
+}
+
+func TestDiceDeckRemove(t *testing.T) {
+	tests := []struct {
+		name       string
+		reshuffle  int
+		rolls      [][2]int
+		cards      int
+		reshuffles int
+	}{
+		{"one roll", 31, [][2]int{{3, 4}}, 35, 0},
+		{"same roll twice", 31, [][2]int{{3, 4}, {3, 4}}, 35, 0},
+		{"mirrored rolls", 31, [][2]int{{3, 4}, {4, 3}}, 34, 0},
+		{"reshuffle after two", 2, [][2]int{{1, 1}, {2, 2}}, 36, 1},
+		{"after the reshuffle", 2, [][2]int{{1, 1}, {2, 2}, {6, 6}}, 35, 1},
+	}
+
+	for _, tt := range tests {
+		d := NewDiceDeck(tt.reshuffle)
+		for _, r := range tt.rolls {
+			d.Remove(r[0], r[1])
+		}
+		if len(d.Cards) != tt.cards || d.Reshuffles != tt.reshuffles {
+			t.Errorf("%s: %d cards and %d reshuffles, want %d and %d", tt.name, len(d.Cards), d.Reshuffles, tt.cards, tt.reshuffles)
+		}
+	}
+}
+
+func TestDiceDeckDraw(t *testing.T) {
+	uniform := func(sum int) float64 { return 1 }
+	noSeven := func(sum int) float64 {
+		if sum == 7 {
+			return 0
+		}
+		return 1
+	}
+	onlySeven := func(sum int) float64 {
+		if sum == 7 {
+			return 1
+		}
+		return 0
+	}
+
+	tests := []struct {
+		name   string
+		weight func(sum int) float64
+		ok     bool
+		seven  bool
+	}{
+		{"uniform", uniform, true, true},
+		{"no seven", noSeven, true, false},
+		{"only seven", onlySeven, true, true},
+		{"no weight", func(sum int) float64 { return 0 }, false, false},
+	}
+
+	for _, tt := range tests {
+		r := NewRand(7)
+		d := NewDiceDeck(36)
+		sevens := 0
+		for i := 0; i < 36; i++ {
+			red, white, ok := d.Draw(r, tt.weight)
+			if ok != tt.ok {
+				t.Fatalf("%s: draw is %v", tt.name, ok)
+			}
+			if !ok {
+				break
+			}
+			if red < 1 || red > 6 || white < 1 || white > 6 {
+				t.Fatalf("%s: drew %d and %d", tt.name, red, white)
+			}
+			if red+white == 7 {
+				sevens++
+			}
+			if tt.weight(red+white) == 0 {
+				t.Fatalf("%s: drew a sum of %d with no weight", tt.name, red+white)
+			}
+		}
+		if (sevens > 0) != tt.seven {
+			t.Errorf("%s: drew %d sevens", tt.name, sevens)
+		}
+	}
+}
+
+// Drawing and removing empties the deck with every outcome once
+func TestDiceDeckDrawsEveryOutcome(t *testing.T) {
+	r := NewRand(11)
+	d := NewDiceDeck(36)
+	var sums [11]int
+	for i := 0; i < 36; i++ {
+		red, white, ok := d.Draw(r, func(sum int) float64 { return 1 })
+		if !ok {
+			t.Fatal("deck is empty")
+		}
+		sums[red+white-2]++
+		d.Remove(red, white)
+	}
+
+	if want := NewDiceDeck(36).GetSums(); sums != want {
+		t.Fatalf("drew sums %v, want %v", sums, want)
+	}
+	if d.Reshuffles != 1 || len(d.Cards) != 36 {
+		t.Fatalf("deck was not shuffled again, %d cards", len(d.Cards))
+	}
+}
+
+func TestDiceDeckSameSeedSameDraws(t *testing.T) {
+	draw := func() [][2]int {
+		r := NewRand(3)
+		d := NewDiceDeck(DefaultBalancedDiceReshuffle)
+		rolls := make([][2]int, 0)
+		for i := 0; i < 100; i++ {
+			red, white, _ := d.Draw(r, func(sum int) float64 { return 1 })
+			d.Remove(red, white)
+			rolls = append(rolls, [2]int{red, white})
+		}
+		return rolls
+	}
+
+	if !reflect.DeepEqual(draw(), draw()) {
+		t.Fatal("same seed drew different dice")
+	}
+}
@@ -0,0 +1,78 @@
+package entities
+
+import "math/rand"
+
+type EventType int
+
+const (
+	EventNone EventType = 0
+
+	// Every player returns one wool to the bank
+	EventPlague EventType = 1
+
+	// Every player gives a random card to the next player
+	EventGoodNeighbour EventType = 2
+
+	// Everyone trades with the bank at 3:1 until the end of the turn
+	EventTradeBonus EventType = 3
+
+	// Cards left in the deck when it is shuffled again
+	EventDeckReserve = 5
+)
+
+type (
+	// One of the 36 outcomes of two dice, drawn instead of rolling
+	EventCard struct {
+		RedRoll   int       `msgpack:"r"`
+		WhiteRoll int       `msgpack:"w"`
+		Event     EventType `msgpack:"e"`
+	}
+
+	// Sent to everyone when a card is drawn
+	EventCardInfo struct {
+		Card EventCard `msgpack:"c"`
+
+		// Cards to draw before the deck is shuffled
+		Left int `msgpack:"l"`
+	}
+)
+
+// Events printed on the cards, keyed by red*6 + white with dice from 0
+// Sevens never carry an event
+var EventCardEvents = map[int]EventType{
+	0*6 + 2: EventPlague,
+	5*6 + 3: EventPlague,
+	1*6 + 2: EventGoodNeighbour,
+	4*6 + 3: EventGoodNeighbour,
+	2*6 + 2: EventTradeBonus,
+	3*6 + 3: EventTradeBonus,
+}
+
+func NewEventCardOrder(r *rand.Rand) []int {
+	return r.Perm(36)
+}
+
+// Card at a position of the deck order, with the events only if enabled
+func GetEventCard(c int, events bool) EventCard {
+	card := EventCard{RedRoll: c/6 + 1, WhiteRoll: c%6 + 1}
+	if events {
+		card.Event = EventCardEvents[c]
+	}
+	return card
+}
+
+func (c EventCard) GetRoll() int {
+	return c.RedRoll + c.WhiteRoll
+}
+
+func (t EventType) String() string {
+	switch t {
+	case EventPlague:
+		return "Plague"
+	case EventGoodNeighbour:
+		return "Good neighbour"
+	case EventTradeBonus:
+		return "Trade bonus"
+	}
+	return ""
+}
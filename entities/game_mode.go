@@ -3,14 +3,23 @@ package entities
 type GameMode uint16
 
 const (
-	Base               GameMode = 1
-	CitiesAndKnights   GameMode = 2
-	IQBaseResource     int16    = 24
-	IQBaseKnight       int16    = 14
-	IQBaseVP           int16    = 5
-	IQBaseRoadBuilding int16    = 2
-	IQBaseYearOfPlenty int16    = 2
-	IQBaseMonopoly     int16    = 2
+	Base             GameMode = 1
+	CitiesAndKnights GameMode = 2
+	Seafarers        GameMode = 3
+
+	// Traders & Barbarians scenarios
+	Fishermen            GameMode = 4
+	Rivers               GameMode = 5
+	Caravans             GameMode = 6
+	BarbarianAttack      GameMode = 7
+	TradersAndBarbarians GameMode = 8
+
+	IQBaseResource     int16 = 24
+	IQBaseKnight       int16 = 14
+	IQBaseVP           int16 = 5
+	IQBaseRoadBuilding int16 = 2
+	IQBaseYearOfPlenty int16 = 2
+	IQBaseMonopoly     int16 = 2
 
 	IQCKCommodity int16 = 12
 
@@ -19,6 +28,33 @@ const (
 	FastSpeed   string = "fast"
 )
 
+func (m GameMode) IsValid() bool {
+	return m >= Base && m <= TradersAndBarbarians
+}
+
+// Seafarers and the traders scenarios are played with the cards
+// of the base game
+func (m GameMode) UsesBaseCards() bool {
+	return m.IsValid() && m != CitiesAndKnights
+}
+
+// The full traders & barbarians game plays all the scenarios together
+func (m GameMode) HasFish() bool {
+	return m == Fishermen || m == TradersAndBarbarians
+}
+
+func (m GameMode) HasRivers() bool {
+	return m == Rivers || m == TradersAndBarbarians
+}
+
+func (m GameMode) HasCaravans() bool {
+	return m == Caravans || m == TradersAndBarbarians
+}
+
+func (m GameMode) HasBarbarianAttack() bool {
+	return m == BarbarianAttack || m == TradersAndBarbarians
+}
+
 type GameSettings struct {
 	Mode          GameMode
 	Private       bool
@@ -30,11 +66,61 @@ type GameSettings struct {
 	EnableKarma   bool
 	Speed         string
 	Advanced      bool
+	Seed          int64
 	MapDefn       *MapDefinition `json:"-" msgpack:"-"`
+
+	// Balance score of a map with fixed numbers, zero otherwise
+	MapBalance int
+
+	// Version of the saved map that is played, zero for generated maps
+	MapVersion int
+
+	// Made by the matchmaking queue, which also decides if finishing
+	// changes the ratings of the players. Only the server sets them.
+	Matched bool
+	Ranked  bool
 }
 
 type AdvancedSettings struct {
 	RerollOn7 bool
+
+	// The robber stays put until every player has this many victory points
+	FriendlyRobber int
+
+	// Sevens are rolled again until everyone has had a turn
+	NoSevensFirstRound bool
+
+	// Zero keeps the standard value
+	LongestRoadLength int
+	LongestRoadVP     int
+	LargestArmySize   int
+	LargestArmyVP     int
+
+	Costs BuildingCosts
+
+	// Zero for no limit
+	MaxDevelopmentCardsPerTurn int
+
+	// Resource cards of everyone are public
+	OpenHands bool
+
+	// Rolls are drawn from a deck of the 36 outcomes of two dice
+	BalancedDice bool
+
+	// Draws before the deck is shuffled again, zero for the default
+	BalancedDiceReshuffle int
+
+	// Make the sum rolled last less likely to come again
+	BalancedDiceDampenRepeats bool
+
+	// Make a 7 less likely for the player in the lead
+	BalancedDiceLeaderPenalty bool
+
+	// Rolls are drawn from a deck of cards instead of the dice
+	EventCards bool
+
+	// Some of the cards also carry an event for everyone
+	EventCardEvents bool
 }
 
 var SpeedMultiplier = map[string]float32{
@@ -2,6 +2,7 @@ package entities
 
 import (
 	"errors"
+	"sort"
 )
 
 const (
@@ -17,6 +18,9 @@ const (
 	TileTypeNone   TileType = 8
 	TileTypeRandom TileType = 9
 	TileTypeGold   TileType = 21
+
+	// Takes the place of the desert in the fishermen scenario
+	TileTypeLake TileType = 22
 )
 
 type (
@@ -47,6 +51,7 @@ type (
 		Placement     EdgeBuildable  `msgpack:"-"`
 		AdjacentTiles []*Tile        `msgpack:"-"`
 		IsBeach       bool           `msgpack:"b"`
+		IsRiver       bool           `msgpack:"r,omitempty"`
 		Orientation   uint16         `msgpack:"o"`
 	}
 
@@ -71,9 +76,90 @@ type (
 		PortCoordinates []EdgeCoordinate `json:"port_coordinates"`
 		Map             [][]int          `json:"map"`
 		RandomTiles     []TileType       `json:"tiles"`
+
+		// Victory points for each island settled after the start (Seafarers)
+		IslandBonus int `json:"island_bonus,omitempty"`
+
+		// Edges with a river, only used by the rivers scenario
+		RiverCoordinates []EdgeCoordinate `json:"river_coordinates,omitempty"`
+
+		// Number of each tile of the map instead of random numbers, zero for none
+		TileNumbers [][]int `json:"tile_numbers,omitempty"`
 	}
 )
 
+// Order coordinates by row then column
+func (c Coordinate) Less(o Coordinate) bool {
+	if c.Y != o.Y {
+		return c.Y < o.Y
+	}
+	return c.X < o.X
+}
+
+func (c EdgeCoordinate) Less(o EdgeCoordinate) bool {
+	if c.C1 != o.C1 {
+		return c.C1.Less(o.C1)
+	}
+	return c.C2.Less(o.C2)
+}
+
+// Sort slices built from map iteration so random picks from them
+// depend only on the game seed
+func SortVertices(vertices []*Vertex) {
+	sort.Slice(vertices, func(i, j int) bool { return vertices[i].C.Less(vertices[j].C) })
+}
+
+func SortEdges(edges []*Edge) {
+	sort.Slice(edges, func(i, j int) bool { return edges[i].C.Less(edges[j].C) })
+}
+
+func SortTiles(tiles []*Tile) {
+	sort.Slice(tiles, func(i, j int) bool { return tiles[i].Center.Less(tiles[j].Center) })
+}
+
+// Land tiles can be settled, sea tiles are only sailed by ships
+func (tile *Tile) IsLand() bool {
+	return tile.Type != TileTypeSea && tile.Type != TileTypeNone
+}
+
+func (v *Vertex) HasLand() bool {
+	for _, t := range v.AdjacentTiles {
+		if t.IsLand() {
+			return true
+		}
+	}
+	return false
+}
+
+// Roads are built along land
+func (e *Edge) HasLand() bool {
+	for _, t := range e.AdjacentTiles {
+		if t.IsLand() {
+			return true
+		}
+	}
+	return false
+}
+
+// Ships are built along sea, including the coast
+func (e *Edge) HasSea() bool {
+	for _, t := range e.AdjacentTiles {
+		if t.Type == TileTypeSea {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Edge) HasTile(tile *Tile) bool {
+	for _, t := range e.AdjacentTiles {
+		if t == tile {
+			return true
+		}
+	}
+	return false
+}
+
 func (tile *Tile) GetVertexCoordinates() []Coordinate {
 	c := tile.Center
 	coords := make([]Coordinate, 6)
@@ -218,5 +304,6 @@ func (g *Graph) GetBeachEdges() []*Edge {
 		}
 	}
 
+	SortEdges(edges)
 	return edges
 }
@@ -3,6 +3,7 @@ package entities
 import (
 	"errors"
 	"math/rand"
+	"sort"
 )
 
 type (
@@ -71,14 +72,35 @@ func (h *Hand) GetCardCount() int16 {
 	return count
 }
 
-func (h *Hand) ChooseRandomCardType() *CardType {
+// Get the card types in the hand in a stable order
+// Use instead of ranging over the deck maps where the order matters
+func (h *Hand) GetCardTypes() []CardType {
+	types := make([]CardType, 0, len(h.CardDeckMap))
+	for t := range h.CardDeckMap {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+func (h *Hand) GetDevelopmentCardTypes() []DevelopmentCardType {
+	types := make([]DevelopmentCardType, 0, len(h.DevelopmentCardDeckMap))
+	for t := range h.DevelopmentCardDeckMap {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+func (h *Hand) ChooseRandomCardType(r *rand.Rand) *CardType {
 	cardCount := h.GetCardCount()
 	if cardCount <= 0 {
 		return nil
 	}
 
-	randomIndex := rand.Intn(int(cardCount))
-	for _, deck := range h.GetCardDeckMap() {
+	randomIndex := r.Intn(int(cardCount))
+	for _, t := range h.GetCardTypes() {
+		deck := h.CardDeckMap[t]
 		randomIndex -= int(deck.Quantity)
 		if randomIndex < 0 {
 			return &deck.Type
@@ -95,14 +117,15 @@ func (h *Hand) GetDevelopmentCardCount() int16 {
 	return count
 }
 
-func (h *Hand) ChooseRandomDevCardType() *DevelopmentCardType {
+func (h *Hand) ChooseRandomDevCardType(r *rand.Rand) *DevelopmentCardType {
 	cardCount := h.GetDevelopmentCardCount()
 	if cardCount <= 0 {
 		return nil
 	}
 
-	randomIndex := rand.Intn(int(cardCount))
-	for _, deck := range h.DevelopmentCardDeckMap {
+	randomIndex := r.Intn(int(cardCount))
+	for _, t := range h.GetDevelopmentCardTypes() {
+		deck := h.DevelopmentCardDeckMap[t]
 		randomIndex -= int(deck.Quantity)
 		if randomIndex < 0 {
 			return &deck.Type
@@ -136,22 +159,22 @@ func GetNewHand(g GameMode, isBank bool) (*Hand, error) {
 	addCard(hand, CardTypeWheat)
 	addCard(hand, CardTypeOre)
 
-	switch g {
-	case Base:
+	switch {
+	case g.UsesBaseCards():
 		knightQuantity, vpQuantity, roadBuildingQuantity, yearOfPlentyQuantity, monopolyQuantity := GetInitialDevelopmentCardQuantity(isBank)
 		hand.addDevelopmentCardDeck(DevelopmentCardDeck{Type: DevelopmentCardKnight, Quantity: knightQuantity})
 		hand.addDevelopmentCardDeck(DevelopmentCardDeck{Type: DevelopmentCardVictoryPoint, Quantity: vpQuantity})
 		hand.addDevelopmentCardDeck(DevelopmentCardDeck{Type: DevelopmentCardRoadBuilding, Quantity: roadBuildingQuantity})
 		hand.addDevelopmentCardDeck(DevelopmentCardDeck{Type: DevelopmentCardYearOfPlenty, Quantity: yearOfPlentyQuantity})
 		hand.addDevelopmentCardDeck(DevelopmentCardDeck{Type: DevelopmentCardMonopoly, Quantity: monopolyQuantity})
-	case CitiesAndKnights:
+	case g == CitiesAndKnights:
 		addCard(hand, CardTypePaper)
 		addCard(hand, CardTypeCloth)
 		addCard(hand, CardTypeCoin)
 
-		cards := GenerateProgessCardOrder(CardTypePaper)
-		cards = append(cards, GenerateProgessCardOrder(CardTypeCloth)...)
-		cards = append(cards, GenerateProgessCardOrder(CardTypeCoin)...)
+		cards := GetProgressCards(CardTypePaper)
+		cards = append(cards, GetProgressCards(CardTypeCloth)...)
+		cards = append(cards, GetProgressCards(CardTypeCoin)...)
 		for _, ct := range cards {
 			if hand.GetDevelopmentCardDeck(ct) == nil {
 				hand.addDevelopmentCardDeck(DevelopmentCardDeck{Type: ct, Quantity: 0})
@@ -0,0 +1,40 @@
+package entities
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// A map with the name of the one being created is already saved
+	ErrMapExists = errors.New("map already exists")
+
+	// The map being changed is not saved or not of the user changing it
+	ErrMapNotFound = errors.New("map not found")
+)
+
+// A saved map with its owner and the version of its definition
+type MapInfo struct {
+	Name     string `json:"name"`
+	Creator  string `json:"creator,omitempty"`
+	Official bool   `json:"official"`
+
+	// Only the creator can see and play a private map
+	Private bool `json:"private"`
+
+	// Goes up by one each time the map is saved, zero for maps saved before versions
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	Defn *MapDefinition `json:"map,omitempty"`
+}
+
+func (m *MapInfo) CanView(userId string) bool {
+	return !m.Private || m.Creator == userId
+}
+
+// Official maps cannot be changed by anyone
+func (m *MapInfo) CanEdit(userId string) bool {
+	return !m.Official && m.Creator != "" && m.Creator == userId
+}
@@ -33,12 +33,27 @@ type (
 
 		LongestRoad int `msgpack:"-"`
 
+		// Variants of the game, nil for the standard rules
+		Rules                  *AdvancedSettings `msgpack:"-"`
+		DevelopmentCardsBought int               `msgpack:"-"`
+
+		// Traders & Barbarians
+		Fish               int `msgpack:"-"`
+		Gold               int `msgpack:"-"`
+		GoldTrades         int `msgpack:"-"`
+		CapturedBarbarians int `msgpack:"-"`
+
 		TimeLeft     int  `msgpack:"-"`
 		SpecialBuild bool `msgpack:"-"`
 
-		IsBot           int32 `msgpack:"-"`
-		InactiveSeconds int32 `msgpack:"-"`
-		IsSpectator     bool  `msgpack:"-"`
+		IsBot           int32  `msgpack:"-"`
+		BotStrategy     string `msgpack:"-"`
+		BotDifficulty   string `msgpack:"-"`
+		InactiveSeconds int32  `msgpack:"-"`
+		IsSpectator     bool   `msgpack:"-"`
+
+		// A bot played for the player after it stopped playing
+		Abandoned bool `msgpack:"-"`
 
 		Embargos []bool `msgpack:"-"`
 	}
@@ -47,12 +62,19 @@ type (
 		CurrentPlayerOrder uint16         `msgpack:"c"`
 		NeedDice           bool           `msgpack:"d"`
 		Robber             *Robber        `msgpack:"r"`
+		Pirate             *Robber        `msgpack:"pi,omitempty"`
 		PlayerStates       []*PlayerState `msgpack:"p"`
 
 		BarbarianPosition int       `msgpack:"bp"`
 		BarbarianStrength int       `msgpack:"bs"`
 		BarbarianKnights  int       `msgpack:"bk"`
 		Merchant          *Merchant `msgpack:"tm"`
+
+		Camels     []EdgeCoordinate  `msgpack:"cm,omitempty"`
+		Barbarians []*TileBarbarians `msgpack:"ba,omitempty"`
+
+		// Event of the card drawn this turn
+		Event EventType `msgpack:"ev,omitempty"`
 	}
 
 	PlayerState struct {
@@ -72,11 +94,19 @@ type (
 		Improvements        map[int]int `msgpack:"ci"`
 		DiscardLimit        int16       `msgpack:"l"`
 		IsBot               bool        `msgpack:"b,omitempty"`
+		BotDifficulty       string      `msgpack:"bd,omitempty"`
 
 		HasLongestRoad bool `msgpack:"lr,omitempty"`
 		HasLargestArmy bool `msgpack:"la,omitempty"`
 
 		DevCardVp *int16 `msgpack:"dv,omitempty"`
+
+		Fish               int `msgpack:"fi,omitempty"`
+		Gold               int `msgpack:"go,omitempty"`
+		CapturedBarbarians int `msgpack:"ba,omitempty"`
+
+		// Only with open hands
+		Cards map[CardType]int `msgpack:"oh,omitempty"`
 	}
 
 	LobbyPlayerState struct {
@@ -86,6 +116,12 @@ type (
 		Ready         bool   `msgpack:"r"`
 		GamesStarted  int32  `msgpack:"s"`
 		GamesFinished int32  `msgpack:"f"`
+		BotStrategy   string `msgpack:"bs,omitempty"`
+		BotDifficulty string `msgpack:"bd,omitempty"`
+		External      bool   `msgpack:"x,omitempty"`
+
+		// Rating for the mode and number of players of the lobby
+		Rating int `msgpack:"rt,omitempty"`
 	}
 
 	AllowedActionsMap struct {
@@ -105,6 +141,12 @@ type (
 		ImproveCloth   bool `msgpack:"il,omitempty"`
 		ImproveCoin    bool `msgpack:"ic,omitempty"`
 
+		BuildShip bool `msgpack:"sh,omitempty"`
+		MoveShip  bool `msgpack:"ms,omitempty"`
+
+		UseFish   bool `msgpack:"fi,omitempty"`
+		SpendGold bool `msgpack:"go,omitempty"`
+
 		SpecialBuild bool `msgpack:"sb,omitempty"`
 	}
 
@@ -132,6 +174,23 @@ type (
 	}
 )
 
+const (
+	BotDifficultyEasy   string = "easy"
+	BotDifficultyMedium string = "medium"
+	BotDifficultyHard   string = "hard"
+)
+
+var BotDifficulties = []string{BotDifficultyEasy, BotDifficultyMedium, BotDifficultyHard}
+
+func IsValidBotDifficulty(d string) bool {
+	for _, v := range BotDifficulties {
+		if v == d {
+			return true
+		}
+	}
+	return false
+}
+
 func GetColor(order uint16) string {
 	colors := [6]string{"#ff0000", "#00ff00", "#0000ff", "#ffff00", "#fc41ec", "#26eded"}
 	if order >= 6 {
@@ -167,6 +226,15 @@ func NewPlayer(g GameMode, id, username string, order uint16) (*Player, error) {
 		player.BuildablesLeft[BTWall] = 3
 	}
 
+	if g == Seafarers {
+		player.BuildablesLeft[BTShip] = 15
+	}
+
+	// Basic warriors only, they are never upgraded
+	if g.HasBarbarianAttack() {
+		player.BuildablesLeft[BTKnight1] = 6
+	}
+
 	player.Improvements = make(map[int]int)
 	player.Improvements[int(CardTypePaper)] = 0
 	player.Improvements[int(CardTypeCloth)] = 0
@@ -177,7 +245,7 @@ func NewPlayer(g GameMode, id, username string, order uint16) (*Player, error) {
 	return player, nil
 }
 
-func GetNewPlayers(g GameMode, numPlayers uint16) ([]*Player, error) {
+func GetNewPlayers(g GameMode, numPlayers uint16, r *rand.Rand) ([]*Player, error) {
 	players := make([]*Player, numPlayers)
 
 	for i := uint16(0); i < numPlayers; i++ {
@@ -185,6 +253,7 @@ func GetNewPlayers(g GameMode, numPlayers uint16) ([]*Player, error) {
 		if err != nil {
 			return nil, err
 		}
+		player.RandInt = r.Intn(9950) + 25
 
 		for j := uint16(0); j < numPlayers; j++ {
 			player.Embargos = append(player.Embargos, false)
@@ -235,6 +304,8 @@ func (p *Player) BuildAtEdge(e *Edge, t BuildableType) error {
 	switch t {
 	case BTRoad:
 		e.Placement = NewRoad(e)
+	case BTShip:
+		e.Placement = NewShip(e)
 	default:
 		return errors.New("invalid build type")
 	}
@@ -289,46 +360,22 @@ func (p *Player) CanBuild(t BuildableType) error {
 		return errors.New("not enough pieces left to build")
 	}
 
-	if t == BTSettlement {
-		if !p.CurrentHand.HasResources(1, 1, 1, 1, 0) {
-			return errors.New("not enough resources")
-		}
-		return nil
-	}
-
-	if t == BTCity {
-		if !p.CurrentHand.HasResources(0, 0, 0, 2, 3) {
-			return errors.New("not enough resources")
-		}
-		return nil
-	}
-
-	if t == BTRoad {
-		if !p.CurrentHand.HasResources(1, 1, 0, 0, 0) {
-			return errors.New("not enough resources")
-		}
-		return nil
+	cost := p.Rules.GetCost(t)
+	if cost == nil {
+		return errors.New("unknown type of buildable")
 	}
 
-	if t == BTKnight1 || t == BTKnight2 || t == BTKnight3 {
-		if !p.CurrentHand.HasResources(0, 0, 1, 0, 1) {
-			return errors.New("not enough resources")
-		}
-		return nil
+	if !p.CurrentHand.HasResources(costArgs(cost)) {
+		return errors.New("not enough resources")
 	}
-
-	if t == BTWall {
-		if !p.CurrentHand.HasResources(0, 2, 0, 0, 0) {
-			return errors.New("not enough resources")
-		}
-		return nil
-	}
-
-	return errors.New("unknown type of buildable")
+	return nil
 }
 
 func (p *Player) CanBuyDevelopmentCard() bool {
-	return p.CurrentHand.HasResources(0, 0, 1, 1, 1)
+	if max := p.Rules.GetMaxDevelopmentCardsPerTurn(); max > 0 && p.DevelopmentCardsBought >= max {
+		return false
+	}
+	return p.CurrentHand.HasResources(costArgs(p.Rules.GetDevelopmentCardCost()))
 }
 
 func (p *Player) HasInactiveKnight() bool {
@@ -0,0 +1,77 @@
+package entities
+
+import "time"
+
+type (
+	// Saved state of a game of a user, with the report once it is over
+	UserGame struct {
+		State     []byte
+		Report    []byte
+		CreatedAt time.Time
+	}
+
+	// Summary of the games of a user
+	UserProfile struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+
+		// Only finished games count
+		Played        int     `json:"played"`
+		Won           int     `json:"won"`
+		WinRate       float64 `json:"winRate"`
+		VictoryPoints float64 `json:"averageVp"`
+
+		Modes    []*ProfileRecord  `json:"modes"`
+		Maps     []*ProfileRecord  `json:"maps"`
+		Openings []*ProfileOpening `json:"openings"`
+		Ratings  []*Rating         `json:"ratings"`
+
+		// Newest first, including games that are not over
+		Games []*ProfileGame `json:"recentGames"`
+	}
+
+	// Finished games of a user in one mode or on one map
+	ProfileRecord struct {
+		Mode          GameMode `json:"mode,omitempty"`
+		Map           string   `json:"map,omitempty"`
+		Played        int      `json:"played"`
+		Won           int      `json:"won"`
+		WinRate       float64  `json:"winRate"`
+		VictoryPoints float64  `json:"averageVp"`
+	}
+
+	// Vertex of a map the user built on while placing the first pieces
+	ProfileOpening struct {
+		Map    string `json:"map"`
+		X      int    `json:"x"`
+		Y      int    `json:"y"`
+		Played int    `json:"played"`
+		Won    int    `json:"won"`
+	}
+
+	ProfileGame struct {
+		ID            string    `json:"id"`
+		Mode          GameMode  `json:"mode"`
+		Map           string    `json:"map"`
+		Players       int       `json:"players"`
+		Ranked        bool      `json:"ranked"`
+		Finished      bool      `json:"finished"`
+		Won           bool      `json:"won"`
+		Place         int       `json:"place,omitempty"`
+		VictoryPoints int       `json:"vp"`
+		RatingChange  *float64  `json:"ratingChange,omitempty"`
+		CreatedAt     time.Time `json:"createdAt"`
+	}
+
+	LeaderboardEntry struct {
+		Rank      int      `json:"rank"`
+		UserID    string   `json:"id"`
+		Username  string   `json:"username"`
+		Mode      GameMode `json:"mode"`
+		Players   int      `json:"players"`
+		Rating    int      `json:"rating"`
+		Deviation int      `json:"deviation"`
+		Games     int      `json:"games"`
+		Wins      int      `json:"wins"`
+	}
+)
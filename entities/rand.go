@@ -0,0 +1,45 @@
+package entities
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+type (
+	// Source shared by the game loop, the ticker and bots
+	lockedSource struct {
+		mutex sync.Mutex
+		src   rand.Source64
+	}
+)
+
+func (s *lockedSource) Int63() int64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Uint64() uint64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.src.Uint64()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.src.Seed(seed)
+}
+
+// Get the random number generator for a game
+// The same seed always gives the same sequence
+func NewRand(seed int64) *rand.Rand {
+	return rand.New(&lockedSource{src: rand.NewSource(seed).(rand.Source64)})
+}
+
+// Get a fresh seed for a game that was not given one
+// Kept below 2^53 so that it survives a round trip through JSON
+func NewSeed() int64 {
+	return time.Now().UnixNano()%(1<<53) + 1
+}
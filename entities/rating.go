@@ -0,0 +1,177 @@
+package entities
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Glicko ratings, see http://www.glicko.net/glicko/glicko.pdf
+const (
+	RatingInitial          = 1500.0
+	RatingDeviationInitial = 350.0
+	RatingDeviationMinimum = 30.0
+
+	// Deviation gained each day without a game, about a year
+	// takes a settled rating back to the initial deviation
+	RatingDeviationDaily = 18.0
+
+	// Changes kept with each rating
+	RatingHistoryLength = 100
+)
+
+var ratingQ = math.Ln10 / 400
+
+type (
+	// Skill of a user in one game mode with one number of players
+	Rating struct {
+		UserID    string    `json:"id" msgpack:"id"`
+		Mode      GameMode  `json:"mode" msgpack:"m"`
+		Players   int       `json:"players" msgpack:"p"`
+		Rating    float64   `json:"rating" msgpack:"r"`
+		Deviation float64   `json:"deviation" msgpack:"d"`
+		Games     int       `json:"games" msgpack:"g"`
+		Wins      int       `json:"wins" msgpack:"w"`
+		UpdatedAt time.Time `json:"updatedAt" msgpack:"t"`
+
+		// Latest changes, oldest first
+		History []*RatingChange `json:"history" msgpack:"h"`
+	}
+
+	RatingChange struct {
+		GameID string    `json:"game" msgpack:"id"`
+		Rating float64   `json:"rating" msgpack:"r"`
+		Change float64   `json:"change" msgpack:"c"`
+		Place  int       `json:"place" msgpack:"pl"`
+		At     time.Time `json:"at" msgpack:"t"`
+	}
+)
+
+func NewRating(userId string, mode GameMode, players int) *Rating {
+	return &Rating{
+		UserID:    userId,
+		Mode:      mode,
+		Players:   players,
+		Rating:    RatingInitial,
+		Deviation: RatingDeviationInitial,
+		History:   make([]*RatingChange, 0),
+	}
+}
+
+// Rating of the user for the mode and number of players in a list,
+// a new rating if there is none
+func FindRating(ratings []*Rating, userId string, mode GameMode, players int) *Rating {
+	for _, r := range ratings {
+		if r.UserID == userId && r.Mode == mode && r.Players == players {
+			return r
+		}
+	}
+	return NewRating(userId, mode, players)
+}
+
+// Deviation at the given time, it grows while the user does not play
+func (r *Rating) DeviationAt(at time.Time) float64 {
+	if r.UpdatedAt.IsZero() || !at.After(r.UpdatedAt) {
+		return r.Deviation
+	}
+
+	days := at.Sub(r.UpdatedAt).Hours() / 24
+	return math.Min(math.Sqrt(r.Deviation*r.Deviation+RatingDeviationDaily*RatingDeviationDaily*days), RatingDeviationInitial)
+}
+
+// Update the ratings of the players of a finished game
+// Every player is compared to each other by their score, higher is better.
+// All the results count as one rating period, against the ratings before the game.
+func UpdateRatings(ratings []*Rating, scores []int, gameId string, at time.Time) {
+	n := len(ratings)
+	deviations := make([]float64, n)
+	for i, r := range ratings {
+		deviations[i] = r.DeviationAt(at)
+	}
+
+	newRatings := make([]float64, n)
+	newDeviations := make([]float64, n)
+	for i, r := range ratings {
+		sum, variance := 0.0, 0.0
+		for j, o := range ratings {
+			if i == j {
+				continue
+			}
+
+			g := 1 / math.Sqrt(1+3*ratingQ*ratingQ*deviations[j]*deviations[j]/(math.Pi*math.Pi))
+			e := 1 / (1 + math.Pow(10, -g*(r.Rating-o.Rating)/400))
+
+			s := 0.5
+			if scores[i] > scores[j] {
+				s = 1
+			} else if scores[i] < scores[j] {
+				s = 0
+			}
+
+			sum += g * (s - e)
+			variance += g * g * e * (1 - e)
+		}
+
+		precision := 1/(deviations[i]*deviations[i]) + ratingQ*ratingQ*variance
+		newRatings[i] = r.Rating + ratingQ/precision*sum
+		newDeviations[i] = math.Max(math.Sqrt(1/precision), RatingDeviationMinimum)
+	}
+
+	for i, r := range ratings {
+		place := 1
+		for j := range ratings {
+			if scores[j] > scores[i] {
+				place++
+			}
+		}
+
+		r.History = append(r.History, &RatingChange{
+			GameID: gameId,
+			Rating: newRatings[i],
+			Change: newRatings[i] - r.Rating,
+			Place:  place,
+			At:     at,
+		})
+		if len(r.History) > RatingHistoryLength {
+			r.History = r.History[len(r.History)-RatingHistoryLength:]
+		}
+
+		r.Rating = newRatings[i]
+		r.Deviation = newDeviations[i]
+		r.Games++
+		if place == 1 {
+			r.Wins++
+		}
+		r.UpdatedAt = at
+	}
+}
+
+// Best rating of each user among the ratings of a mode and number of
+// players, highest first. Zero matches any mode or number of players.
+func BestRatings(ratings []*Rating, mode GameMode, players int, limit int) []*Rating {
+	best := make(map[string]*Rating)
+	for _, r := range ratings {
+		if (mode != 0 && r.Mode != mode) || (players != 0 && r.Players != players) {
+			continue
+		}
+		if b, ok := best[r.UserID]; !ok || r.Rating > b.Rating {
+			best[r.UserID] = r
+		}
+	}
+
+	sorted := make([]*Rating, 0, len(best))
+	for _, r := range best {
+		sorted = append(sorted, r)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Rating != sorted[j].Rating {
+			return sorted[i].Rating > sorted[j].Rating
+		}
+		return sorted[i].UserID < sorted[j].UserID
+	})
+
+	if limit > 0 && len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+	return sorted
+}
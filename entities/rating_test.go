@@ -0,0 +1,183 @@
+package entities
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestUpdateRatings(t *testing.T) {
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		ratings []float64
+		scores  []int
+
+		// Sign of the change of each player
+		changes []int
+		places  []int
+	}{
+		{"winner of two gains", []float64{1500, 1500}, []int{10, 8}, []int{1, -1}, []int{1, 2}},
+		{"tie changes nothing", []float64{1500, 1500}, []int{9, 9}, []int{0, 0}, []int{1, 1}},
+		{"upset moves both", []float64{1300, 1700}, []int{10, 7}, []int{1, -1}, []int{1, 2}},
+		{"four players by score", []float64{1500, 1500, 1500, 1500}, []int{10, 8, 6, 4}, []int{1, 1, -1, -1}, []int{1, 2, 3, 4}},
+		{"shared second place", []float64{1500, 1500, 1500}, []int{11, 7, 7}, []int{1, -1, -1}, []int{1, 2, 2}},
+	}
+
+	for _, tt := range tests {
+		ratings := make([]*Rating, len(tt.ratings))
+		for i, r := range tt.ratings {
+			ratings[i] = NewRating(string(rune('a'+i)), Base, len(tt.ratings))
+			ratings[i].Rating = r
+		}
+
+		UpdateRatings(ratings, tt.scores, "game", at)
+
+		for i, r := range ratings {
+			change := r.Rating - tt.ratings[i]
+			sign := 0
+			if change > 0.001 {
+				sign = 1
+			} else if change < -0.001 {
+				sign = -1
+			}
+			if sign != tt.changes[i] {
+				t.Errorf("%s: player %d changed by %f", tt.name, i, change)
+			}
+
+			if r.Deviation >= RatingDeviationInitial || r.Deviation < RatingDeviationMinimum {
+				t.Errorf("%s: player %d has deviation %f", tt.name, i, r.Deviation)
+			}
+			if r.Games != 1 || len(r.History) != 1 || !r.UpdatedAt.Equal(at) {
+				t.Errorf("%s: player %d game was not counted", tt.name, i)
+			}
+
+			h := r.History[0]
+			if h.GameID != "game" || h.Place != tt.places[i] || math.Abs(h.Change-change) > 1e-9 || h.Rating != r.Rating {
+				t.Errorf("%s: player %d has history %+v", tt.name, i, h)
+			}
+			if (r.Wins == 1) != (tt.places[i] == 1) {
+				t.Errorf("%s: player %d has %d wins in place %d", tt.name, i, r.Wins, tt.places[i])
+			}
+		}
+	}
+}
+
+func TestUpdateRatingsUpsetCountsMore(t *testing.T) {
+	at := time.Now()
+	play := func(winner, loser float64) float64 {
+		ratings := []*Rating{NewRating("a", Base, 2), NewRating("b", Base, 2)}
+		ratings[0].Rating, ratings[1].Rating = winner, loser
+		UpdateRatings(ratings, []int{10, 5}, "game", at)
+		return ratings[0].Rating - winner
+	}
+
+	if favourite, underdog := play(1700, 1300), play(1300, 1700); favourite >= underdog {
+		t.Fatalf("favourite gained %f and underdog %f", favourite, underdog)
+	}
+}
+
+func TestRatingDeviationAt(t *testing.T) {
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := &Rating{Deviation: 50, UpdatedAt: at}
+
+	tests := []struct {
+		days float64
+		want float64
+	}{
+		{-1, 50},
+		{0, 50},
+		{1, math.Sqrt(50*50 + RatingDeviationDaily*RatingDeviationDaily)},
+		{100, math.Sqrt(50*50 + RatingDeviationDaily*RatingDeviationDaily*100)},
+		{10000, RatingDeviationInitial},
+	}
+
+	for _, tt := range tests {
+		got := r.DeviationAt(at.Add(time.Duration(tt.days * 24 * float64(time.Hour))))
+		if math.Abs(got-tt.want) > 1e-6 {
+			t.Errorf("after %v days: got %f, want %f", tt.days, got, tt.want)
+		}
+	}
+
+	if got := NewRating("a", Base, 4).DeviationAt(at); got != RatingDeviationInitial {
+		t.Errorf("new rating has deviation %f", got)
+	}
+}
+
+func TestRatingHistoryLength(t *testing.T) {
+	ratings := []*Rating{NewRating("a", Base, 2), NewRating("b", Base, 2)}
+	at := time.Now()
+	for i := 0; i < RatingHistoryLength+10; i++ {
+		UpdateRatings(ratings, []int{10, 5}, "game", at)
+	}
+
+	if len(ratings[0].History) != RatingHistoryLength {
+		t.Fatalf("history has %d changes", len(ratings[0].History))
+	}
+	if ratings[0].Games != RatingHistoryLength+10 || ratings[0].Wins != RatingHistoryLength+10 {
+		t.Fatalf("counted %d games and %d wins", ratings[0].Games, ratings[0].Wins)
+	}
+}
+
+func TestFindRating(t *testing.T) {
+	saved := []*Rating{
+		{UserID: "a", Mode: Base, Players: 4, Rating: 1600},
+		{UserID: "a", Mode: Seafarers, Players: 4, Rating: 1700},
+	}
+
+	tests := []struct {
+		mode    GameMode
+		players int
+		want    float64
+	}{
+		{Base, 4, 1600},
+		{Seafarers, 4, 1700},
+		{Base, 3, RatingInitial},
+		{CitiesAndKnights, 4, RatingInitial},
+	}
+
+	for _, tt := range tests {
+		r := FindRating(saved, "a", tt.mode, tt.players)
+		if r.Rating != tt.want || r.Mode != tt.mode || r.Players != tt.players || r.UserID != "a" {
+			t.Errorf("mode %d with %d players: got %+v", tt.mode, tt.players, r)
+		}
+	}
+}
+
+func TestBestRatings(t *testing.T) {
+	ratings := []*Rating{
+		{UserID: "a", Mode: Base, Players: 4, Rating: 1600},
+		{UserID: "a", Mode: Seafarers, Players: 4, Rating: 1800},
+		{UserID: "b", Mode: Base, Players: 4, Rating: 1700},
+		{UserID: "b", Mode: Base, Players: 3, Rating: 1550},
+		{UserID: "c", Mode: Base, Players: 3, Rating: 1500},
+	}
+
+	tests := []struct {
+		mode    GameMode
+		players int
+		limit   int
+		want    []string
+	}{
+		{0, 0, 0, []string{"a", "b", "c"}},
+		{Base, 0, 0, []string{"b", "a", "c"}},
+		{Base, 3, 0, []string{"b", "c"}},
+		{Seafarers, 4, 0, []string{"a"}},
+		{CitiesAndKnights, 0, 0, []string{}},
+		{0, 0, 2, []string{"a", "b"}},
+	}
+
+	for _, tt := range tests {
+		best := BestRatings(ratings, tt.mode, tt.players, tt.limit)
+		if len(best) != len(tt.want) {
+			t.Errorf("mode %d players %d: got %d ratings, want %d", tt.mode, tt.players, len(best), len(tt.want))
+			continue
+		}
+		for i, r := range best {
+			if r.UserID != tt.want[i] {
+				t.Errorf("mode %d players %d: rank %d is %s, want %s", tt.mode, tt.players, i+1, r.UserID, tt.want[i])
+			}
+		}
+	}
+}
@@ -1,9 +1,5 @@
 package entities
 
-import (
-	"math"
-)
-
 type Road struct {
 	Owner    *Player       `msgpack:"p"`
 	Location *Edge         `msgpack:"e"`
@@ -52,7 +48,7 @@ func (p *Player) GetBuildLocationsRoad(g *Graph, init bool) []*Edge {
 		}
 
 		for _, e := range g.GetAdjacentVertexEdges(v) {
-			if e.Placement == nil {
+			if e.Placement == nil && e.HasLand() {
 				edges[e] = true
 			}
 		}
@@ -62,7 +58,7 @@ func (p *Player) GetBuildLocationsRoad(g *Graph, init bool) []*Edge {
 		v, _ := g.GetVertex(*c)
 		if v != nil && (v.Placement == nil || v.Placement.GetOwner() == p) {
 			for _, adjE := range g.GetAdjacentVertexEdges(v) {
-				if adjE.Placement == nil {
+				if adjE.Placement == nil && adjE.HasLand() {
 					edges[adjE] = true
 				}
 			}
@@ -71,6 +67,11 @@ func (p *Player) GetBuildLocationsRoad(g *Graph, init bool) []*Edge {
 
 	if !init {
 		for _, ep := range p.EdgePlacements {
+			// Roads do not continue from ships
+			if ep.GetType() != BTRoad {
+				continue
+			}
+
 			e := ep.GetLocation()
 
 			addVertex(&e.C.C1)
@@ -82,6 +83,7 @@ func (p *Player) GetBuildLocationsRoad(g *Graph, init bool) []*Edge {
 	for k := range edges {
 		keys = append(keys, k)
 	}
+	SortEdges(keys)
 	return keys
 }
 
@@ -97,87 +99,63 @@ func (r *Road) GetVertices(g *Graph) (v1, v2 *Vertex) {
 	return v1, v2
 }
 
-func (r *Road) GetAdjacentRoads(g *Graph, v *Vertex) []*Road {
-	adjRoads := make(map[*Road]bool)
-	v1, v2 := r.GetVertices(g)
-
-	if v1 != v && v2 != v {
-		return make([]*Road, 0)
-	}
-
-	adjRoadsForVertex := func(v *Vertex) {
-		adjEdges := g.GetAdjacentVertexEdges(v)
-
-		for _, adj := range adjEdges {
-			if adj.Placement != nil && adj.Placement.GetType() == BTRoad && adj.Placement.GetOwner() == r.GetOwner() && adj.Placement != r {
-				adjRoads[adj.Placement.(*Road)] = true
-			}
-		}
-	}
-
-	if v.Placement == nil || v.Placement.GetOwner() == r.GetOwner() {
-		adjRoadsForVertex(v)
-	}
-
-	keys := make([]*Road, 0, len(adjRoads))
-	for k := range adjRoads {
-		keys = append(keys, k)
-	}
-	return keys
-}
-
+// Longest trade route of roads and ships that are not interrupted by
+// buildings of other players. Roads and ships only connect at a
+// settlement or city of the player.
 func (p *Player) GetLongestRoad(g *Graph) int {
 	if len(p.EdgePlacements) == 0 {
 		return 0
 	}
 
-	longest := 0
+	otherVertex := func(e *Edge, v *Vertex) *Vertex {
+		c := e.C.C1
+		if c == v.C {
+			c = e.C.C2
+		}
+		o, _ := g.GetVertex(c)
+		return o
+	}
 
-	var dfs func(r *Road, v *Vertex, start bool, visited map[EdgeBuildable]bool) int
-	dfs = func(r *Road, v *Vertex, start bool, visited map[EdgeBuildable]bool) int {
-		if !start && visited[r] {
+	// Longest path continuing from ep through v
+	var dfs func(ep EdgeBuildable, v *Vertex, visited map[EdgeBuildable]bool) int
+	dfs = func(ep EdgeBuildable, v *Vertex, visited map[EdgeBuildable]bool) int {
+		if v == nil || (v.Placement != nil && v.Placement.GetOwner() != p) {
 			return 0
 		}
 
-		visited[r] = true
+		longest := 0
+		for _, adj := range g.GetAdjacentVertexEdges(v) {
+			next := adj.Placement
+			if next == nil || next.GetOwner() != p || visited[next] {
+				continue
+			}
+
+			if next.GetType() != ep.GetType() && !p.HasBuildingAt(v) {
+				continue
+			}
 
-		var currentLength int
-		if !start {
-			currentLength = 1
-		} else {
-			currentLength = 0
-		}
-		longestSubPath := 0
-
-		for _, adjRoad := range r.GetAdjacentRoads(g, v) {
-			if !visited[adjRoad] {
-				v1, v2 := adjRoad.GetVertices(g)
-				var adjV *Vertex
-				if v1 == v {
-					adjV = v2
-				} else {
-					adjV = v1
-				}
+			visited[next] = true
+			length := 1 + dfs(next, otherVertex(adj, v), visited)
+			visited[next] = false
 
-				currentLongestSubPath := dfs(adjRoad, adjV, false, visited)
-				longestSubPath = int(math.Max(float64(longestSubPath), float64(currentLongestSubPath)))
+			if length > longest {
+				longest = length
 			}
 		}
 
-		currentLength += longestSubPath
-
-		return currentLength
+		return longest
 	}
 
+	longest := 0
 	for _, ep := range p.EdgePlacements {
-		visited := make(map[EdgeBuildable]bool)
-		v1, v2 := ep.(*Road).GetVertices(g)
-		longestPath1 := dfs(ep.(*Road), v1, true, visited)
-		longestPath2 := dfs(ep.(*Road), v2, true, visited)
-
-		currentLength := int(math.Max(float64(longestPath1), float64(longestPath2))) + 1
-		if currentLength > longest {
-			longest = currentLength
+		e := ep.GetLocation()
+		for _, c := range []Coordinate{e.C.C1, e.C.C2} {
+			v, _ := g.GetVertex(c)
+			visited := map[EdgeBuildable]bool{ep: true}
+			length := 1 + dfs(ep, v, visited)
+			if length > longest {
+				longest = length
+			}
 		}
 	}
 
@@ -0,0 +1,189 @@
+package entities
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+)
+
+type (
+	// Resources needed to build, in the order wood, brick, wool, wheat and ore
+	// An empty cost keeps the standard cost
+	BuildingCosts struct {
+		Road            []int
+		Settlement      []int
+		City            []int
+		Ship            []int
+		DevelopmentCard []int
+	}
+
+	// Bounds of a numeric variant, zero always keeps the standard rule
+	advancedLimit struct {
+		name string
+		get  func(s *AdvancedSettings) int
+		max  int
+	}
+)
+
+const (
+	DefaultLongestRoadLength = 5
+	DefaultLongestRoadVP     = 2
+	DefaultLargestArmySize   = 3
+	DefaultLargestArmyVP     = 2
+
+	// Shuffle with five cards left in the deck
+	DefaultBalancedDiceReshuffle = 31
+)
+
+var DefaultCosts = map[BuildableType][]int{
+	BTSettlement: {1, 1, 1, 1, 0},
+	BTCity:       {0, 0, 0, 2, 3},
+	BTRoad:       {1, 1, 0, 0, 0},
+	BTShip:       {1, 0, 1, 0, 0},
+	BTKnight1:    {0, 0, 1, 0, 1},
+	BTKnight2:    {0, 0, 1, 0, 1},
+	BTKnight3:    {0, 0, 1, 0, 1},
+	BTWall:       {0, 2, 0, 0, 0},
+}
+
+var DefaultDevelopmentCardCost = []int{0, 0, 1, 1, 1}
+
+var advancedLimits = []advancedLimit{
+	{"friendly robber", func(s *AdvancedSettings) int { return s.FriendlyRobber }, 10},
+	{"longest road length", func(s *AdvancedSettings) int { return s.LongestRoadLength }, 15},
+	{"longest road points", func(s *AdvancedSettings) int { return s.LongestRoadVP }, 5},
+	{"largest army size", func(s *AdvancedSettings) int { return s.LargestArmySize }, 14},
+	{"largest army points", func(s *AdvancedSettings) int { return s.LargestArmyVP }, 5},
+	{"development cards per turn", func(s *AdvancedSettings) int { return s.MaxDevelopmentCardsPerTurn }, 10},
+	{"dice draws before shuffling", func(s *AdvancedSettings) int { return s.BalancedDiceReshuffle }, 36},
+}
+
+// Check the variants chosen by the host
+func (s *AdvancedSettings) Validate() error {
+	for _, l := range advancedLimits {
+		if v := l.get(s); v < 0 || v > l.max {
+			return errors.New(l.name + " must be between 0 and " + strconv.Itoa(l.max))
+		}
+	}
+
+	if s.BalancedDice && s.EventCards {
+		return errors.New("balanced dice cannot be used with event cards")
+	}
+
+	for _, cost := range [][]int{s.Costs.Road, s.Costs.Settlement, s.Costs.City, s.Costs.Ship, s.Costs.DevelopmentCard} {
+		if len(cost) == 0 {
+			continue
+		}
+
+		if len(cost) != 5 {
+			return errors.New("costs need a quantity for each resource")
+		}
+
+		sum := 0
+		for _, q := range cost {
+			if q < 0 || q > 5 {
+				return errors.New("costs must be between 0 and 5 of each resource")
+			}
+			sum += q
+		}
+		if sum == 0 {
+			return errors.New("nothing can be free")
+		}
+	}
+
+	return nil
+}
+
+// Settings are nil when the advanced settings are disabled
+// so these always fall back to the standard rules
+
+// No variant changes the standard rules
+func (s *AdvancedSettings) IsDefault() bool {
+	if s == nil {
+		return true
+	}
+
+	c := s.Costs
+	if len(c.Road) > 0 || len(c.Settlement) > 0 || len(c.City) > 0 || len(c.Ship) > 0 || len(c.DevelopmentCard) > 0 {
+		return false
+	}
+
+	d := *s
+	d.Costs = BuildingCosts{}
+	return reflect.DeepEqual(d, AdvancedSettings{})
+}
+
+func (s *AdvancedSettings) GetCost(t BuildableType) []int {
+	if s != nil {
+		var cost []int
+		switch t {
+		case BTRoad:
+			cost = s.Costs.Road
+		case BTSettlement:
+			cost = s.Costs.Settlement
+		case BTCity:
+			cost = s.Costs.City
+		case BTShip:
+			cost = s.Costs.Ship
+		}
+		if len(cost) == 5 {
+			return cost
+		}
+	}
+	return DefaultCosts[t]
+}
+
+func (s *AdvancedSettings) GetDevelopmentCardCost() []int {
+	if s != nil && len(s.Costs.DevelopmentCard) == 5 {
+		return s.Costs.DevelopmentCard
+	}
+	return DefaultDevelopmentCardCost
+}
+
+func (s *AdvancedSettings) GetLongestRoadLength() int {
+	if s == nil || s.LongestRoadLength == 0 {
+		return DefaultLongestRoadLength
+	}
+	return s.LongestRoadLength
+}
+
+func (s *AdvancedSettings) GetLongestRoadVP() int {
+	if s == nil || s.LongestRoadVP == 0 {
+		return DefaultLongestRoadVP
+	}
+	return s.LongestRoadVP
+}
+
+func (s *AdvancedSettings) GetLargestArmySize() int {
+	if s == nil || s.LargestArmySize == 0 {
+		return DefaultLargestArmySize
+	}
+	return s.LargestArmySize
+}
+
+func (s *AdvancedSettings) GetLargestArmyVP() int {
+	if s == nil || s.LargestArmyVP == 0 {
+		return DefaultLargestArmyVP
+	}
+	return s.LargestArmyVP
+}
+
+// Zero if the number of development cards bought in a turn is unlimited
+func (s *AdvancedSettings) GetMaxDevelopmentCardsPerTurn() int {
+	if s == nil {
+		return 0
+	}
+	return s.MaxDevelopmentCardsPerTurn
+}
+
+func (s *AdvancedSettings) GetBalancedDiceReshuffle() int {
+	if s == nil || s.BalancedDiceReshuffle == 0 {
+		return DefaultBalancedDiceReshuffle
+	}
+	return s.BalancedDiceReshuffle
+}
+
+// Cards needed as arguments of Hand.HasResources
+func costArgs(cost []int) (int, int, int, int, int) {
+	return cost[0], cost[1], cost[2], cost[3], cost[4]
+}
@@ -65,7 +65,7 @@ func (p *Player) GetBuildLocationsSettlement(g *Graph, init bool, allowFog bool)
 				continue
 			}
 
-			if !hasEmptyAdjacentVertices(v) {
+			if !hasEmptyAdjacentVertices(v) || !v.HasLand() {
 				continue
 			}
 
@@ -74,7 +74,7 @@ func (p *Player) GetBuildLocationsSettlement(g *Graph, init bool, allowFog bool)
 	} else {
 		checkVertex := func(c *Coordinate) {
 			v, _ := g.GetVertex(*c)
-			if v != nil && v.Placement == nil && hasEmptyAdjacentVertices(v) && !hasAdjacentFogTile(v) {
+			if v != nil && v.Placement == nil && hasEmptyAdjacentVertices(v) && !hasAdjacentFogTile(v) && v.HasLand() {
 				vertices[v] = true
 			}
 		}
@@ -91,5 +91,6 @@ func (p *Player) GetBuildLocationsSettlement(g *Graph, init bool, allowFog bool)
 	for k := range vertices {
 		keys = append(keys, k)
 	}
+	SortVertices(keys)
 	return keys
 }
@@ -0,0 +1,124 @@
+package entities
+
+type Ship struct {
+	Owner    *Player       `msgpack:"p"`
+	Location *Edge         `msgpack:"e"`
+	Type     BuildableType `msgpack:"t"`
+
+	// Built this turn, cannot be moved yet
+	New bool `msgpack:"-"`
+}
+
+func NewShip(e *Edge) *Ship {
+	s := &Ship{Location: e}
+	s.Type = BTShip
+	s.New = true
+	return s
+}
+
+func (s *Ship) GetType() BuildableType {
+	return BTShip
+}
+
+func (s *Ship) SetOwner(p *Player) {
+	s.Owner = p
+}
+
+func (s *Ship) GetOwner() *Player {
+	return s.Owner
+}
+
+func (s *Ship) GetLocation() *Edge {
+	return s.Location
+}
+
+// Check if the player has a settlement or city at the vertex
+func (p *Player) HasBuildingAt(v *Vertex) bool {
+	if v.Placement == nil || v.Placement.GetOwner() != p {
+		return false
+	}
+	return v.Placement.GetType() == BTSettlement || v.Placement.GetType() == BTCity
+}
+
+// Ships start at a settlement or city of the player and continue
+// from the ends of their ships, but never next to the pirate
+func (p *Player) GetBuildLocationsShip(g *Graph, pirate *Tile) []*Edge {
+	edges := make(map[*Edge]bool)
+
+	addVertex := func(v *Vertex) {
+		if v == nil || (v.Placement != nil && v.Placement.GetOwner() != p) {
+			return
+		}
+
+		for _, e := range g.GetAdjacentVertexEdges(v) {
+			if e.Placement == nil && e.HasSea() && (pirate == nil || !e.HasTile(pirate)) {
+				edges[e] = true
+			}
+		}
+	}
+
+	for _, vp := range p.VertexPlacements {
+		if p.HasBuildingAt(vp.GetLocation()) {
+			addVertex(vp.GetLocation())
+		}
+	}
+
+	for _, ep := range p.EdgePlacements {
+		if ep.GetType() != BTShip {
+			continue
+		}
+
+		e := ep.GetLocation()
+		v1, _ := g.GetVertex(e.C.C1)
+		v2, _ := g.GetVertex(e.C.C2)
+		addVertex(v1)
+		addVertex(v2)
+	}
+
+	keys := make([]*Edge, 0, len(edges))
+	for k := range edges {
+		keys = append(keys, k)
+	}
+	SortEdges(keys)
+	return keys
+}
+
+// Ships at the open end of a shipping route, which are not
+// connected to another ship or a building of the player there
+func (p *Player) GetMovableShips(g *Graph, pirate *Tile) []*Edge {
+	edges := make([]*Edge, 0)
+
+	isOpen := func(s *Ship, c Coordinate) bool {
+		v, _ := g.GetVertex(c)
+		if v == nil || p.HasBuildingAt(v) {
+			return false
+		}
+
+		for _, e := range g.GetAdjacentVertexEdges(v) {
+			if e != s.Location && e.Placement != nil &&
+				e.Placement.GetType() == BTShip && e.Placement.GetOwner() == p {
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, ep := range p.EdgePlacements {
+		s, ok := ep.(*Ship)
+		if !ok || s.New {
+			continue
+		}
+
+		e := s.GetLocation()
+		if pirate != nil && e.HasTile(pirate) {
+			continue
+		}
+
+		if isOpen(s, e.C.C1) || isOpen(s, e.C.C2) {
+			edges = append(edges, e)
+		}
+	}
+
+	SortEdges(edges)
+	return edges
+}
@@ -0,0 +1,174 @@
+package entities
+
+import "sort"
+
+type (
+	// Counters kept while a game is played, for the report at the end
+	GameStats struct {
+		Turns   int
+		Players []*PlayerStats
+		Tiles   map[Coordinate]*TileStats
+	}
+
+	PlayerStats struct {
+		Order    uint16 `msgpack:"o"`
+		Username string `msgpack:"u"`
+
+		// Cards from the dice by card type, and gold to choose
+		Produced [9]int `msgpack:"pr"`
+		Gold     int    `msgpack:"au"`
+
+		// Cards not produced because of the robber
+		Blocked int `msgpack:"bl"`
+
+		// Cards the dice should have produced with the board at each roll
+		Expected float64 `msgpack:"ex"`
+
+		// Cards lost to the robber, taken with it and discarded
+		Stolen    int `msgpack:"st"`
+		Robbed    int `msgpack:"rb"`
+		Discarded int `msgpack:"di"`
+
+		// Number of trades and cards exchanged with each player by order
+		Trades      []int `msgpack:"tr"`
+		TradeVolume []int `msgpack:"tv"`
+		CardsGiven  int   `msgpack:"cg"`
+		CardsTaken  int   `msgpack:"ct"`
+
+		BankTrades int `msgpack:"bt"`
+		BankGiven  int `msgpack:"bg"`
+		BankTaken  int `msgpack:"bk"`
+
+		// Development and progress cards played
+		CardsPlayed map[DevelopmentCardType]int `msgpack:"cp"`
+
+		// Victory points at the end of each turn
+		VictoryPoints []int `msgpack:"vp"`
+
+		// Vertices built on while placing the first pieces
+		Openings []Coordinate `msgpack:"op"`
+	}
+
+	TileStats struct {
+		Tile     Coordinate `msgpack:"c"`
+		Type     TileType   `msgpack:"t"`
+		Number   uint16     `msgpack:"n"`
+		Produced int        `msgpack:"p"`
+		Blocked  int        `msgpack:"b"`
+	}
+
+	// Sent when the game is over and kept by the store
+	GameReport struct {
+		ID     string `msgpack:"id"`
+		Winner int    `msgpack:"w"`
+
+		// Turns played, including the one the game ended in
+		Turns   int            `msgpack:"n"`
+		Players []*PlayerStats `msgpack:"p"`
+		Tiles   []*TileStats   `msgpack:"t"`
+		Dice    *DiceStats     `msgpack:"d"`
+	}
+)
+
+func NewGameStats(numPlayers int) *GameStats {
+	s := &GameStats{
+		Players: make([]*PlayerStats, numPlayers),
+		Tiles:   make(map[Coordinate]*TileStats),
+	}
+	for i := range s.Players {
+		s.Players[i] = &PlayerStats{
+			Order:         uint16(i),
+			Trades:        make([]int, numPlayers),
+			TradeVolume:   make([]int, numPlayers),
+			CardsPlayed:   make(map[DevelopmentCardType]int),
+			VictoryPoints: make([]int, 0),
+		}
+	}
+	return s
+}
+
+func (s *GameStats) getTile(t *Tile) *TileStats {
+	ts := s.Tiles[t.Center]
+	if ts == nil {
+		ts = &TileStats{Tile: t.Center, Type: t.Type, Number: t.Number}
+		s.Tiles[t.Center] = ts
+	}
+	return ts
+}
+
+func (s *GameStats) AddProduction(t *Tile, order uint16, cardType CardType, quantity int) {
+	s.Players[order].Produced[cardType] += quantity
+	s.getTile(t).Produced += quantity
+}
+
+func (s *GameStats) AddGold(t *Tile, order uint16, quantity int) {
+	s.Players[order].Gold += quantity
+	s.getTile(t).Produced += quantity
+}
+
+func (s *GameStats) AddBlocked(t *Tile, order uint16, quantity int) {
+	s.Players[order].Blocked += quantity
+	s.getTile(t).Blocked += quantity
+}
+
+func (s *GameStats) AddExpected(order uint16, quantity float64) {
+	s.Players[order].Expected += quantity
+}
+
+func (s *GameStats) AddSteal(victim uint16, stealer uint16) {
+	s.Players[victim].Stolen++
+	s.Players[stealer].Robbed++
+}
+
+func (s *GameStats) AddDiscard(order uint16, quantity int) {
+	s.Players[order].Discarded += quantity
+}
+
+// Trade of the given player, the partner is -1 for the bank
+func (s *GameStats) AddTrade(order uint16, partner int, given int, taken int) {
+	p := s.Players[order]
+	if partner < 0 {
+		p.BankTrades++
+		p.BankGiven += given
+		p.BankTaken += taken
+		return
+	}
+
+	o := s.Players[partner]
+	p.Trades[partner]++
+	o.Trades[order]++
+	p.TradeVolume[partner] += given + taken
+	o.TradeVolume[order] += given + taken
+	p.CardsGiven += given
+	p.CardsTaken += taken
+	o.CardsGiven += taken
+	o.CardsTaken += given
+}
+
+func (s *GameStats) AddCardPlayed(order uint16, card DevelopmentCardType) {
+	s.Players[order].CardsPlayed[card]++
+}
+
+func (s *GameStats) AddOpening(order uint16, vertex Coordinate) {
+	s.Players[order].Openings = append(s.Players[order].Openings, vertex)
+}
+
+// Victory points of each player by order at the end of a turn
+func (s *GameStats) AddTurn(vps []int) {
+	s.Turns++
+	for i, vp := range vps {
+		if i < len(s.Players) {
+			s.Players[i].VictoryPoints = append(s.Players[i].VictoryPoints, vp)
+		}
+	}
+}
+
+// Tiles that produced or were blocked, in row then column order
+func (s *GameStats) GetTiles() []*TileStats {
+	tiles := make([]*TileStats, 0, len(s.Tiles))
+	for _, t := range s.Tiles {
+		tiles = append(tiles, t)
+	}
+	sort.Slice(tiles, func(i, j int) bool { return tiles[i].Tile.Less(tiles[j].Tile) })
+	return tiles
+}
@@ -0,0 +1,43 @@
+package entities
+
+type (
+	// Barbarians that landed on a tile
+	TileBarbarians struct {
+		Tile  Coordinate `msgpack:"t"`
+		Count int        `msgpack:"n"`
+	}
+)
+
+const (
+	// Number of fish spent for each use
+	FishRobber   = 2
+	FishSteal    = 3
+	FishResource = 4
+	FishRoad     = 5
+	FishCard     = 7
+
+	// Gold for one resource from the bank, twice per turn
+	GoldPerResource   = 2
+	GoldTradesPerTurn = 2
+
+	MaxCamels = 22
+
+	// A tile with this many barbarians is conquered and produces nothing
+	BarbariansToConquer = 3
+)
+
+var FishAmounts = []int{FishRobber, FishSteal, FishResource, FishRoad, FishCard}
+
+func IsFishAmount(amount int) bool {
+	for _, a := range FishAmounts {
+		if a == amount {
+			return true
+		}
+	}
+	return false
+}
+
+// Rolls that make the lake produce fish
+func IsLakeRoll(roll int) bool {
+	return roll == 2 || roll == 3 || roll == 11 || roll == 12
+}
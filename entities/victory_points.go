@@ -11,5 +11,10 @@ type (
 		Metropolis              map[CardType]*Player
 		PrinterHolder           *Player
 		ConstitutionHolder      *Player
+
+		// Seafarers, islands of each player by order
+		// true if settled after the start and worth the bonus
+		IslandBonus    int
+		SettledIslands []map[int]bool
 	}
 )
@@ -3,7 +3,6 @@ package game
 import (
 	"errors"
 	"imperials/entities"
-	"math/rand"
 	"reflect"
 	"strconv"
 
@@ -64,10 +63,7 @@ func (g *Game) BuildSettlement(player *entities.Player, coordinates entities.Coo
 	}
 
 	if !init {
-		g.MoveCards(int(player.Order), -1, entities.CardTypeWood, 1, false, false)
-		g.MoveCards(int(player.Order), -1, entities.CardTypeBrick, 1, false, false)
-		g.MoveCards(int(player.Order), -1, entities.CardTypeWool, 1, false, false)
-		g.MoveCards(int(player.Order), -1, entities.CardTypeWheat, 1, false, false)
+		g.payCost(player, player.Rules.GetCost(entities.BTSettlement), false)
 	}
 
 	vertex, _ := g.Graph.GetVertex(coordinates)
@@ -77,6 +73,12 @@ func (g *Game) BuildSettlement(player *entities.Player, coordinates entities.Coo
 	}
 
 	player.BuildablesLeft[entities.BTSettlement]--
+	g.settleIsland(player, vertex)
+
+	// Settling by a river is worth gold
+	if g.isRiverVertex(vertex) {
+		g.giveGold(player, 1)
+	}
 
 	g.SetExtraVictoryPoints()
 	g.SendPlayerSecret(player)
@@ -88,6 +90,13 @@ func (g *Game) BuildSettlement(player *entities.Player, coordinates entities.Coo
 	})
 
 	g.j.WVertexBuild(vertex, false)
+	if init {
+		g.countOpening(player, vertex)
+	}
+
+	if g.Mode.HasCaravans() && !init && !g.j.playing {
+		g.goAsync(func() { g.PlaceCamelInteractive(player) })
+	}
 
 	g.CheckForVictory()
 
@@ -131,8 +140,7 @@ func (g *Game) BuildCity(player *entities.Player, coordinates entities.Coordinat
 	}
 
 	if !init {
-		g.MoveCards(int(player.Order), -1, entities.CardTypeOre, 3, false, false)
-		g.MoveCards(int(player.Order), -1, entities.CardTypeWheat, 2, false, false)
+		g.payCost(player, player.Rules.GetCost(entities.BTCity), false)
 	}
 
 	vertex, _ := g.Graph.GetVertex(coordinates)
@@ -158,15 +166,21 @@ func (g *Game) BuildCity(player *entities.Player, coordinates entities.Coordinat
 	})
 
 	g.j.WVertexBuild(vertex, false)
+	if init {
+		g.countOpening(player, vertex)
+	}
+
+	if g.Mode.HasCaravans() && !init && !g.j.playing {
+		g.goAsync(func() { g.PlaceCamelInteractive(player) })
+	}
 
 	g.CheckForVictory()
 
 	return nil
 }
 
-func (g *Game) BuildRoad(player *entities.Player, c entities.EdgeCoordinate) error {
-	init := g.IsInitPhase()
-
+// Check everything about a road but its cost
+func (g *Game) canPlaceRoad(player *entities.Player, c entities.EdgeCoordinate, init bool) error {
 	if err := g.EnsureCurrentPlayer(player); err != nil && !init {
 		return err
 	}
@@ -175,26 +189,27 @@ func (g *Game) BuildRoad(player *entities.Player, c entities.EdgeCoordinate) err
 		return err
 	}
 
-	if err := player.CanBuild(entities.BTRoad); !init && err != nil {
-		return err
+	if player.BuildablesLeft[entities.BTRoad] <= 0 && !init {
+		return errors.New("not enough pieces left to build")
 	}
 
-	canBuild := func() bool {
-		for _, e := range player.GetBuildLocationsRoad(g.Graph, init) {
-			if (e.C.C1 == c.C1 && e.C.C2 == c.C2) || (e.C.C1 == c.C2 && e.C.C2 == c.C1) {
-				return true
-			}
+	for _, e := range player.GetBuildLocationsRoad(g.Graph, init) {
+		if (e.C.C1 == c.C1 && e.C.C2 == c.C2) || (e.C.C1 == c.C2 && e.C.C2 == c.C1) {
+			return nil
 		}
-		return false
 	}
+	return errors.New("cannot build road here")
+}
 
-	if !canBuild() {
-		return errors.New("cannot build road here")
+func (g *Game) BuildRoad(player *entities.Player, c entities.EdgeCoordinate) error {
+	init := g.IsInitPhase()
+
+	if err := g.canPlaceRoad(player, c, init); err != nil {
+		return err
 	}
 
-	if !init {
-		g.MoveCards(int(player.Order), -1, entities.CardTypeWood, 1, false, false)
-		g.MoveCards(int(player.Order), -1, entities.CardTypeBrick, 1, false, false)
+	if err := player.CanBuild(entities.BTRoad); !init && err != nil {
+		return err
 	}
 
 	e, err := g.Graph.GetEdge(c)
@@ -202,6 +217,19 @@ func (g *Game) BuildRoad(player *entities.Player, c entities.EdgeCoordinate) err
 		return err
 	}
 
+	bridge := g.isBridge(e) && !init
+	if bridge && !g.canBuildBridge(player) {
+		return errors.New("not enough resources for a bridge")
+	}
+
+	if !init {
+		g.payCost(player, player.Rules.GetCost(entities.BTRoad), false)
+	}
+
+	if bridge {
+		g.MoveCards(int(player.Order), -1, entities.CardTypeBrick, 1, false, false)
+	}
+
 	err = player.BuildAtEdge(e, entities.BTRoad)
 	if err != nil {
 		return err
@@ -209,7 +237,33 @@ func (g *Game) BuildRoad(player *entities.Player, c entities.EdgeCoordinate) err
 
 	player.BuildablesLeft[entities.BTRoad]--
 
-	// Reveal Fog Tiles if possible
+	// Bridges are paid back in gold
+	if bridge {
+		g.giveGold(player, 1)
+	}
+
+	if err := g.revealFog(player, c); err != nil {
+		return err
+	}
+
+	g.SetExtraVictoryPoints()
+
+	g.SendPlayerSecret(player)
+	g.BroadcastState()
+	g.BroadcastMessage(&entities.Message{
+		Type: entities.MessageTypeEdgePlacement,
+		Data: e.Placement,
+	})
+
+	g.j.WEdgeBuild(e)
+
+	g.CheckForVictory()
+
+	return nil
+}
+
+// Reveal Fog Tiles next to a new road or ship if possible
+func (g *Game) revealFog(player *entities.Player, c entities.EdgeCoordinate) error {
 	adjacentTiles := make([]*entities.Tile, 0)
 	v1, err := g.Graph.GetVertex(c.C1)
 	if err != nil {
@@ -224,9 +278,11 @@ func (g *Game) BuildRoad(player *entities.Player, c entities.EdgeCoordinate) err
 	adjacentTiles = append(adjacentTiles, v1.AdjacentTiles...)
 	adjacentTiles = append(adjacentTiles, v2.AdjacentTiles...)
 
+	gold := 0
 	for _, t := range adjacentTiles {
 		if t.Fog {
 			t.Fog = false
+			g.preventUndo()
 
 			if t.Type >= entities.TileTypeWood && t.Type <= entities.TileTypeOre {
 				if g.Bank.Hand.GetCardDeck(entities.CardType(t.Type)).Quantity > 0 {
@@ -234,7 +290,9 @@ func (g *Game) BuildRoad(player *entities.Player, c entities.EdgeCoordinate) err
 				}
 			}
 
-			// TODO: Give gold on opening gold
+			if t.Type == entities.TileTypeGold {
+				gold++
+			}
 
 			g.BroadcastMessage(&entities.Message{
 				Type: entities.MessageTypeTileFog,
@@ -243,18 +301,10 @@ func (g *Game) BuildRoad(player *entities.Player, c entities.EdgeCoordinate) err
 		}
 	}
 
-	g.SetExtraVictoryPoints()
-
-	g.SendPlayerSecret(player)
-	g.BroadcastState()
-	g.BroadcastMessage(&entities.Message{
-		Type: entities.MessageTypeEdgePlacement,
-		Data: e.Placement,
-	})
-
-	g.j.WEdgeBuild(e)
-
-	g.CheckForVictory()
+	// Gold lets the player choose the resources
+	if gold > 0 && !g.j.playing {
+		g.goAsync(func() { g.GiveGold([]GoldCall{{Player: player, Quantity: gold}}) })
+	}
 
 	return nil
 }
@@ -272,10 +322,21 @@ func (g *Game) BuyDevelopmentCard(player *entities.Player) error {
 		return errors.New("cannot buy development card")
 	}
 
-	g.MoveCards(int(player.Order), -1, entities.CardTypeWool, 1, true, false)
-	g.MoveCards(int(player.Order), -1, entities.CardTypeWheat, 1, true, false)
-	g.MoveCards(int(player.Order), -1, entities.CardTypeOre, 1, true, false)
+	g.payCost(player, player.Rules.GetDevelopmentCardCost(), true)
+	g.setDevelopmentCardsBought(player, player.DevelopmentCardsBought+1)
+
+	g.drawDevelopmentCard(player)
+
+	g.SendPlayerSecret(player)
+	g.BroadcastState()
 
+	g.CheckForVictory()
+
+	return nil
+}
+
+// Give the next development card of the stack to the player
+func (g *Game) drawDevelopmentCard(player *entities.Player) {
 	developmentCardType := g.Bank.DevelopmentCardOrder[0][g.Bank.DevelopmentCardCursor]
 	g.Bank.DevelopmentCardCursor++
 	g.j.WDevelopmentCardCursor(g.Bank.DevelopmentCardCursor)
@@ -284,13 +345,6 @@ func (g *Game) BuyDevelopmentCard(player *entities.Player) error {
 	developmentCardDeck.Quantity += 1
 	g.MoveDevelopmentCard(-1, int(player.Order), developmentCardType, true)
 	g.j.WUpdateDevelopmentCard(player, developmentCardType, developmentCardDeck.Quantity, developmentCardDeck.NumUsed, developmentCardDeck.CanUse)
-
-	g.SendPlayerSecret(player)
-	g.BroadcastState()
-
-	g.CheckForVictory()
-
-	return nil
 }
 
 func (g *Game) BuildKnight(player *entities.Player, coordinates entities.Coordinate) error {
@@ -354,6 +408,13 @@ func (g *Game) BuildKnight(player *entities.Player, coordinates entities.Coordin
 	vertex.Placement.(*entities.Knight).CanUse = isActivated
 	player.BuildablesLeft[knightType]--
 
+	// New warriors attack the barbarians around them
+	if g.Mode.HasBarbarianAttack() {
+		for _, t := range vertex.AdjacentTiles {
+			g.fightBarbarians(t)
+		}
+	}
+
 	g.SetExtraVictoryPoints()
 
 	g.SendPlayerSecret(player)
@@ -508,6 +569,10 @@ func (g *Game) KnightChaseRobber(player *entities.Player, dry bool) error {
 		return errors.New("no adjacent activated knight to robber")
 	}
 
+	if err := g.ensureRobberCanMove(); err != nil {
+		return err
+	}
+
 	if dry {
 		return nil
 	}
@@ -527,7 +592,7 @@ func (g *Game) KnightChaseRobber(player *entities.Player, dry bool) error {
 	v, _ := g.Graph.GetVertex(loc)
 
 	if player.GetIsBot() {
-		v = vertices[rand.Intn(len(vertices))]
+		v = g.randomVertex(vertices)
 	}
 
 	found := false
@@ -547,8 +612,9 @@ func (g *Game) KnightChaseRobber(player *entities.Player, dry bool) error {
 		Data: v.Placement,
 	})
 
-	g.MoveRobberInteractive()
-	g.StealCardWithRobber()
+	if err := g.MoveRobberInteractive(); err == nil {
+		g.StealCardWithRobber()
+	}
 
 	return nil
 }
@@ -775,9 +841,21 @@ func (g *Game) EndTurn(player *entities.Player) error {
 
 	g.j.WEndTurn(player)
 
+	// Special builds end as many turns, only the player's own counts
+	if !g.SpecialBuildPhase {
+		g.countTurn()
+	}
+
 	g.CurrentOffers = make([]*entities.TradeOffer, 0)
 	g.resetTimeLeft()
 
+	if g.Mode == entities.Seafarers {
+		g.resetShips(player)
+	}
+	player.GoldTrades = 0
+	player.DevelopmentCardsBought = 0
+	g.Event = entities.EventNone
+
 	if g.Settings.SpecialBuild {
 		if g.SpecialBuildStarter == nil {
 			g.SpecialBuildStarter = player
@@ -845,14 +923,14 @@ func (g *Game) EndTurn(player *entities.Player) error {
 	g.BroadcastState()
 	g.BroadcastMessage(&entities.Message{Type: entities.MessageTypeTradeCloseOffers})
 
-	g.ai.Reset()
+	g.resetBots()
 
 	return nil
 }
 
 func (g *Game) EndTurnResetDevelopmentCards() {
 	// Make all development cards usable
-	if g.Mode == entities.Base {
+	if g.Mode.UsesBaseCards() {
 		for _, deck := range g.CurrentPlayer.CurrentHand.DevelopmentCardDeckMap {
 			if deck.Quantity > 0 && deck.Type != entities.DevelopmentCardVictoryPoint {
 				deck.CanUse = true
@@ -940,6 +1018,10 @@ func (g *Game) GetRatiosForPlayer(player *entities.Player) [9]int {
 		ratios = MergeRatios(ratios, g.MerchantFleets)
 	}
 
+	if g.Event == entities.EventTradeBonus {
+		ratios = MergeRatios(ratios, GetRatiosForPortType(entities.PortTypeAny))
+	}
+
 	return ratios
 }
 
@@ -1027,12 +1109,14 @@ func (g *Game) Trade(player *entities.Player, acceptingPlayer *entities.Player,
 		askOrder = int(acceptingPlayer.Order)
 	}
 
+	given, taken := 0, 0
 	for i, val := range offerDetails.Give {
 		if val <= 0 {
 			continue
 		}
 		cardType := entities.CardType(i)
 		g.MoveCards(int(player.Order), askOrder, cardType, val, true, false)
+		given += val
 	}
 
 	for i, val := range offerDetails.Ask {
@@ -1041,7 +1125,9 @@ func (g *Game) Trade(player *entities.Player, acceptingPlayer *entities.Player,
 		}
 		cardType := entities.CardType(i)
 		g.MoveCards(askOrder, int(player.Order), cardType, val, true, false)
+		taken += val
 	}
+	g.countTrade(player, askOrder, given, taken)
 
 	g.SendPlayerSecret(player)
 	if acceptingPlayer != nil {
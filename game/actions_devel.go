@@ -31,8 +31,10 @@ func (g *Game) UseDevelopmentCard(player *entities.Player, developmentCardType e
 		thisDeck.Quantity--
 		thisDeck.NumUsed++
 		g.MoveDevelopmentCard(int(player.Order), -1, thisDeck.Type, false)
-		if g.Mode == entities.Base {
-			for _, deck := range g.CurrentPlayer.CurrentHand.DevelopmentCardDeckMap {
+		g.countCardPlayed(player, thisDeck.Type)
+		if g.Mode.UsesBaseCards() {
+			for _, t := range g.CurrentPlayer.CurrentHand.GetDevelopmentCardTypes() {
+				deck := g.CurrentPlayer.CurrentHand.DevelopmentCardDeckMap[t]
 				deck.CanUse = false
 				g.j.WUpdateDevelopmentCard(player, deck.Type, deck.Quantity, deck.NumUsed, deck.CanUse)
 			}
@@ -41,11 +43,17 @@ func (g *Game) UseDevelopmentCard(player *entities.Player, developmentCardType e
 
 	switch developmentCardType {
 	case entities.DevelopmentCardKnight:
+		// The player keeps the card if the robber cannot move
+		if err := g.ensureRobberCanMove(); err != nil {
+			return err
+		}
+
 		useCard()
 		g.BroadcastDevCardUse(thisDeck.Type, 0, -1)
 		g.SetExtraVictoryPoints()
-		g.MoveRobberInteractive()
-		g.StealCardWithRobber()
+		if err := g.MoveRobberInteractive(); err == nil {
+			g.StealCardWithRobber()
+		}
 		g.CheckForVictory()
 		g.BroadcastDevCardUse(thisDeck.Type, 500, -1)
 
@@ -86,15 +94,7 @@ func (g *Game) UseDevelopmentCard(player *entities.Player, developmentCardType e
 		}
 
 		if !found { // Probably a timeout
-			// Cheat code - find the most abundant card
-			minCount := 999
-			for _, t := range AllowedTypes {
-				deck := g.Bank.Hand.GetCardDeck(entities.CardType(t))
-				if deck.Quantity < int16(minCount) {
-					monopolyResource = deck.Type
-					minCount = int(deck.Quantity)
-				}
-			}
+			monopolyResource = g.botStrategy(player).ChooseMonopolyResource(player)
 		}
 
 		for _, p := range g.Players {
@@ -161,7 +161,7 @@ func (g *Game) UseDevelopmentCard(player *entities.Player, developmentCardType e
 
 		for resourcesLeft > 0 {
 			// Only for base game - bank only has valid cards
-			ct := g.Bank.Hand.ChooseRandomCardType()
+			ct := g.Bank.Hand.ChooseRandomCardType(g.Rand)
 			if ct == nil {
 				break
 			}
@@ -188,8 +188,23 @@ func (g *Game) UseDevelopmentCard(player *entities.Player, developmentCardType e
 
 func (g *Game) UseDevRoadBuilding(player *entities.Player, types []entities.BuildableType) {
 	buildRoad := func() {
-		locations := player.GetBuildLocationsRoad(g.Graph, false)
-		if player.BuildablesLeft[entities.BTRoad] <= 0 || len(locations) == 0 {
+		locations := make([]*entities.Edge, 0)
+		if player.BuildablesLeft[entities.BTRoad] > 0 {
+			locations = append(locations, player.GetBuildLocationsRoad(g.Graph, false)...)
+		}
+
+		// Ships can be built instead of roads in seafarers
+		roads := len(locations)
+		if g.Mode == entities.Seafarers && player.BuildablesLeft[entities.BTShip] > 0 {
+			for _, e := range player.GetBuildLocationsShip(g.Graph, g.Pirate.Tile) {
+				// Coasts are built as roads
+				if !containsEdge(locations[:roads], e) {
+					locations = append(locations, e)
+				}
+			}
+		}
+
+		if len(locations) == 0 {
 			return
 		}
 
@@ -207,19 +222,27 @@ func (g *Game) UseDevRoadBuilding(player *entities.Player, types []entities.Buil
 		var bedge entities.EdgeCoordinate
 		mapstructure.Decode(res, &bedge)
 
-		// Make sure the player has cards
-		// Do not check the bank
-		player.CurrentHand.UpdateResources(1, 1, 0, 0, 0)
-		g.j.WUpdateResources(player, 1, 1, 0, 0, 0)
-
 		// Prevent animation
 		orig := player.UsingDevCard
 		player.UsingDevCard = entities.DevelopmentCardRoadBuilding
 
-		err = g.BuildRoad(player, bedge)
-		if err != nil {
-			// Build at first place possible
-			g.BuildRoad(player, locations[0].C)
+		// Build at first place possible if the choice is invalid
+		choice := 0
+		for i, e := range locations {
+			if e.C == bedge || (e.C.C1 == bedge.C2 && e.C.C2 == bedge.C1) {
+				choice = i
+				break
+			}
+		}
+
+		// Make sure the player has cards
+		// Do not check the bank
+		if choice < roads {
+			g.buildFreeRoad(player, locations[choice])
+		} else {
+			player.CurrentHand.UpdateResources(1, 0, 1, 0, 0)
+			g.j.WUpdateResources(player, 1, 0, 1, 0, 0)
+			g.BuildShip(player, locations[choice].C)
 		}
 
 		// Reset
@@ -318,6 +341,11 @@ func (g *Game) ReinsertDevelopmentCard(p *entities.Player, card entities.Develop
 	g.j.WReinsertDevelopmentCard(p, card)
 	g.MoveDevelopmentCard(int(p.Order), -1, card, secret)
 
+	// Discards are secret, replayed cards are counted from their own entry
+	if !secret && !g.j.playing {
+		g.countCardPlayed(p, card)
+	}
+
 	deck := p.CurrentHand.GetDevelopmentCardDeck(card)
 	if deck == nil {
 		return
@@ -3,7 +3,6 @@ package game
 import (
 	"errors"
 	"imperials/entities"
-	"math/rand"
 	"strconv"
 	"sync"
 
@@ -15,6 +14,10 @@ func (g *Game) UseProgressCoinBishop(p *entities.Player, dry bool) error {
 		return errors.New("cannot move robber till first attack")
 	}
 
+	if err := g.ensureRobberCanMove(); err != nil {
+		return err
+	}
+
 	if dry {
 		return nil
 	}
@@ -32,7 +35,7 @@ func (g *Game) UseProgressCoinBishop(p *entities.Player, dry bool) error {
 
 		stolen[owner.Order] = true
 
-		ct := owner.CurrentHand.ChooseRandomCardType()
+		ct := owner.CurrentHand.ChooseRandomCardType(g.Rand)
 		if ct != nil {
 			g.MoveCards(int(owner.Order), int(p.Order), *ct, 1, true, true)
 			g.SendPlayerSecret(owner)
@@ -90,7 +93,7 @@ func (g *Game) UseProgressCoinDeserter(p *entities.Player, dry bool) error {
 	stoleOrder := 0
 	err = mapstructure.Decode(exp, &stoleOrder)
 	if err != nil || stoleOrder < 0 || stoleOrder > len(g.Players) || !stealChoices[stoleOrder] {
-		stoleOrder = int(stealPlayers[rand.Intn(len(stealPlayers))].Order)
+		stoleOrder = int(stealPlayers[g.Rand.Intn(len(stealPlayers))].Order)
 	}
 
 	vertices := make([]*entities.Vertex, 0)
@@ -120,7 +123,7 @@ func (g *Game) UseProgressCoinDeserter(p *entities.Player, dry bool) error {
 		}
 	}
 	if !found {
-		v = vertices[rand.Intn(len(vertices))]
+		v = g.randomVertex(vertices)
 	}
 
 	wasActivated := v.Placement.(*entities.Knight).Activated
@@ -156,7 +159,7 @@ func (g *Game) UseProgressCoinDeserter(p *entities.Player, dry bool) error {
 			}
 		}
 		if !found {
-			v = buildLocations[rand.Intn(len(buildLocations))]
+			v = g.randomVertex(buildLocations)
 		}
 
 		err := p.BuildAtVertex(v, level)
@@ -249,7 +252,7 @@ func (g *Game) UseProgressCoinDiplomat(p *entities.Player, dry bool) error {
 		}
 	}
 	if redge == nil || redge.Placement == nil {
-		redge = edges[rand.Intn(len(edges))]
+		redge = g.randomEdge(edges)
 	}
 
 	owner := redge.Placement.GetOwner()
@@ -363,7 +366,8 @@ func (g *Game) UseProgressCoinWedding(p *entities.Player, dry bool) error {
 		wg.Add(1)
 		p.ClearExpect()
 
-		go func(wg *sync.WaitGroup, stoleFrom *entities.Player, action *entities.PlayerActionSelectCards) {
+		stoleFrom := stoleFrom
+		g.fork(func() {
 			defer wg.Done()
 
 			defer g.Unlock()
@@ -401,7 +405,7 @@ func (g *Game) UseProgressCoinWedding(p *entities.Player, dry bool) error {
 			}
 
 			for sum < action.Quantity {
-				t := stoleFrom.CurrentHand.ChooseRandomCardType()
+				t := stoleFrom.CurrentHand.ChooseRandomCardType(g.Rand)
 				if t == nil {
 					break
 				}
@@ -413,7 +417,7 @@ func (g *Game) UseProgressCoinWedding(p *entities.Player, dry bool) error {
 			stoleFrom.SendAction(&entities.PlayerAction{Type: entities.PlayerActionTypeSelectCardsDone})
 			g.SendPlayerSecret(stoleFrom)
 			g.BroadcastState()
-		}(&wg, stoleFrom, action)
+		})
 	}
 
 	g.TickerPause = true
@@ -538,7 +542,7 @@ func (g *Game) UseProgressCoinSpy(p *entities.Player, dry bool) error {
 	stoleOrder := 0
 	err = mapstructure.Decode(exp, &stoleOrder)
 	if err != nil || stoleOrder < 0 || stoleOrder > len(g.Players) || !stealChoices[stoleOrder] {
-		stoleOrder = int(stealPlayers[rand.Intn(len(stealPlayers))].Order)
+		stoleOrder = int(stealPlayers[g.Rand.Intn(len(stealPlayers))].Order)
 	}
 
 	stoleFrom := g.Players[stoleOrder]
@@ -579,7 +583,7 @@ func (g *Game) UseProgressCoinSpy(p *entities.Player, dry bool) error {
 	}
 
 	if stealCard == 0 {
-		stealCard = *stoleFrom.CurrentHand.ChooseRandomDevCardType()
+		stealCard = *stoleFrom.CurrentHand.ChooseRandomDevCardType(g.Rand)
 	}
 
 	stealDeck := stoleFrom.CurrentHand.GetDevelopmentCardDeck(stealCard)
@@ -3,7 +3,6 @@ package game
 import (
 	"errors"
 	"imperials/entities"
-	"math/rand"
 
 	"github.com/mitchellh/mapstructure"
 )
@@ -31,8 +30,8 @@ func (g *Game) UseProgressPaperAlchemist(p *entities.Player, dry bool) error {
 	err = mapstructure.Decode(exp, &res)
 	if err != nil || res == nil || len(res) != 2 || res[0] <= 0 || res[0] > 6 || res[1] <= 0 || res[1] > 6 {
 		res = make([]int, 2)
-		res[0] = rand.Intn(6) + 1
-		res[1] = rand.Intn(6) + 1
+		res[0] = g.Rand.Intn(6) + 1
+		res[1] = g.Rand.Intn(6) + 1
 	}
 	redRoll := res[0]
 	whiteRoll := res[1]
@@ -172,7 +171,7 @@ func (g *Game) UseProgressPaperInventor(p *entities.Player, dry bool) error {
 	err = mapstructure.Decode(exp, &resp1)
 	selTile1 := g.Graph.Tiles[resp1]
 	if err != nil {
-		selTile1 = tiles1[rand.Intn(len(tiles1))]
+		selTile1 = g.randomTile(tiles1)
 	}
 
 	{ // Check if valid
@@ -210,7 +209,7 @@ func (g *Game) UseProgressPaperInventor(p *entities.Player, dry bool) error {
 	selTile2 := g.Graph.Tiles[resp2]
 
 	if err != nil {
-		selTile2 = tiles2[rand.Intn(len(tiles2))]
+		selTile2 = g.randomTile(tiles2)
 	}
 
 	{ // Check if valid
@@ -533,7 +532,7 @@ func (g *Game) UseProgressPaperSmith(p *entities.Player, dry bool) error {
 	}
 	if !found {
 		if p.GetIsBot() {
-			v2 = vertices2[rand.Intn(len(vertices2))]
+			v2 = g.randomVertex(vertices2)
 		} else {
 			return nil
 		}
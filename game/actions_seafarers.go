@@ -0,0 +1,295 @@
+package game
+
+import (
+	"errors"
+	"imperials/entities"
+)
+
+func (g *Game) BuildShip(player *entities.Player, c entities.EdgeCoordinate) error {
+	if g.Mode != entities.Seafarers {
+		return errors.New("ships are only available in seafarers")
+	}
+
+	if err := g.EnsureCurrentPlayer(player); err != nil {
+		return err
+	}
+
+	if err := g.ensureDiceRolled(); err != nil {
+		return err
+	}
+
+	if err := player.CanBuild(entities.BTShip); err != nil {
+		return err
+	}
+
+	e, err := g.Graph.GetEdge(c)
+	if err != nil {
+		return err
+	}
+
+	if !containsEdge(player.GetBuildLocationsShip(g.Graph, g.Pirate.Tile), e) {
+		return errors.New("cannot build ship here")
+	}
+
+	g.payCost(player, player.Rules.GetCost(entities.BTShip), false)
+
+	err = player.BuildAtEdge(e, entities.BTShip)
+	if err != nil {
+		return err
+	}
+
+	player.BuildablesLeft[entities.BTShip]--
+
+	if err := g.revealFog(player, c); err != nil {
+		return err
+	}
+
+	g.SetExtraVictoryPoints()
+
+	g.SendPlayerSecret(player)
+	g.BroadcastState()
+	g.BroadcastMessage(&entities.Message{
+		Type: entities.MessageTypeEdgePlacement,
+		Data: e.Placement,
+	})
+
+	g.j.WEdgeBuild(e)
+
+	g.CheckForVictory()
+
+	return nil
+}
+
+func (g *Game) CanMoveShip(player *entities.Player) error {
+	if g.Mode != entities.Seafarers {
+		return errors.New("ships are only available in seafarers")
+	}
+
+	if err := g.EnsureCurrentPlayer(player); err != nil {
+		return err
+	}
+
+	if g.DiceState == 0 {
+		return errors.New("dice not rolled")
+	}
+
+	if err := g.ensureNotSpecialBuildPhase(); err != nil {
+		return err
+	}
+
+	if g.ShipMoved {
+		return errors.New("already moved a ship this turn")
+	}
+
+	if len(player.GetMovableShips(g.Graph, g.Pirate.Tile)) == 0 {
+		return errors.New("no ship can be moved")
+	}
+
+	return nil
+}
+
+// Locations the ship could sail to, ignoring its current position
+func (g *Game) GetShipMoveLocations(player *entities.Player, from *entities.Edge) []*entities.Edge {
+	ship := from.Placement
+	if ship == nil || ship.GetOwner() != player {
+		return []*entities.Edge{}
+	}
+
+	placements := player.EdgePlacements
+	from.RemovePlacement()
+	locations := player.GetBuildLocationsShip(g.Graph, g.Pirate.Tile)
+	from.Placement = ship
+	player.EdgePlacements = placements
+
+	edges := make([]*entities.Edge, 0, len(locations))
+	for _, e := range locations {
+		if e != from {
+			edges = append(edges, e)
+		}
+	}
+	return edges
+}
+
+// Move the open ship at the end of a shipping route, once per turn
+func (g *Game) MoveShip(player *entities.Player, fromC entities.EdgeCoordinate, toC entities.EdgeCoordinate) error {
+	if err := g.CanMoveShip(player); err != nil {
+		return err
+	}
+
+	from, err := g.Graph.GetEdge(fromC)
+	if err != nil {
+		return err
+	}
+
+	to, err := g.Graph.GetEdge(toC)
+	if err != nil {
+		return err
+	}
+
+	if !containsEdge(player.GetMovableShips(g.Graph, g.Pirate.Tile), from) {
+		return errors.New("this ship cannot be moved")
+	}
+
+	if !containsEdge(g.GetShipMoveLocations(player, from), to) {
+		return errors.New("cannot move ship here")
+	}
+
+	g.BroadcastMessage(&entities.Message{
+		Type: entities.MessageTypeEdgePlacementRem,
+		Data: from.Placement,
+	})
+
+	from.RemovePlacement()
+	player.BuildAtEdge(to, entities.BTShip)
+	to.Placement.(*entities.Ship).New = false
+	g.ShipMoved = true
+
+	if err := g.revealFog(player, toC); err != nil {
+		return err
+	}
+
+	g.SetExtraVictoryPoints()
+
+	g.SendPlayerSecret(player)
+	g.BroadcastState()
+	g.BroadcastMessage(&entities.Message{
+		Type: entities.MessageTypeEdgePlacement,
+		Data: to.Placement,
+	})
+
+	g.j.WMoveShip(from, to)
+
+	g.CheckForVictory()
+
+	return nil
+}
+
+// Ships built this turn can be moved from the next turn
+func (g *Game) resetShips(player *entities.Player) {
+	g.ShipMoved = false
+	for _, ep := range player.EdgePlacements {
+		if s, ok := ep.(*entities.Ship); ok {
+			s.New = false
+		}
+	}
+}
+
+func containsEdge(edges []*entities.Edge, e *entities.Edge) bool {
+	for _, o := range edges {
+		if o == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Number the islands of the map, land tiles sharing an edge are on
+// the same island
+func (g *Game) getIslands() map[entities.Coordinate]int {
+	if g.islands != nil {
+		return g.islands
+	}
+
+	g.islands = make(map[entities.Coordinate]int)
+	island := 0
+	for _, c := range sortedCoordinates(g.Tiles) {
+		if _, ok := g.islands[c]; ok || !g.Tiles[c].IsLand() {
+			continue
+		}
+
+		queue := []*entities.Tile{g.Tiles[c]}
+		g.islands[c] = island
+		for len(queue) > 0 {
+			t := queue[0]
+			queue = queue[1:]
+
+			for _, ec := range t.GetEdgeCoordinates() {
+				e, err := g.Graph.GetEdge(ec)
+				if err != nil {
+					continue
+				}
+
+				for _, adj := range e.AdjacentTiles {
+					if _, ok := g.islands[adj.Center]; !ok && adj.IsLand() {
+						g.islands[adj.Center] = island
+						queue = append(queue, adj)
+					}
+				}
+			}
+		}
+		island++
+	}
+
+	return g.islands
+}
+
+// Island of a vertex, -1 if it is only next to sea
+func (g *Game) getIsland(v *entities.Vertex) int {
+	islands := g.getIslands()
+	for _, t := range v.AdjacentTiles {
+		if t.IsLand() {
+			return islands[t.Center]
+		}
+	}
+	return -1
+}
+
+// Islands settled at the start are home, every other island gives
+// the bonus for the first settlement of a player there
+func (g *Game) settleIsland(player *entities.Player, v *entities.Vertex) {
+	if g.Mode != entities.Seafarers {
+		return
+	}
+
+	island := g.getIsland(v)
+	if island < 0 {
+		return
+	}
+
+	settled := g.ExtraVictoryPoints.SettledIslands[player.Order]
+	if _, ok := settled[island]; !ok {
+		settled[island] = !g.InitPhase
+	}
+}
+
+func (g *Game) GetIslandVictoryPoints(player *entities.Player) int {
+	if g.Mode != entities.Seafarers {
+		return 0
+	}
+
+	vp := 0
+	for _, bonus := range g.ExtraVictoryPoints.SettledIslands[player.Order] {
+		if bonus {
+			vp += g.ExtraVictoryPoints.IslandBonus
+		}
+	}
+	return vp
+}
+
+// Players with a ship next to the pirate
+func (g *Game) getPirateVictims() []*entities.Player {
+	if g.Pirate.Tile == nil {
+		return make([]*entities.Player, 0)
+	}
+	return g.getShipOwners(g.Pirate.Tile)
+}
+
+// Players with a ship next to the tile
+func (g *Game) getShipOwners(t *entities.Tile) []*entities.Player {
+	victims := make([]*entities.Player, 0)
+	seen := make([]bool, len(g.Players))
+	for _, ec := range t.GetEdgeCoordinates() {
+		e, err := g.Graph.GetEdge(ec)
+		if err != nil || e.Placement == nil || e.Placement.GetType() != entities.BTShip {
+			continue
+		}
+
+		o := e.Placement.GetOwner()
+		if !seen[o.Order] {
+			seen[o.Order] = true
+			victims = append(victims, o)
+		}
+	}
+
+	return victims
+}
@@ -0,0 +1,30 @@
+package game
+
+import (
+	"imperials/entities"
+	"testing"
+)
+
+func TestRevealFogGold(t *testing.T) {
+	g, p := newUndoTestGame(t, "fog-gold", nil)
+	p.SetIsBot(true)
+	cards := p.CurrentHand.GetCardCount()
+
+	// The gold is given in the background once the action is done
+	g.Lock()
+	buildTestRoad(t, g, p, func(e *entities.Edge) {
+		v, err := g.Graph.GetVertex(e.C.C2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v.AdjacentTiles[0].Type = entities.TileTypeGold
+		v.AdjacentTiles[0].Fog = true
+	})
+	g.Unlock()
+	g.async.Wait()
+
+	// A road costs two cards and the gold gives one back
+	if got := p.CurrentHand.GetCardCount(); got != cards-1 {
+		t.Fatalf("player has %d cards, want %d", got, cards-1)
+	}
+}
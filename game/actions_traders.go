@@ -0,0 +1,630 @@
+package game
+
+import (
+	"errors"
+	"imperials/entities"
+	"sort"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// Fishermen
+
+func (g *Game) getLake() *entities.Tile {
+	for _, c := range sortedCoordinates(g.Tiles) {
+		if g.Tiles[c].Type == entities.TileTypeLake {
+			return g.Tiles[c]
+		}
+	}
+	return nil
+}
+
+// Settlements next to the lake catch one fish and cities two
+// The robber does not keep the fish away
+func (g *Game) produceFish() {
+	lake := g.getLake()
+	if lake == nil {
+		return
+	}
+
+	for _, placement := range g.Graph.GetTilePlacements(lake) {
+		switch placement.GetType() {
+		case entities.BTSettlement:
+			placement.GetOwner().Fish++
+		case entities.BTCity:
+			placement.GetOwner().Fish += 2
+		}
+	}
+}
+
+func (g *Game) setFish(player *entities.Player, fish int) {
+	player.Fish = fish
+	g.j.WSetFish(player)
+}
+
+// Opponents that have a card to steal with fish
+func (g *Game) getFishVictims(player *entities.Player) []*entities.Player {
+	victims := make([]*entities.Player, 0)
+	for _, o := range g.Players {
+		if o != player && o.CurrentHand.GetCardCount() > 0 {
+			victims = append(victims, o)
+		}
+	}
+	return victims
+}
+
+func (g *Game) CanUseFish(player *entities.Player, amount int) error {
+	if !g.Mode.HasFish() {
+		return errors.New("fish are only available in the fishermen scenario")
+	}
+
+	if err := g.EnsureCurrentPlayer(player); err != nil {
+		return err
+	}
+
+	if g.DiceState == 0 {
+		return errors.New("dice not rolled")
+	}
+
+	if err := g.ensureNotSpecialBuildPhase(); err != nil {
+		return err
+	}
+
+	if !entities.IsFishAmount(amount) {
+		return errors.New("invalid amount of fish")
+	}
+
+	if player.Fish < amount {
+		return errors.New("not enough fish")
+	}
+
+	switch amount {
+	case entities.FishRobber:
+		lake := g.getLake()
+		if lake == nil || g.Robber.Tile == lake {
+			return errors.New("the robber is already on the lake")
+		}
+	case entities.FishSteal:
+		if len(g.getFishVictims(player)) == 0 {
+			return errors.New("nobody to steal from")
+		}
+	case entities.FishResource:
+		if g.Bank.Hand.GetCardCount() == 0 {
+			return errors.New("no cards left in the bank")
+		}
+	case entities.FishRoad:
+		if player.BuildablesLeft[entities.BTRoad] <= 0 || len(player.GetBuildLocationsRoad(g.Graph, false)) == 0 {
+			return errors.New("nowhere to build a road")
+		}
+	case entities.FishCard:
+		if g.Bank.DevelopmentCardCursor >= len(g.Bank.DevelopmentCardOrder[0]) {
+			return errors.New("no development cards left")
+		}
+	}
+
+	return nil
+}
+
+func (g *Game) canUseAnyFish(player *entities.Player) bool {
+	for _, amount := range entities.FishAmounts {
+		if g.CanUseFish(player, amount) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Spend fish, the amount decides what the player gets
+func (g *Game) UseFish(player *entities.Player, amount int) error {
+	if err := g.CanUseFish(player, amount); err != nil {
+		return err
+	}
+
+	g.setFish(player, player.Fish-amount)
+
+	switch amount {
+	case entities.FishRobber:
+		// Send the robber back to the lake
+		lake := g.getLake()
+		g.Robber.Move(lake)
+		g.j.WSetRobber(lake)
+
+	case entities.FishSteal:
+		victims := g.getFishVictims(player)
+		choices := make([]bool, len(g.Players))
+		for _, o := range victims {
+			choices[o.Order] = true
+		}
+
+		order := int(victims[0].Order)
+		if len(victims) > 1 {
+			exp, err := g.BlockForAction(player, g.TimerVals.ChoosePlayer, &entities.PlayerAction{
+				Type: entities.PlayerActionTypeChoosePlayer,
+				Data: entities.PlayerActionChoosePlayer{
+					Choices: choices,
+				},
+				Message: "Choose player to steal from",
+			})
+			if err != nil {
+				return err
+			}
+
+			err = mapstructure.Decode(exp, &order)
+			if err != nil || order < 0 || order >= len(g.Players) || !choices[order] {
+				order = int(g.botStrategy(player).ChooseRobberVictim(player, victims).Order)
+			}
+		}
+
+		g.stealRandomCard(player, g.Players[order])
+
+	case entities.FishResource:
+		action := &entities.PlayerActionSelectCards{
+			AllowedTypes: []int{1, 2, 3, 4, 5},
+			Quantity:     1,
+			NotSelfHand:  true,
+		}
+
+		exp, err := g.BlockForAction(player, g.TimerVals.UseDevCard, &entities.PlayerAction{
+			Type:    entities.PlayerActionTypeSelectCards,
+			Data:    action,
+			Message: "Choose a card to receive",
+		})
+		if err != nil {
+			return err
+		}
+
+		var resp []float64
+		err = mapstructure.Decode(exp, &resp)
+
+		var ct *entities.CardType
+		if err == nil && len(resp) == 9 {
+			for _, ti := range action.AllowedTypes {
+				t := entities.CardType(ti)
+				if resp[t] > 0 && g.Bank.Hand.GetCardDeck(t).Quantity > 0 {
+					ct = &t
+					break
+				}
+			}
+		}
+
+		if ct == nil {
+			t := g.botStrategy(player).ChooseMonopolyResource(player)
+			if g.Bank.Hand.GetCardDeck(t).Quantity > 0 {
+				ct = &t
+			} else {
+				ct = g.Bank.Hand.ChooseRandomCardType(g.Rand)
+			}
+		}
+
+		if ct != nil {
+			g.MoveCards(-1, int(player.Order), *ct, 1, true, false)
+		}
+		player.SendAction(&entities.PlayerAction{Type: entities.PlayerActionTypeSelectCardsDone})
+
+	case entities.FishRoad:
+		locations := player.GetBuildLocationsRoad(g.Graph, false)
+		res, err := g.BlockForAction(player, g.TimerVals.UseDevCard, &entities.PlayerAction{
+			Type:    entities.PlayerActionTypeChooseEdge,
+			Message: "Choose position for road",
+			Data: entities.PlayerActionChooseEdge{
+				Allowed: locations,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		var bedge entities.EdgeCoordinate
+		mapstructure.Decode(res, &bedge)
+
+		e, err := g.Graph.GetEdge(bedge)
+		if err != nil || !containsEdge(locations, e) {
+			e = g.botStrategy(player).ChooseBestEdgeRoad(player, locations)
+		}
+
+		orig := player.UsingDevCard
+		player.UsingDevCard = entities.DevelopmentCardRoadBuilding
+		err = g.buildFreeRoad(player, e)
+		player.UsingDevCard = orig
+		if err != nil {
+			return err
+		}
+
+	case entities.FishCard:
+		g.drawDevelopmentCard(player)
+	}
+
+	g.SendPlayerSecret(player)
+	g.BroadcastState()
+	g.CheckForVictory()
+
+	return nil
+}
+
+// Rivers
+
+func (g *Game) setRivers(coords []entities.EdgeCoordinate) {
+	for _, c := range coords {
+		if e, err := g.Graph.GetEdge(c); err == nil {
+			e.IsRiver = true
+		}
+	}
+}
+
+// Roads over a river are bridges, which cost another brick
+func (g *Game) isBridge(e *entities.Edge) bool {
+	return g.Mode.HasRivers() && e.IsRiver
+}
+
+// Cost of a road over the river
+func (g *Game) getBridgeCost(player *entities.Player) []int {
+	cost := append([]int(nil), player.Rules.GetCost(entities.BTRoad)...)
+	cost[entities.CardTypeBrick-1]++
+	return cost
+}
+
+func (g *Game) canBuildBridge(player *entities.Player) bool {
+	c := g.getBridgeCost(player)
+	return player.CurrentHand.HasResources(c[0], c[1], c[2], c[3], c[4])
+}
+
+// Build a road the player does not pay for, bridges included
+// The cards are only given once the road is known to fit there
+func (g *Game) buildFreeRoad(player *entities.Player, e *entities.Edge) error {
+	if e == nil {
+		return errors.New("cannot build road here")
+	}
+	if err := g.canPlaceRoad(player, e.C, false); err != nil {
+		return err
+	}
+
+	g.giveRoadResources(player, e)
+	return g.BuildRoad(player, e.C)
+}
+
+// Give the cards for a road built for free
+func (g *Game) giveRoadResources(player *entities.Player, e *entities.Edge) {
+	c := player.Rules.GetCost(entities.BTRoad)
+	if g.isBridge(e) {
+		c = g.getBridgeCost(player)
+	}
+	player.CurrentHand.UpdateResources(c[0], c[1], c[2], c[3], c[4])
+	g.j.WUpdateResources(player, c[0], c[1], c[2], c[3], c[4])
+}
+
+// Road locations the player can pay for, bridges included
+func (g *Game) GetRoadLocations(player *entities.Player) []*entities.Edge {
+	locations := player.GetBuildLocationsRoad(g.Graph, false)
+	if !g.Mode.HasRivers() || g.canBuildBridge(player) {
+		return locations
+	}
+
+	edges := make([]*entities.Edge, 0, len(locations))
+	for _, e := range locations {
+		if !e.IsRiver {
+			edges = append(edges, e)
+		}
+	}
+	return edges
+}
+
+// Gold for building next to the rivers, this is not journaled
+// since the builds that give it are
+func (g *Game) giveGold(player *entities.Player, gold int) {
+	if !g.Mode.HasRivers() {
+		return
+	}
+	player.Gold += gold
+}
+
+func (g *Game) isRiverVertex(v *entities.Vertex) bool {
+	for _, t := range v.AdjacentTiles {
+		for _, ec := range t.GetEdgeCoordinates() {
+			if ec.C1 != v.C && ec.C2 != v.C {
+				continue
+			}
+			if e, err := g.Graph.GetEdge(ec); err == nil && e.IsRiver {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (g *Game) CanSpendGold(player *entities.Player) error {
+	if !g.Mode.HasRivers() {
+		return errors.New("gold is only available in the rivers scenario")
+	}
+
+	if err := g.EnsureCurrentPlayer(player); err != nil {
+		return err
+	}
+
+	if g.DiceState == 0 {
+		return errors.New("dice not rolled")
+	}
+
+	if err := g.ensureNotSpecialBuildPhase(); err != nil {
+		return err
+	}
+
+	if player.Gold < entities.GoldPerResource {
+		return errors.New("not enough gold")
+	}
+
+	if player.GoldTrades >= entities.GoldTradesPerTurn {
+		return errors.New("already bought enough cards with gold this turn")
+	}
+
+	return nil
+}
+
+// Buy a resource from the bank with gold
+func (g *Game) SpendGold(player *entities.Player, cardType entities.CardType) error {
+	if err := g.CanSpendGold(player); err != nil {
+		return err
+	}
+
+	if cardType < entities.CardTypeWood || cardType > entities.CardTypeOre {
+		return errors.New("invalid card type")
+	}
+
+	if g.Bank.Hand.GetCardDeck(cardType).Quantity <= 0 {
+		return errors.New("no cards of this type left in the bank")
+	}
+
+	player.Gold -= entities.GoldPerResource
+	player.GoldTrades++
+	g.j.WSetGold(player)
+
+	g.MoveCards(-1, int(player.Order), cardType, 1, true, false)
+
+	g.SendPlayerSecret(player)
+	g.BroadcastState()
+	g.CheckForVictory()
+
+	return nil
+}
+
+// The richest players get a point and the poorest lose two,
+// unless everyone has as much gold
+func (g *Game) getGoldVictoryPoints(player *entities.Player) int {
+	most, least := player.Gold, player.Gold
+	for _, p := range g.Players {
+		if p.Gold > most {
+			most = p.Gold
+		}
+		if p.Gold < least {
+			least = p.Gold
+		}
+	}
+
+	if most == least {
+		return 0
+	}
+
+	vp := 0
+	if player.Gold == most {
+		vp++
+	}
+	if player.Gold == least {
+		vp -= 2
+	}
+	return vp
+}
+
+// Caravans
+
+func (g *Game) isOasis(t *entities.Tile) bool {
+	return t.Type == entities.TileTypeDesert || t.Type == entities.TileTypeLake
+}
+
+func (g *Game) hasCamel(e *entities.Edge) bool {
+	for _, c := range g.Camels {
+		if c == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Camels leave from the oasis and extend the caravans from there
+func (g *Game) GetCamelLocations() []*entities.Edge {
+	edges := make([]*entities.Edge, 0)
+	if len(g.Camels) >= entities.MaxCamels {
+		return edges
+	}
+
+	route := make(map[entities.Coordinate]bool)
+	for _, e := range g.Camels {
+		route[e.C.C1] = true
+		route[e.C.C2] = true
+	}
+
+	for _, e := range g.Edges {
+		if !e.HasLand() || g.hasCamel(e) {
+			continue
+		}
+
+		ok := route[e.C.C1] || route[e.C.C2]
+		for _, t := range e.AdjacentTiles {
+			ok = ok || g.isOasis(t)
+		}
+
+		if ok {
+			edges = append(edges, e)
+		}
+	}
+
+	entities.SortEdges(edges)
+	return edges
+}
+
+func (g *Game) placeCamel(e *entities.Edge) {
+	g.Camels = append(g.Camels, e)
+	g.j.WPlaceCamel(e)
+}
+
+// Let the builder of a settlement or city lead a camel
+// Should run in a separate goroutine
+func (g *Game) PlaceCamelInteractive(player *entities.Player) {
+	g.ActionMutex.Lock()
+	defer g.ActionMutex.Unlock()
+
+	defer g.Unlock()
+	if !g.Lock() {
+		return
+	}
+
+	if !g.Initialized || g.j.playing || g.GameOver {
+		return
+	}
+
+	locations := g.GetCamelLocations()
+	if len(locations) == 0 {
+		return
+	}
+
+	res, err := g.BlockForAction(player, g.TimerVals.UseDevCard, &entities.PlayerAction{
+		Type:    entities.PlayerActionTypeChooseEdge,
+		Message: "Choose position for camel",
+		Data: entities.PlayerActionChooseEdge{
+			Allowed: locations,
+		},
+	})
+	if err != nil {
+		return
+	}
+
+	var bedge entities.EdgeCoordinate
+	mapstructure.Decode(res, &bedge)
+
+	e, err := g.Graph.GetEdge(bedge)
+	if err != nil || !containsEdge(locations, e) {
+		e = g.botStrategy(player).ChooseBestEdgeRoad(player, locations)
+	}
+
+	g.placeCamel(e)
+
+	for _, p := range g.Players {
+		g.SendPlayerSecret(p)
+	}
+	g.BroadcastState()
+	g.CheckForVictory()
+}
+
+// Buildings along a caravan get a point each
+func (g *Game) getCamelVictoryPoints(player *entities.Player) int {
+	route := make(map[entities.Coordinate]bool)
+	for _, e := range g.Camels {
+		route[e.C.C1] = true
+		route[e.C.C2] = true
+	}
+
+	vp := 0
+	for _, placement := range player.VertexPlacements {
+		t := placement.GetType()
+		if (t == entities.BTSettlement || t == entities.BTCity) && route[placement.GetLocation().C] {
+			vp++
+		}
+	}
+	return vp
+}
+
+// Barbarian attack
+
+// A tile with enough barbarians produces nothing
+func (g *Game) isConquered(t *entities.Tile) bool {
+	return g.Barbarians[t.Center] >= entities.BarbariansToConquer
+}
+
+// Tiles on the coast where barbarians can land
+func (g *Game) getBarbarianCoast() []*entities.Tile {
+	tiles := make([]*entities.Tile, 0)
+	for _, c := range sortedCoordinates(g.Tiles) {
+		t := g.Tiles[c]
+		if !t.IsLand() || t.Fog || t.Number == 0 || g.isConquered(t) {
+			continue
+		}
+
+		for _, ec := range t.GetEdgeCoordinates() {
+			if e, err := g.Graph.GetEdge(ec); err == nil && e.IsBeach {
+				tiles = append(tiles, t)
+				break
+			}
+		}
+	}
+	return tiles
+}
+
+// Land a barbarian on a random tile of the coast
+func (g *Game) landRandomBarbarian() {
+	tiles := g.getBarbarianCoast()
+	if len(tiles) == 0 {
+		return
+	}
+
+	tile := tiles[g.Rand.Intn(len(tiles))]
+	g.j.WLandBarbarian(tile)
+	g.landBarbarian(tile)
+}
+
+func (g *Game) landBarbarian(tile *entities.Tile) {
+	g.Barbarians[tile.Center]++
+	g.fightBarbarians(tile)
+}
+
+// Warriors around a tile capture the barbarians on it if there are
+// at least as many of them, the captives are shared in turns
+func (g *Game) fightBarbarians(tile *entities.Tile) {
+	count := g.Barbarians[tile.Center]
+	if count == 0 {
+		return
+	}
+
+	knights := make([]entities.VertexBuildable, 0)
+	for _, placement := range g.Graph.GetTilePlacements(tile) {
+		if placement.GetType() == entities.BTKnight1 {
+			knights = append(knights, placement)
+		}
+	}
+
+	if len(knights) < count {
+		return
+	}
+
+	for i := 0; i < count; i++ {
+		knights[i%len(knights)].GetOwner().CapturedBarbarians++
+	}
+	delete(g.Barbarians, tile.Center)
+}
+
+// Get barbarians on the map in a stable order
+func (g *Game) getTileBarbarians() []*entities.TileBarbarians {
+	res := make([]*entities.TileBarbarians, 0, len(g.Barbarians))
+	for c, n := range g.Barbarians {
+		res = append(res, &entities.TileBarbarians{Tile: c, Count: n})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Tile.Less(res[j].Tile) })
+	return res
+}
+
+// Victory points of all the traders & barbarians scenarios
+func (g *Game) GetTradersVictoryPoints(player *entities.Player) int {
+	vp := 0
+
+	if g.Mode.HasRivers() {
+		vp += g.getGoldVictoryPoints(player)
+	}
+
+	if g.Mode.HasCaravans() {
+		vp += g.getCamelVictoryPoints(player)
+	}
+
+	// Every two captured barbarians are worth a point
+	if g.Mode.HasBarbarianAttack() {
+		vp += player.CapturedBarbarians / 2
+	}
+
+	return vp
+}
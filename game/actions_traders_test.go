@@ -0,0 +1,50 @@
+package game
+
+import (
+	"imperials/entities"
+	"testing"
+)
+
+func TestBuildFreeRoad(t *testing.T) {
+	g, p := newUndoTestGame(t, "free-road", nil)
+	locations := p.GetBuildLocationsRoad(g.Graph, false)
+	entities.SortEdges(locations)
+
+	// An edge far from the pieces of the player
+	var far *entities.Edge
+	for _, e := range g.Graph.Edges {
+		if e.Placement == nil && !containsEdge(locations, e) {
+			far = e
+			break
+		}
+	}
+	if far == nil {
+		t.Fatal("every edge is in reach")
+	}
+
+	tests := []struct {
+		name string
+		edge *entities.Edge
+		ok   bool
+	}{
+		{"no edge", nil, false},
+		{"edge out of reach", far, false},
+		{"edge next to a road", locations[0], true},
+	}
+
+	for _, tt := range tests {
+		cards := p.CurrentHand.GetCardCount()
+		roads := len(p.EdgePlacements)
+
+		err := g.buildFreeRoad(p, tt.edge)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: got error %v", tt.name, err)
+		}
+		if p.CurrentHand.GetCardCount() != cards {
+			t.Errorf("%s: player has %d cards, had %d", tt.name, p.CurrentHand.GetCardCount(), cards)
+		}
+		if tt.ok && len(p.EdgePlacements) != roads+1 {
+			t.Errorf("%s: road was not built", tt.name)
+		}
+	}
+}
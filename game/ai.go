@@ -4,7 +4,6 @@ import (
 	"imperials/entities"
 	"log"
 	"math"
-	"math/rand"
 )
 
 type AI struct {
@@ -12,6 +11,7 @@ type AI struct {
 
 	noBuildRoad   bool
 	noBuildWall   bool
+	noBuildShip   bool
 	noBuyDevCard  bool
 	tradeTime     int
 	tradeChecked  bool
@@ -23,15 +23,53 @@ type AI struct {
 
 type TileScoreMap = map[entities.TileType]float64
 
+// Tuning of the AI for a bot difficulty
+type AILevel struct {
+	RoadSearchDepth int     // Depth of the search for the best road
+	TradeChecks     int     // Rounds of player trades in a turn
+	TradeChance     float64 // Multiplier for the chance to trade towards a build
+	AcceptChance    int     // Percent of good offers that are accepted
+	Mistakes        int     // Percent of placements chosen at random
+	LeaderCaution   float64 // Price asked from players that are ahead, zero to ignore the scores
+}
+
+var AILevels = map[string]AILevel{
+	entities.BotDifficultyEasy:   {RoadSearchDepth: 1, TradeChecks: 1, TradeChance: 0.5, AcceptChance: 50, Mistakes: 40, LeaderCaution: 0},
+	entities.BotDifficultyMedium: {RoadSearchDepth: 3, TradeChecks: 4, TradeChance: 1, AcceptChance: 100, Mistakes: 0, LeaderCaution: 1},
+	entities.BotDifficultyHard:   {RoadSearchDepth: 4, TradeChecks: 6, TradeChance: 1.5, AcceptChance: 100, Mistakes: 0, LeaderCaution: 1.5},
+}
+
+func (ai *AI) level(p *entities.Player) AILevel {
+	if l, ok := AILevels[p.BotDifficulty]; ok {
+		return l
+	}
+	return AILevels[entities.BotDifficultyMedium]
+}
+
+// Randomly decide to make a bad choice
+func (ai *AI) blunder(p *entities.Player) bool {
+	mistakes := ai.level(p).Mistakes
+	return mistakes > 0 && ai.g.Rand.Intn(100) < mistakes
+}
+
+func NewAI(g *Game) *AI {
+	return &AI{g: g}
+}
+
 func (ai *AI) ChooseBestVertexSettlement(p *entities.Player, allowed []*entities.Vertex) *entities.Vertex {
 	if len(allowed) == 0 {
 		return nil
 	}
 
+	if ai.blunder(p) {
+		return ai.g.randomVertex(allowed)
+	}
+
 	maxScore := -9999.0
 	maxScoreVertex := allowed[0]
-	for v, s := range ai.getVertexSettlementScoreMap(p, allowed) {
-		if s > maxScore {
+	scores := ai.getVertexSettlementScoreMap(p, allowed)
+	for _, v := range allowed {
+		if s := scores[v]; s > maxScore {
 			maxScore = s
 			maxScoreVertex = v
 		}
@@ -66,6 +104,10 @@ func (ai *AI) getNumberScore(num uint16) float64 {
 }
 
 func (ai *AI) GetRobberTile(p *entities.Player, tiles []*entities.Tile) *entities.Tile {
+	if ai.blunder(p) {
+		return ai.g.randomTile(tiles)
+	}
+
 	// Invalid coordinates, place at best position
 	maxScore := 0.0
 	selTile := tiles[0]
@@ -103,6 +145,39 @@ func (ai *AI) GetRobberTile(p *entities.Player, tiles []*entities.Tile) *entitie
 	return selTile
 }
 
+// Steal from the player with the most victory points
+func (ai *AI) ChooseRobberVictim(p *entities.Player, choices []*entities.Player) *entities.Player {
+	victim := choices[0]
+	maxScore := 0
+	for _, c := range choices {
+		if score := ai.g.GetVictoryPoints(c, true); score > maxScore {
+			victim = c
+			maxScore = score
+		}
+	}
+	return victim
+}
+
+// Discard random cards
+func (ai *AI) ChooseDiscard(p *entities.Player, quantity int) [9]int {
+	res := [9]int{0, 0, 0, 0, 0, 0, 0, 0, 0}
+	left := int(p.CurrentHand.GetCardCount())
+
+	for i := 0; i < quantity && left > 0; i++ {
+		randomIndex := ai.g.Rand.Intn(left)
+		for _, t := range p.CurrentHand.GetCardTypes() {
+			randomIndex -= int(p.CurrentHand.CardDeckMap[t].Quantity) - res[t]
+			if randomIndex < 0 {
+				res[t]++
+				break
+			}
+		}
+		left--
+	}
+
+	return res
+}
+
 func (ai *AI) getTileScoreMap(p *entities.Player) TileScoreMap {
 	tileScore := make(TileScoreMap)
 	for _, vp := range p.VertexPlacements {
@@ -172,6 +247,10 @@ func (ai *AI) ChooseBestEdgeRoad(p *entities.Player, allowed []*entities.Edge) *
 		return nil
 	}
 
+	if ai.blunder(p) {
+		return ai.g.randomEdge(allowed)
+	}
+
 	currVert := p.GetBuildLocationsSettlement(ai.g.Graph, false, false) // currently possible locations
 	allVert := p.GetBuildLocationsSettlement(ai.g.Graph, true, true)    // all possible locations
 
@@ -187,7 +266,7 @@ func (ai *AI) ChooseBestEdgeRoad(p *entities.Player, allowed []*entities.Edge) *
 	maxScoreEdge := allowed[0]
 
 	for _, e := range allowed {
-		s := ai.g.ai.getEdgeRoadScore(p, e, currScoreMap, allScoreMap, allowedMap, 3)
+		s := ai.getEdgeRoadScore(p, e, currScoreMap, allScoreMap, allowedMap, ai.level(p).RoadSearchDepth)
 		if s > maxScore {
 			maxScore = s
 			maxScoreEdge = e
@@ -234,135 +313,202 @@ func (ai *AI) getEdgeRoadScore(
 			}
 			allowedMap[adje] = true
 			defer delete(allowedMap, adje)
-			score += 0.4 * ai.g.ai.getEdgeRoadScore(p, adje, currScoreMap, allScoreMap, allowedMap, dfsMaxDepth-1)
+			score += 0.4 * ai.getEdgeRoadScore(p, adje, currScoreMap, allScoreMap, allowedMap, dfsMaxDepth-1)
 		}
 	}
 
 	return score
 }
 
-func (ai *AI) tickPlayer(p *entities.Player) bool {
-	if p.PendingAction != nil {
-		p.SendExpect(nil)
-		return true
+// Score for an offer, positive if it is worth taking
+func (ai *AI) scoreOffer(p *entities.Player, offer *entities.TradeOffer) float64 {
+	if offer.CreatedBy == p.Order {
+		return 1
 	}
 
-	if ai.g.HasPlayerPendingAction() {
-		return true
+	if ai.barbarianBad == 0 {
+		ai.recalculateBarbarianBad()
 	}
 
-	var cityLocs []*entities.Vertex
-	var settlementLocs []*entities.Vertex
+	cityLocs := p.GetBuildLocationsCity(ai.g.Graph)
+	settlementLocs := p.GetBuildLocationsSettlement(ai.g.Graph, false, false)
+	score := 0.0
 
-	// Populate location variables
-	getLocs := func() {
-		if cityLocs == nil {
-			cityLocs = p.GetBuildLocationsCity(ai.g.Graph)
-			settlementLocs = p.GetBuildLocationsSettlement(ai.g.Graph, false, false)
-		}
+	gain := offer.Details.Give
+	lose := offer.Details.Ask
+	if offer.CurrentPlayer == p.Order {
+		gain = offer.Details.Ask
+		lose = offer.Details.Give
 	}
 
-	recalculateBarbarianBad := func() {
-		if ai.g.GetBarbarianStrength() > ai.g.GetBarbarianKnights() {
-			ai.barbarianBad = 1
-		} else {
-			ai.barbarianBad = -1
+	scoreType := func(ct entities.CardType, want int, priority float64) {
+		excess := int(p.CurrentHand.GetCardDeck(ct).Quantity) - want
+		if excess > 0 {
+			excess = ai.g.Rand.Intn(excess + 1)
 		}
-	}
-	if ai.barbarianBad == 0 {
-		recalculateBarbarianBad()
+		score += float64(excess+gain[ct]) * priority
 	}
 
-	// Score for an offer
-	offerScore := func(offer *entities.TradeOffer) float64 {
-		if offer.CreatedBy == p.Order {
-			return 1
+	for i, q := range offer.Details.Ask {
+		deck := p.CurrentHand.GetCardDeck(entities.CardType(i))
+		if deck == nil {
+			continue
+		}
+		if int(deck.Quantity) < q {
+			return -1
 		}
 
-		getLocs()
-		score := 0.0
+		score -= float64(lose[i])
 
-		gain := offer.Details.Give
-		lose := offer.Details.Ask
-		if offer.CurrentPlayer == p.Order {
-			gain = offer.Details.Ask
-			lose = offer.Details.Give
+		if ai.g.Mode == entities.CitiesAndKnights {
+			if ai.barbarianBad == 1 {
+				if p.HasInactiveKnight() {
+					scoreType(entities.CardTypeWheat, 1, 2)
+				} else {
+					scoreType(entities.CardTypeWool, 1, 1.5)
+					scoreType(entities.CardTypeOre, 1, 1.5)
+				}
+			}
 		}
 
-		scoreType := func(ct entities.CardType, want int, priority float64) {
-			excess := int(p.CurrentHand.GetCardDeck(ct).Quantity) - want
-			if excess > 0 {
-				excess = rand.Intn(excess + 1)
-			}
-			score += float64(excess+gain[ct]) * priority
+		if len(cityLocs) > 0 && p.BuildablesLeft[entities.BTCity] > 0 {
+			scoreType(entities.CardTypeWheat, 2, 1)
+			scoreType(entities.CardTypeOre, 3, 1)
 		}
 
-		for i, q := range offer.Details.Ask {
-			deck := p.CurrentHand.GetCardDeck(entities.CardType(i))
-			if deck == nil {
-				continue
-			}
-			if int(deck.Quantity) < q {
-				return -1
-			}
+		if len(settlementLocs) > 0 && p.BuildablesLeft[entities.BTSettlement] > 0 {
+			scoreType(entities.CardTypeWood, 1, 1)
+			scoreType(entities.CardTypeBrick, 1, 1)
+			scoreType(entities.CardTypeWool, 1, 1)
+			scoreType(entities.CardTypeWheat, 1, 1)
+		}
 
-			score -= float64(lose[i])
+		if len(settlementLocs) == 0 {
+			scoreType(entities.CardTypeWood, 1, 1)
+			scoreType(entities.CardTypeBrick, 1, 1)
+		}
 
-			if ai.g.Mode == entities.CitiesAndKnights {
-				if ai.barbarianBad == 1 {
-					if p.HasInactiveKnight() {
-						scoreType(entities.CardTypeWheat, 1, 2)
-					} else {
-						scoreType(entities.CardTypeWool, 1, 1.5)
-						scoreType(entities.CardTypeOre, 1, 1.5)
-					}
-				}
+		if ai.g.Mode == entities.CitiesAndKnights {
+			if i >= int(entities.CardTypePaper) {
+				score -= float64(lose[i])
+				score += float64(2 * gain[i])
 			}
+		}
+	}
 
-			if len(cityLocs) > 0 && p.BuildablesLeft[entities.BTCity] > 0 {
-				scoreType(entities.CardTypeWheat, 2, 1)
-				scoreType(entities.CardTypeOre, 3, 1)
-			}
+	return score
+}
 
-			if len(settlementLocs) > 0 && p.BuildablesLeft[entities.BTSettlement] > 0 {
-				scoreType(entities.CardTypeWood, 1, 1)
-				scoreType(entities.CardTypeBrick, 1, 1)
-				scoreType(entities.CardTypeWool, 1, 1)
-				scoreType(entities.CardTypeWheat, 1, 1)
-			}
+// Victory points of another player as far as the bot can tell
+// Part of the development cards are assumed to be victory points
+func (ai *AI) estimateVictoryPoints(p *entities.Player) float64 {
+	vp := float64(ai.g.GetVictoryPoints(p, true))
+	if ai.g.Mode.UsesBaseCards() {
+		vp += 0.2 * float64(p.CurrentHand.GetDevelopmentCardCount())
+	}
+	return vp
+}
 
-			if len(settlementLocs) == 0 {
-				scoreType(entities.CardTypeWood, 1, 1)
-				scoreType(entities.CardTypeBrick, 1, 1)
-			}
+// Check if a player is close to winning and well ahead of everyone else
+func (ai *AI) isRunawayLeader(p *entities.Player) bool {
+	vp := ai.estimateVictoryPoints(p)
+	if vp < float64(ai.g.Settings.VictoryPoints-3) {
+		return false
+	}
 
-			if ai.g.Mode == entities.CitiesAndKnights {
-				if i >= int(entities.CardTypePaper) {
-					score -= float64(lose[i])
-					score += float64(2 * gain[i])
-				}
-			}
+	for _, o := range ai.g.Players {
+		if o != p && ai.estimateVictoryPoints(o) > vp-2 {
+			return false
+		}
+	}
+	return true
+}
+
+// Value a trade must bring before the bot helps the partner with it
+// Negative for partners that are behind, infinite for one about to win
+func (ai *AI) tradePremium(p *entities.Player, partner *entities.Player, partnerGets [9]int) float64 {
+	caution := ai.level(p).LeaderCaution
+	if caution == 0 || partner == p {
+		return 0
+	}
+
+	vp := ai.estimateVictoryPoints(partner)
+	if vp >= float64(ai.g.Settings.VictoryPoints-2) {
+		return math.Inf(1)
+	}
+
+	lead := vp - float64(ai.g.GetVictoryPoints(p, false))
+	if lead <= 0 {
+		return math.Max(lead, -2) * 0.25 * caution
+	}
+
+	// Cards the partner does not produce are likely what it needs
+	premium := lead * 0.5
+	production := ai.getTileScoreMap(partner)
+	for i, q := range partnerGets {
+		if q > 0 && i <= int(entities.CardTypeOre) && production[entities.TileType(i)] == 0 {
+			premium += 0.5 * float64(q)
 		}
+	}
+	return premium * caution
+}
 
+// Score for trading with a partner, positive if it is worth doing
+func (ai *AI) scoreTrade(p *entities.Player, partner *entities.Player, offer *entities.TradeOffer) float64 {
+	score := ai.scoreOffer(p, offer)
+	if score <= 0 || offer.CreatedBy == p.Order {
 		return score
 	}
 
-	// Accept and reject trade offers
-	if ai.g.CurrentPlayer != p {
-		for _, o := range ai.g.CurrentOffers {
-			if o.Acceptances[p.Order] == 0 {
-				if offerScore(o) > 0 {
-					ai.g.AcceptOffer(o.Id, p)
-				} else {
-					ai.g.RejectOffer(o.Id, p)
-				}
-			}
+	partnerGets := offer.Details.Ask
+	if offer.CurrentPlayer == p.Order {
+		partnerGets = offer.Details.Give
+	}
+	return score - ai.tradePremium(p, partner, partnerGets)
+}
+
+// Embargo runaway leaders and lift it once they are caught
+// Only for bot players, players that timed out keep their own choices
+func (ai *AI) updateEmbargos(p *entities.Player) {
+	if p.BotStrategy == "" || ai.level(p).LeaderCaution == 0 {
+		return
+	}
+
+	for _, o := range ai.g.Players {
+		if o != p {
+			p.Embargos[o.Order] = ai.isRunawayLeader(o)
 		}
+	}
+}
 
+func (ai *AI) ShouldAcceptOffer(p *entities.Player, offer *entities.TradeOffer) bool {
+	ai.updateEmbargos(p)
+	if ai.scoreTrade(p, ai.g.Players[offer.CurrentPlayer], offer) <= 0 {
 		return false
 	}
 
-	getLocs()
+	chance := ai.level(p).AcceptChance
+	return chance >= 100 || ai.g.Rand.Intn(100) < chance
+}
+
+func (ai *AI) recalculateBarbarianBad() {
+	if ai.g.GetBarbarianStrength() > ai.g.GetBarbarianKnights() {
+		ai.barbarianBad = 1
+	} else {
+		ai.barbarianBad = -1
+	}
+}
+
+// Take one action on the bot's own turn
+func (ai *AI) Tick(p *entities.Player) bool {
+	ai.updateEmbargos(p)
+
+	cityLocs := p.GetBuildLocationsCity(ai.g.Graph)
+	settlementLocs := p.GetBuildLocationsSettlement(ai.g.Graph, false, false)
+
+	if ai.barbarianBad == 0 {
+		ai.recalculateBarbarianBad()
+	}
 
 	// Current offers to show
 	currentOffers := make([]*entities.TradeOfferDetails, 0)
@@ -380,7 +526,7 @@ func (ai *AI) tickPlayer(p *entities.Player) bool {
 				missingCards += q - int(deck.Quantity)
 			}
 		}
-		if missingCards != 1 && rand.Intn(100) > priority {
+		if missingCards != 1 && ai.g.Rand.Intn(100) > int(float64(priority)*ai.level(p).TradeChance) {
 			return
 		}
 
@@ -396,13 +542,14 @@ func (ai *AI) tickPlayer(p *entities.Player) bool {
 				continue
 			}
 
-			for t, giveDeck := range p.CurrentHand.CardDeckMap {
+			for _, t := range p.CurrentHand.GetCardTypes() {
+				giveDeck := p.CurrentHand.CardDeckMap[t]
 				if giveDeck.Quantity <= 0 {
 					continue
 				}
 
 				if ai.g.Mode == entities.CitiesAndKnights {
-					if t >= entities.CardTypePaper && rand.Intn(10) >= 3 {
+					if t >= entities.CardTypePaper && ai.g.Rand.Intn(10) >= 3 {
 						continue
 					}
 				}
@@ -431,7 +578,7 @@ func (ai *AI) tickPlayer(p *entities.Player) bool {
 		// iterate over currentOffers
 		for len(currentOffers) > 0 && len(ai.g.CurrentOffers) < 4 {
 			// get a random offer from currentoffers and remove
-			oid := rand.Intn(len(currentOffers))
+			oid := ai.g.Rand.Intn(len(currentOffers))
 			offer := currentOffers[oid]
 
 			// remove oid-th element from currentOffers
@@ -464,11 +611,11 @@ func (ai *AI) tickPlayer(p *entities.Player) bool {
 		if p.CurrentHand.HasResources(0, 0, 0, 1, 0) {
 			locs := p.GetActivateLocationsKnight(ai.g.Graph)
 			if len(locs) > 0 {
-				loc := locs[rand.Intn(len(locs))]
-				if err := ai.g.ai.g.ActivateKnight(p, loc.C); err != nil {
+				loc := ai.g.randomVertex(locs)
+				if err := ai.g.ActivateKnight(p, loc.C); err != nil {
 					log.Println("[BUG]: bot failed to activate knight", err)
 				}
-				recalculateBarbarianBad()
+				ai.recalculateBarbarianBad()
 				return true
 			}
 		}
@@ -477,7 +624,7 @@ func (ai *AI) tickPlayer(p *entities.Player) bool {
 		if !ai.noBuyDevCard && p.CanBuild(entities.BTKnight1) == nil {
 			if len(settlementLocs) > 0 || len(cityLocs) > 0 {
 				// Save the cards to build settlement/city
-				if rand.Intn(8) >= 3 &&
+				if ai.g.Rand.Intn(8) >= 3 &&
 					p.CurrentHand.GetCardCount() < ai.g.GetDiscardLimit(p) &&
 					(ai.barbarianBad != 1 || p.HasInactiveKnight()) {
 					ai.noBuyDevCard = true
@@ -487,7 +634,7 @@ func (ai *AI) tickPlayer(p *entities.Player) bool {
 
 			locs := p.GetBuildLocationsKnight(ai.g.Graph, true)
 			if len(locs) > 0 {
-				loc := locs[rand.Intn(len(locs))]
+				loc := ai.g.randomVertex(locs)
 
 				// Try to find a place where settlement cant be built
 				settlementLocsMap := make(map[*entities.Vertex]bool)
@@ -501,7 +648,7 @@ func (ai *AI) tickPlayer(p *entities.Player) bool {
 					}
 				}
 				if len(nonIntrusiveLocs) > 0 {
-					loc = nonIntrusiveLocs[rand.Intn(len(nonIntrusiveLocs))]
+					loc = ai.g.randomVertex(nonIntrusiveLocs)
 				}
 
 				if err := ai.g.BuildKnight(p, loc.C); err != nil {
@@ -526,12 +673,16 @@ func (ai *AI) tickPlayer(p *entities.Player) bool {
 				if err != nil {
 					log.Println("[BUG]: bot failed to chase away robber")
 				}
-				recalculateBarbarianBad()
+				ai.recalculateBarbarianBad()
 				return true
 			}
 		}
 	}
 
+	if ai.tickTraders(p) {
+		return true
+	}
+
 	if len(cityLocs) > 0 && p.CanBuild(entities.BTCity) == nil {
 		vertex := ai.ChooseBestVertexSettlement(p, cityLocs)
 		if err := ai.g.BuildCity(p, vertex.C); err != nil {
@@ -553,7 +704,7 @@ func (ai *AI) tickPlayer(p *entities.Player) bool {
 	// Trade
 	tradeCheck := func(bank bool) bool {
 		ai.numTradeCheck++
-		if !bank && ai.numTradeCheck > 4 {
+		if !bank && ai.numTradeCheck > ai.level(p).TradeChecks {
 			return false
 		}
 
@@ -572,14 +723,17 @@ func (ai *AI) tickPlayer(p *entities.Player) bool {
 			}
 
 			if len(cityLocs) > 0 && p.BuildablesLeft[entities.BTCity] > 0 {
-				convergeHand([9]int{0, 0, 0, 0, 2, 3, 0, 0, 0}, bank, 30)
+				convergeHand(costHand(p.Rules.GetCost(entities.BTCity)), bank, 30)
 			}
 			if len(settlementLocs) > 0 && p.BuildablesLeft[entities.BTSettlement] > 0 {
-				convergeHand([9]int{0, 1, 1, 1, 1, 0, 0, 0, 0}, bank, 20)
+				convergeHand(costHand(p.Rules.GetCost(entities.BTSettlement)), bank, 20)
 
 			}
 			if p.BuildablesLeft[entities.BTRoad] > 0 {
-				convergeHand([9]int{0, 1, 1, 0, 0, 0, 0, 0, 0}, bank, 10)
+				convergeHand(costHand(p.Rules.GetCost(entities.BTRoad)), bank, 10)
+			}
+			if ai.g.Mode == entities.Seafarers && p.BuildablesLeft[entities.BTShip] > 0 {
+				convergeHand(costHand(p.Rules.GetCost(entities.BTShip)), bank, 10)
 			}
 
 			if executeHand(bank) {
@@ -618,8 +772,18 @@ func (ai *AI) tickPlayer(p *entities.Player) bool {
 				}
 
 				if len(acceptors) > 0 {
-					aorder := acceptors[rand.Intn(len(acceptors))]
-					if offerScore(o) > 0 {
+					aorder := acceptors[ai.g.Rand.Intn(len(acceptors))]
+
+					// Prefer the partner that is furthest behind
+					if ai.level(p).LeaderCaution > 0 {
+						for _, i := range acceptors {
+							if ai.estimateVictoryPoints(ai.g.Players[i]) < ai.estimateVictoryPoints(ai.g.Players[aorder]) {
+								aorder = i
+							}
+						}
+					}
+
+					if ai.scoreTrade(p, ai.g.Players[aorder], o) > 0 {
 						err := ai.g.CloseOffer(o.Id, p, uint16(aorder))
 						if err == nil {
 							ai.tradeTime = 6
@@ -643,13 +807,13 @@ func (ai *AI) tickPlayer(p *entities.Player) bool {
 	if !ai.noBuildRoad && p.CanBuild(entities.BTRoad) == nil {
 		if len(settlementLocs) > 0 {
 			// Save the cards to build settlement
-			if rand.Intn(10) >= 2 && p.CurrentHand.GetCardCount() < ai.g.GetDiscardLimit(p)+2 {
+			if ai.g.Rand.Intn(10) >= 2 && p.CurrentHand.GetCardCount() < ai.g.GetDiscardLimit(p)+2 {
 				ai.noBuildRoad = true
 				return true
 			}
 		}
 
-		edges := p.GetBuildLocationsRoad(ai.g.Graph, false)
+		edges := ai.g.GetRoadLocations(p)
 		if len(edges) > 0 {
 			edge := ai.ChooseBestEdgeRoad(p, edges)
 			if err := ai.g.BuildRoad(p, edge.C); err != nil {
@@ -660,10 +824,28 @@ func (ai *AI) tickPlayer(p *entities.Player) bool {
 		}
 	}
 
-	if ai.g.Mode == entities.Base && !ai.noBuyDevCard {
+	if ai.g.Mode == entities.Seafarers && !ai.noBuildShip && p.CanBuild(entities.BTShip) == nil {
+		// Sail only when there is nothing left to settle on land
+		if len(settlementLocs) > 0 && ai.g.Rand.Intn(10) >= 2 {
+			ai.noBuildShip = true
+			return true
+		}
+
+		edges := p.GetBuildLocationsShip(ai.g.Graph, ai.g.Pirate.Tile)
+		if len(edges) > 0 {
+			edge := ai.ChooseBestEdgeRoad(p, edges)
+			if err := ai.g.BuildShip(p, edge.C); err != nil {
+				log.Println("[BUG] Bot failed to build ship", err)
+				return false
+			}
+			return true
+		}
+	}
+
+	if ai.g.Mode.UsesBaseCards() && !ai.noBuyDevCard {
 		if len(settlementLocs) > 0 || len(cityLocs) > 0 {
 			// Save the cards to build settlement/city
-			if rand.Intn(8) >= 3 && p.CurrentHand.GetCardCount() < ai.g.GetDiscardLimit(p) {
+			if ai.g.Rand.Intn(8) >= 3 && p.CurrentHand.GetCardCount() < ai.g.GetDiscardLimit(p) {
 				ai.noBuyDevCard = true
 				return true
 			}
@@ -676,7 +858,8 @@ func (ai *AI) tickPlayer(p *entities.Player) bool {
 	}
 
 	devCards := make([]entities.DevelopmentCardType, 0)
-	for t, deck := range p.CurrentHand.DevelopmentCardDeckMap {
+	for _, t := range p.CurrentHand.GetDevelopmentCardTypes() {
+		deck := p.CurrentHand.DevelopmentCardDeckMap[t]
 		if deck.CanUse && !ai.failedDev[t] {
 			for i := 0; i < int(deck.Quantity); i++ {
 				devCards = append(devCards, t)
@@ -685,7 +868,7 @@ func (ai *AI) tickPlayer(p *entities.Player) bool {
 	}
 
 	if len(devCards) > 0 {
-		dc := devCards[rand.Intn(len(devCards))]
+		dc := devCards[ai.g.Rand.Intn(len(devCards))]
 		err := ai.g.UseDevelopmentCard(p, dc)
 		if err != nil {
 			ai.failedDev[dc] = true
@@ -696,7 +879,7 @@ func (ai *AI) tickPlayer(p *entities.Player) bool {
 	if ai.g.Mode == entities.CitiesAndKnights && !ai.noBuildWall && p.CanBuild(entities.BTWall) == nil {
 		if len(settlementLocs) > 0 {
 			// Save the cards to build settlement
-			if rand.Intn(8) >= 3 && p.CurrentHand.GetCardCount() < ai.g.GetDiscardLimit(p) {
+			if ai.g.Rand.Intn(8) >= 3 && p.CurrentHand.GetCardCount() < ai.g.GetDiscardLimit(p) {
 				ai.noBuildWall = true
 				return true
 			}
@@ -704,7 +887,7 @@ func (ai *AI) tickPlayer(p *entities.Player) bool {
 
 		vertices := p.GetBuildLocationsWall(ai.g.Graph)
 		if len(vertices) > 0 {
-			v := vertices[rand.Intn(len(vertices))]
+			v := ai.g.randomVertex(vertices)
 			if err := ai.g.BuildWall(p, v.C); err != nil {
 				log.Println("[BUG] Bot failed to build wall", err)
 				return false
@@ -716,26 +899,10 @@ func (ai *AI) tickPlayer(p *entities.Player) bool {
 	return false
 }
 
-func (ai *AI) Tick() bool {
-	if !ai.g.Initialized ||
-		ai.g.GameOver ||
-		ai.g.DiceState == 0 ||
-		ai.g.InitPhase {
-		return false
-	}
-
-	acted := false
-	for _, p := range ai.g.Players {
-		if p.GetIsBot() {
-			acted = ai.tickPlayer(p) || acted
-		}
-	}
-	return acted
-}
-
 func (ai *AI) Reset() {
 	ai.noBuildRoad = false
 	ai.noBuildWall = false
+	ai.noBuildShip = false
 	ai.tradeTime = 6
 	ai.tradeChecked = false
 	ai.numTradeCheck = 0
@@ -744,3 +911,10 @@ func (ai *AI) Reset() {
 	ai.barbarianBad = 0
 	ai.failedDev = make(map[entities.DevelopmentCardType]bool)
 }
+
+// Hand to aim for to pay a cost
+func costHand(cost []int) [9]int {
+	var hand [9]int
+	copy(hand[entities.CardTypeWood:], cost)
+	return hand
+}
@@ -18,7 +18,8 @@ func (ai *AI) ChooseMerchantLocation(p *entities.Player, allowed []*entities.Til
 func (ai *AI) ChooseMerchantFleet(p *entities.Player) [9]int {
 	res := entities.CardTypeWood
 	maxCards := 0
-	for _, deck := range p.CurrentHand.CardDeckMap {
+	for _, t := range p.CurrentHand.GetCardTypes() {
+		deck := p.CurrentHand.CardDeckMap[t]
 		if deck != nil && int(deck.Quantity) > maxCards {
 			maxCards = int(deck.Quantity)
 			res = deck.Type
@@ -29,3 +30,23 @@ func (ai *AI) ChooseMerchantFleet(p *entities.Player) [9]int {
 	ans[res] = 1
 	return ans
 }
+
+// Take the resource that is scarcest in the bank
+func (ai *AI) ChooseMonopolyResource(p *entities.Player) entities.CardType {
+	res := entities.CardTypeWood
+	minCount := 999
+	for _, t := range []entities.CardType{
+		entities.CardTypeWood,
+		entities.CardTypeBrick,
+		entities.CardTypeWool,
+		entities.CardTypeWheat,
+		entities.CardTypeOre,
+	} {
+		deck := ai.g.Bank.Hand.GetCardDeck(t)
+		if deck.Quantity < int16(minCount) {
+			res = deck.Type
+			minCount = int(deck.Quantity)
+		}
+	}
+	return res
+}
@@ -0,0 +1,93 @@
+package game
+
+import (
+	"imperials/entities"
+	"log"
+)
+
+// Spend fish and gold and fight the barbarians
+// Returns true if the bot did something
+func (ai *AI) tickTraders(p *entities.Player) bool {
+	if ai.g.Mode.HasFish() {
+		// Save up for a card, unless the robber sits on the bot
+		if ai.g.CanUseFish(p, entities.FishCard) == nil {
+			if err := ai.g.UseFish(p, entities.FishCard); err != nil {
+				log.Println("[BUG] Bot failed to use fish", err)
+				return false
+			}
+			return true
+		}
+
+		if ai.isRobberOnMe(p) && ai.g.CanUseFish(p, entities.FishRobber) == nil {
+			if err := ai.g.UseFish(p, entities.FishRobber); err != nil {
+				log.Println("[BUG] Bot failed to use fish", err)
+				return false
+			}
+			return true
+		}
+	}
+
+	// Keep enough gold to stay out of the poorest spot
+	if ai.g.Mode.HasRivers() && p.Gold >= 2*entities.GoldPerResource && ai.g.CanSpendGold(p) == nil {
+		if ct := ai.chooseNeededResource(p); ct != 0 {
+			if err := ai.g.SpendGold(p, ct); err != nil {
+				log.Println("[BUG] Bot failed to spend gold", err)
+				return false
+			}
+			return true
+		}
+	}
+
+	// Send warriors where the barbarians are
+	if ai.g.Mode.HasBarbarianAttack() && p.CanBuild(entities.BTKnight1) == nil {
+		var best *entities.Vertex
+		bestCount := 0
+		for _, v := range p.GetBuildLocationsKnight(ai.g.Graph, false) {
+			count := 0
+			for _, t := range v.AdjacentTiles {
+				count += ai.g.Barbarians[t.Center]
+			}
+			if count > bestCount {
+				best = v
+				bestCount = count
+			}
+		}
+
+		if best != nil {
+			if err := ai.g.BuildKnight(p, best.C); err != nil {
+				log.Println("[BUG] Bot failed to build warrior", err)
+				return false
+			}
+			return true
+		}
+	}
+
+	return false
+}
+
+func (ai *AI) isRobberOnMe(p *entities.Player) bool {
+	for _, vp := range ai.g.Graph.GetTilePlacements(ai.g.Robber.Tile) {
+		if vp.GetOwner() == p && (vp.GetType() == entities.BTCity || vp.GetType() == entities.BTSettlement) {
+			return true
+		}
+	}
+	return false
+}
+
+// Resource the bot has the least of that the bank can give
+// Returns 0 if the bank is empty
+func (ai *AI) chooseNeededResource(p *entities.Player) entities.CardType {
+	var res entities.CardType
+	minCount := int16(999)
+	for t := entities.CardTypeWood; t <= entities.CardTypeOre; t++ {
+		if ai.g.Bank.Hand.GetCardDeck(t).Quantity <= 0 {
+			continue
+		}
+
+		if q := p.CurrentHand.GetCardDeck(t).Quantity; q < minCount {
+			res = t
+			minCount = q
+		}
+	}
+	return res
+}
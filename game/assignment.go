@@ -2,20 +2,28 @@ package game
 
 import (
 	"imperials/entities"
-	"math/rand"
-	"reflect"
-	"time"
+	"sort"
 )
 
+// Get tile coordinates of a set in a stable order
+func sortedCoordinates(tiles map[entities.Coordinate]*entities.Tile) []entities.Coordinate {
+	coords := make([]entities.Coordinate, 0, len(tiles))
+	for c := range tiles {
+		coords = append(coords, c)
+	}
+	sort.Slice(coords, func(i, j int) bool { return coords[i].Less(coords[j]) })
+	return coords
+}
+
 func (g *Game) assignTileTypes(types []entities.TileType) {
-	rand.Seed(time.Now().UnixNano())
 	for i := range types {
-		j := rand.Intn(i + 1)
+		j := g.Rand.Intn(i + 1)
 		types[i], types[j] = types[j], types[i]
 	}
 
 	i := 0
-	for _, t := range g.Tiles {
+	for _, c := range sortedCoordinates(g.Tiles) {
+		t := g.Tiles[c]
 		if t.Type == entities.TileTypeRandom || t.Type == entities.TileTypeFog {
 			if len(types) > i {
 				t.Type = types[i]
@@ -28,8 +36,6 @@ func (g *Game) assignTileTypes(types []entities.TileType) {
 }
 
 func (g *Game) assignNumbers(allNumbers []uint16) {
-	rand.Seed(time.Now().UnixNano())
-
 	redNumbers := make([]uint16, 0)
 	whiteNumbers := make([]uint16, 0)
 	for _, num := range allNumbers {
@@ -42,12 +48,22 @@ func (g *Game) assignNumbers(allNumbers []uint16) {
 
 	tileCoords := make(map[entities.Coordinate]*entities.Tile)
 	allTileCoords := make(map[entities.Coordinate]*entities.Tile)
-	for _, t := range g.Tiles {
-		if t.Type != entities.TileTypeDesert && t.Type != entities.TileTypeSea {
+	for _, c := range sortedCoordinates(g.Tiles) {
+		t := g.Tiles[c]
+		if t.Number != 0 {
+			// Numbers fixed by the map are only written
+			g.j.WSetTileType(t)
+		} else if t.Type != entities.TileTypeDesert && t.Type != entities.TileTypeSea && t.Type != entities.TileTypeLake {
 			tileCoords[t.Center] = t
 			allTileCoords[t.Center] = t
 		} else {
-			if !t.Fog {
+			// Tiles without a number are deserts unless written
+			if t.Type == entities.TileTypeSea || t.Type == entities.TileTypeLake {
+				g.j.WSetTileType(t)
+			}
+
+			// The sea belongs to the pirate in seafarers
+			if !t.Fog && (g.Mode != entities.Seafarers || t.Type != entities.TileTypeSea) {
 				g.Robber.Move(t)
 				g.j.WSetRobber(t)
 			}
@@ -55,7 +71,8 @@ func (g *Game) assignNumbers(allNumbers []uint16) {
 	}
 
 	if g.Robber.Tile == nil {
-		for _, t := range g.Tiles {
+		for _, c := range sortedCoordinates(g.Tiles) {
+			t := g.Tiles[c]
 			if !t.Fog {
 				g.Robber.Move(t)
 				g.j.WSetRobber(t)
@@ -70,8 +87,8 @@ func (g *Game) assignNumbers(allNumbers []uint16) {
 			continue
 		}
 
-		coords := reflect.ValueOf(tileCoords).MapKeys()
-		C := coords[rand.Intn(len(tileCoords))].Interface().(entities.Coordinate)
+		coords := sortedCoordinates(tileCoords)
+		C := coords[g.Rand.Intn(len(coords))]
 		tileCoords[C].Number = num
 		g.j.WSetTileType(tileCoords[C])
 		delete(tileCoords, C)
@@ -89,8 +106,8 @@ func (g *Game) assignNumbers(allNumbers []uint16) {
 			break
 		}
 
-		coords := reflect.ValueOf(allTileCoords).MapKeys()
-		C := coords[rand.Intn(len(allTileCoords))].Interface().(entities.Coordinate)
+		coords := sortedCoordinates(allTileCoords)
+		C := coords[g.Rand.Intn(len(coords))]
 		allTileCoords[C].Number = num
 		g.j.WSetTileType(allTileCoords[C])
 		delete(allTileCoords, C)
@@ -0,0 +1,76 @@
+package game
+
+import (
+	"imperials/entities"
+)
+
+const (
+	// Weight of the sum rolled last with dampened repeats
+	BalancedDiceRepeatWeight = 0.5
+
+	// Weight of a 7 for the player in the lead
+	BalancedDiceLeaderWeight = 0.5
+)
+
+func (g *Game) isBalancedDice() bool {
+	rules := g.rules()
+	return rules != nil && rules.BalancedDice
+}
+
+// The deck is created on the first roll so that replays
+// build the same deck from the journaled rolls
+func (g *Game) getDiceDeck() *entities.DiceDeck {
+	if g.DiceStats.Deck == nil {
+		g.DiceStats.Deck = entities.NewDiceDeck(g.rules().GetBalancedDiceReshuffle())
+	}
+	return g.DiceStats.Deck
+}
+
+// Roll the dice for the current player
+// The rolled outcome is taken out of the deck by RollDiceWith
+func (g *Game) drawDice(noSeven bool) (int, int) {
+	if g.isBalancedDice() {
+		rules := g.rules()
+		lastSum := g.LastRollRed + g.LastRollWhite
+		leader := rules.BalancedDiceLeaderPenalty && g.isLeader(g.CurrentPlayer)
+
+		red, white, ok := g.getDiceDeck().Draw(g.Rand, func(sum int) float64 {
+			if sum == 7 && noSeven {
+				return 0
+			}
+
+			weight := 1.0
+			if rules.BalancedDiceDampenRepeats && g.numRolls() > 0 && sum == lastSum {
+				weight *= BalancedDiceRepeatWeight
+			}
+			if leader && sum == 7 {
+				weight *= BalancedDiceLeaderWeight
+			}
+			return weight
+		})
+		if ok {
+			return red, white
+		}
+	}
+
+	return g.Rand.Intn(6) + 1, g.Rand.Intn(6) + 1
+}
+
+// Player has more public victory points than everyone else
+func (g *Game) isLeader(p *entities.Player) bool {
+	vp := g.GetVictoryPoints(p, true)
+	for _, o := range g.Players {
+		if o != p && g.GetVictoryPoints(o, true) >= vp {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *Game) numRolls() int {
+	rolls := 0
+	for _, r := range g.DiceStats.Rolls {
+		rolls += r
+	}
+	return rolls
+}
@@ -0,0 +1,86 @@
+package game
+
+import (
+	"imperials/entities"
+	"imperials/memstore"
+	"reflect"
+	"testing"
+)
+
+func balancedDiceRules() *entities.AdvancedSettings {
+	return &entities.AdvancedSettings{
+		BalancedDice:              true,
+		BalancedDiceDampenRepeats: true,
+		BalancedDiceLeaderPenalty: true,
+	}
+}
+
+func TestBalancedDiceDeckFollowsRolls(t *testing.T) {
+	for _, turns := range []int{1, 10, 40} {
+		g := newTestGame(t, memstore.NewMemStore(), "balanced-rolls", testSettings(entities.Base, 41), balancedDiceRules(), 4)
+		playTestTurns(t, g, turns)
+
+		d := g.DiceStats.Deck
+		if d == nil {
+			t.Fatalf("%d turns: no dice deck", turns)
+		}
+
+		// Every roll since the last shuffle is out of the deck
+		rolls := g.numRolls()
+		draws := rolls - d.Reshuffles*entities.DefaultBalancedDiceReshuffle
+		if d.Draws != draws || len(d.Cards) < 36-draws {
+			t.Errorf("%d turns: %d draws and %d cards after %d rolls", turns, d.Draws, len(d.Cards), rolls)
+		}
+	}
+}
+
+func TestBalancedDiceReplay(t *testing.T) {
+	for _, turns := range []int{1, 10, 40} {
+		store := memstore.NewMemStore()
+		g := newTestGame(t, store, "balanced-replay", testSettings(entities.Base, 42), balancedDiceRules(), 4)
+		playTestTurns(t, g, turns)
+
+		r := replayTestGame(t, store, "balanced-replay", g.Settings, 4)
+
+		if !reflect.DeepEqual(r.DiceStats.Deck, g.DiceStats.Deck) {
+			t.Fatalf("%d turns: replayed dice deck differs", turns)
+		}
+
+		// The replay rolls on like the game would
+		g.DiceState, r.DiceState = 0, 0
+		for i := 0; i < 5; i++ {
+			gr, gw := g.drawDice(false)
+			rr, rw := r.drawDice(false)
+			if gr != rr || gw != rw {
+				t.Fatalf("%d turns: roll %d is %d,%d live and %d,%d replayed", turns, i, gr, gw, rr, rw)
+			}
+		}
+	}
+}
+
+func TestBalancedDiceNoSeven(t *testing.T) {
+	g := newTestGame(t, memstore.NewMemStore(), "balanced-seven", testSettings(entities.Base, 43), balancedDiceRules(), 4)
+	playTestTurns(t, g, 2)
+
+	for i := 0; i < 200; i++ {
+		if red, white := g.drawDice(true); red+white == 7 {
+			t.Fatal("rolled a seven that was put aside")
+		}
+	}
+}
+
+// Undo replays the journal, the deck must come back as it was
+func TestBalancedDiceUndo(t *testing.T) {
+	g, p := newUndoTestGame(t, "balanced-undo", balancedDiceRules())
+	deck := *g.DiceStats.Deck
+	deck.Cards = append([]int{}, deck.Cards...)
+
+	buildTestRoad(t, g, p, nil)
+	if err := g.Undo(p); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(*g.DiceStats.Deck, deck) {
+		t.Fatal("dice deck changed with undo")
+	}
+}
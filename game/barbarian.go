@@ -93,7 +93,7 @@ func (g *Game) MoveBarbarian() {
 			// Victory
 			if len(maxKnightPlayers) > 1 {
 				if !g.j.playing {
-					go g.BarbarianDistributeProgressCards(maxKnightPlayers)
+					g.goAsync(func() { g.BarbarianDistributeProgressCards(maxKnightPlayers) })
 				}
 			} else if len(maxKnightPlayers) == 1 {
 				// Give victory point to maxKnightPlayers[0]
@@ -109,7 +109,7 @@ func (g *Game) MoveBarbarian() {
 		} else {
 			// Defeat
 			if !g.j.playing && len(minKnightPlayers) > 0 {
-				go g.BarbarianDestruction(minKnightPlayers)
+				g.goAsync(func() { g.BarbarianDestruction(minKnightPlayers) })
 			}
 		}
 	}
@@ -203,7 +203,8 @@ func (g *Game) BarbarianDestruction(players []*entities.Player) {
 		wg.Add(1)
 		p.ClearExpect()
 
-		go func(wg *sync.WaitGroup, p *entities.Player) {
+		p := p
+		g.fork(func() {
 			defer wg.Done()
 
 			defer g.Unlock()
@@ -261,7 +262,7 @@ func (g *Game) BarbarianDestruction(players []*entities.Player) {
 
 			g.SendPlayerSecret(p)
 			g.BroadcastState()
-		}(&wg, p)
+		})
 	}
 
 	g.TickerPause = true
@@ -0,0 +1,186 @@
+package game
+
+import (
+	"errors"
+	"imperials/entities"
+	"sort"
+)
+
+type (
+	// Decisions a bot makes during the game
+	// One instance is created for each bot player
+	BotStrategy interface {
+		// Take an action during the bot's own turn
+		// Returns false when the bot is done with its turn
+		Tick(p *entities.Player) bool
+
+		// Called at the end of every turn
+		Reset()
+
+		// Placement of settlements, cities and roads
+		ChooseBestVertexSettlement(p *entities.Player, allowed []*entities.Vertex) *entities.Vertex
+		ChooseBestEdgeRoad(p *entities.Player, allowed []*entities.Edge) *entities.Edge
+
+		// Robber position and the player to steal from
+		GetRobberTile(p *entities.Player, allowed []*entities.Tile) *entities.Tile
+		ChooseRobberVictim(p *entities.Player, choices []*entities.Player) *entities.Player
+
+		// Cards to discard on a 7
+		ChooseDiscard(p *entities.Player, quantity int) [9]int
+
+		// Respond to an offer made by another player
+		ShouldAcceptOffer(p *entities.Player, offer *entities.TradeOffer) bool
+
+		// Resource to steal with monopoly
+		ChooseMonopolyResource(p *entities.Player) entities.CardType
+
+		// Merchant and merchant fleet progress cards
+		ChooseMerchantLocation(p *entities.Player, allowed []*entities.Tile) *entities.Tile
+		ChooseMerchantFleet(p *entities.Player) [9]int
+	}
+
+	// Creates a strategy for one bot in a game
+	BotStrategyConstructor func(g *Game) BotStrategy
+)
+
+const (
+	BotStrategyDefault = "default"
+	BotStrategyRandom  = "random"
+	BotStrategyMCTS    = "mcts"
+)
+
+var botStrategies = map[string]BotStrategyConstructor{
+	BotStrategyDefault: func(g *Game) BotStrategy { return NewAI(g) },
+	BotStrategyRandom:  func(g *Game) BotStrategy { return NewRandomBot(g) },
+	BotStrategyMCTS:    func(g *Game) BotStrategy { return NewMCTSBot(g) },
+}
+
+// Make a strategy available to the lobby and simulator
+// Must be called before any game starts, e.g. from an init function
+func RegisterBotStrategy(name string, constructor BotStrategyConstructor) {
+	botStrategies[name] = constructor
+}
+
+// Get names of all registered strategies in alphabetical order
+func GetBotStrategyNames() []string {
+	names := make([]string, 0, len(botStrategies))
+	for name := range botStrategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func IsValidBotStrategy(name string) bool {
+	_, ok := botStrategies[name]
+	return ok
+}
+
+// Set the strategy used when this player is a bot
+func (g *Game) SetBotStrategy(p *entities.Player, name string) error {
+	if name == "" {
+		name = BotStrategyDefault
+	}
+
+	if !IsValidBotStrategy(name) {
+		return errors.New("unknown bot strategy " + name)
+	}
+
+	p.BotStrategy = name
+	g.j.WSetBotStrategy(p, name)
+
+	if g.bots != nil {
+		g.bots[p.Order] = nil
+	}
+	return nil
+}
+
+// Set how well this player plays when it is a bot
+func (g *Game) SetBotDifficulty(p *entities.Player, difficulty string) error {
+	if difficulty == "" {
+		difficulty = entities.BotDifficultyMedium
+	}
+
+	if !entities.IsValidBotDifficulty(difficulty) {
+		return errors.New("unknown bot difficulty " + difficulty)
+	}
+
+	p.BotDifficulty = difficulty
+	g.j.WSetBotDifficulty(p, difficulty)
+	return nil
+}
+
+// Get the strategy for a player, creating it if needed
+// Also used for players that timed out
+func (g *Game) botStrategy(p *entities.Player) BotStrategy {
+	if g.bots == nil {
+		g.bots = make([]BotStrategy, len(g.Players))
+	}
+
+	if g.bots[p.Order] == nil {
+		constructor := botStrategies[p.BotStrategy]
+		if constructor == nil {
+			constructor = botStrategies[BotStrategyDefault]
+		}
+		g.bots[p.Order] = constructor(g)
+		g.bots[p.Order].Reset()
+	}
+
+	return g.bots[p.Order]
+}
+
+func (g *Game) tickBots() bool {
+	if !g.Initialized ||
+		g.GameOver ||
+		g.DiceState == 0 ||
+		g.InitPhase {
+		return false
+	}
+
+	acted := false
+	for _, p := range g.Players {
+		if p.GetIsBot() {
+			acted = g.tickBot(p) || acted
+		}
+	}
+	return acted
+}
+
+func (g *Game) tickBot(p *entities.Player) bool {
+	// Let the game fall back to the strategy's choice
+	if p.PendingAction != nil {
+		p.SendExpect(nil)
+		return true
+	}
+
+	if g.HasPlayerPendingAction() {
+		return true
+	}
+
+	s := g.botStrategy(p)
+
+	// Accept and reject trade offers
+	if g.CurrentPlayer != p {
+		for _, o := range g.CurrentOffers {
+			if o.Acceptances[p.Order] == 0 {
+				if s.ShouldAcceptOffer(p, o) {
+					g.AcceptOffer(o.Id, p)
+				} else {
+					g.RejectOffer(o.Id, p)
+				}
+			}
+		}
+
+		return false
+	}
+
+	return s.Tick(p)
+}
+
+func (g *Game) resetBots() {
+	for _, s := range g.bots {
+		if s != nil {
+			s.Reset()
+		}
+	}
+}
@@ -0,0 +1,513 @@
+package game
+
+import (
+	"imperials/entities"
+	"log"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Picks each action of its turn with a Monte Carlo search over
+// clones of the game, playing the rest out with the default AI.
+// Hidden cards are re-dealt from public information in each playout.
+// Decisions outside its own turn are made by the default AI.
+type MCTSBot struct {
+	*AI
+
+	rand   *rand.Rand
+	failed map[mctsMove]bool
+
+	// A search is running without the game lock
+	searching bool
+}
+
+// Search budget of the MCTS bot for a bot difficulty
+type MCTSLevel struct {
+	Rollouts    int           // Playouts for each action
+	MaxRollouts int           // Playouts of all actions together
+	MoveTime    time.Duration // Time limit for each action, ignored when headless to keep simulations reproducible
+	Rounds      int           // Rounds played out before evaluating
+}
+
+var MCTSLevels = map[string]MCTSLevel{
+	entities.BotDifficultyEasy:   {Rollouts: 16, MaxRollouts: 128, MoveTime: 250 * time.Millisecond, Rounds: 1},
+	entities.BotDifficultyMedium: {Rollouts: 48, MaxRollouts: 384, MoveTime: time.Second, Rounds: 1},
+	entities.BotDifficultyHard:   {Rollouts: 128, MaxRollouts: 1024, MoveTime: 2 * time.Second, Rounds: 2},
+}
+
+const (
+	mctsMoveEndTurn = iota
+	mctsMoveCity
+	mctsMoveSettlement
+	mctsMoveRoad
+	mctsMoveDevCard
+	mctsMoveUseDevCard
+	mctsMoveImprovement
+	mctsMoveKnight
+	mctsMoveActivateKnight
+	mctsMoveBankTrade
+)
+
+// An action the bot can take in its turn
+type mctsMove struct {
+	Type int
+	C    entities.Coordinate
+	E    entities.EdgeCoordinate
+	Card int
+	Give entities.CardType
+	Ask  entities.CardType
+}
+
+type mctsNode struct {
+	move   mctsMove
+	visits int
+	reward float64
+}
+
+func NewMCTSBot(g *Game) *MCTSBot {
+	return &MCTSBot{
+		AI:   NewAI(g),
+		rand: rand.New(rand.NewSource(g.Rand.Int63())),
+	}
+}
+
+func (m *MCTSBot) searchLevel(p *entities.Player) MCTSLevel {
+	if l, ok := MCTSLevels[p.BotDifficulty]; ok {
+		return l
+	}
+	return MCTSLevels[entities.BotDifficultyMedium]
+}
+
+func (m *MCTSBot) Tick(p *entities.Player) bool {
+	// Trades are not allowed in the special build phase
+	if m.g.SpecialBuildPhase {
+		return m.AI.Tick(p)
+	}
+
+	// The search of an earlier tick is not done yet
+	if m.searching {
+		return true
+	}
+
+	moves := m.getMoves(p)
+	if len(moves) == 1 {
+		return false
+	}
+
+	snapshot, err := m.g.Clone()
+	if err != nil {
+		log.Println("MCTS bot failed to clone game", err)
+		return false
+	}
+
+	// Let players and ticks in while searching the snapshot
+	index := m.g.j.index
+	m.searching = true
+	m.g.Unlock()
+	move := m.search(snapshot, p.Order, m.searchLevel(p), moves)
+	m.g.Lock()
+	m.searching = false
+
+	// The game went on without the bot, search again on the next tick
+	if !m.g.Initialized || m.g.j.index != index ||
+		m.g.CurrentPlayer != p || !p.GetIsBot() || m.g.HasPlayerPendingAction() {
+		return true
+	}
+
+	if move.Type == mctsMoveEndTurn {
+		return false
+	}
+
+	if err := m.apply(m.g, p, move); err != nil {
+		log.Println("MCTS bot failed to play move", err)
+		m.failed[move] = true
+	}
+	return true
+}
+
+func (m *MCTSBot) Reset() {
+	m.AI.Reset()
+	m.failed = make(map[mctsMove]bool)
+}
+
+// Get all useful actions, ending the turn is always the first
+func (m *MCTSBot) getMoves(p *entities.Player) []mctsMove {
+	moves := []mctsMove{{Type: mctsMoveEndTurn}}
+	add := func(move mctsMove) {
+		if !m.failed[move] {
+			moves = append(moves, move)
+		}
+	}
+
+	// Locations come from maps, sort them to keep games reproducible
+	sortedVertices := func(vertices []*entities.Vertex) []*entities.Vertex {
+		entities.SortVertices(vertices)
+		return vertices
+	}
+
+	if p.CanBuild(entities.BTCity) == nil {
+		for _, v := range sortedVertices(p.GetBuildLocationsCity(m.g.Graph)) {
+			add(mctsMove{Type: mctsMoveCity, C: v.C})
+		}
+	}
+
+	if p.CanBuild(entities.BTSettlement) == nil {
+		for _, v := range sortedVertices(p.GetBuildLocationsSettlement(m.g.Graph, false, false)) {
+			add(mctsMove{Type: mctsMoveSettlement, C: v.C})
+		}
+	}
+
+	if p.CanBuild(entities.BTRoad) == nil {
+		edges := m.g.GetRoadLocations(p)
+		entities.SortEdges(edges)
+		for _, e := range edges {
+			add(mctsMove{Type: mctsMoveRoad, E: e.C})
+		}
+	}
+
+	if m.g.Mode.UsesBaseCards() && p.CanBuyDevelopmentCard() &&
+		m.g.Bank.DevelopmentCardCursor < len(m.g.Bank.DevelopmentCardOrder[0]) {
+		add(mctsMove{Type: mctsMoveDevCard})
+	}
+
+	for _, t := range p.CurrentHand.GetDevelopmentCardTypes() {
+		deck := p.CurrentHand.DevelopmentCardDeckMap[t]
+		if deck.CanUse && deck.Quantity > 0 && t != entities.DevelopmentCardVictoryPoint {
+			add(mctsMove{Type: mctsMoveUseDevCard, Card: int(t)})
+		}
+	}
+
+	if m.g.Mode == entities.CitiesAndKnights {
+		for _, ct := range [3]entities.CardType{entities.CardTypePaper, entities.CardTypeCloth, entities.CardTypeCoin} {
+			if m.g.CanBuildImprovement(p, ct) == nil {
+				add(mctsMove{Type: mctsMoveImprovement, Card: int(ct)})
+			}
+		}
+
+		if p.CanBuild(entities.BTKnight1) == nil {
+			for _, v := range sortedVertices(p.GetBuildLocationsKnight(m.g.Graph, true)) {
+				add(mctsMove{Type: mctsMoveKnight, C: v.C})
+			}
+		}
+
+		if p.CurrentHand.HasResources(0, 0, 0, 1, 0) {
+			for _, v := range sortedVertices(p.GetActivateLocationsKnight(m.g.Graph)) {
+				add(mctsMove{Type: mctsMoveActivateKnight, C: v.C})
+			}
+		}
+	}
+
+	// Only bank trades that make something new affordable
+	if m.g.CanEndTurn() == nil {
+		ratios := m.g.GetRatiosForPlayer(p)
+		before := m.getAffordable(p)
+		for _, give := range p.CurrentHand.GetCardTypes() {
+			if int(p.CurrentHand.GetCardDeck(give).Quantity) < ratios[give] {
+				continue
+			}
+
+			for ask := entities.CardTypeWood; ask <= entities.CardTypeOre; ask++ {
+				if ask == give || m.g.Bank.Hand.GetCardDeck(ask).Quantity <= 0 {
+					continue
+				}
+
+				p.CurrentHand.UpdateCards(give, -ratios[give])
+				p.CurrentHand.UpdateCards(ask, 1)
+				after := m.getAffordable(p)
+				p.CurrentHand.UpdateCards(ask, -1)
+				p.CurrentHand.UpdateCards(give, ratios[give])
+
+				if after&^before != 0 {
+					add(mctsMove{Type: mctsMoveBankTrade, Give: give, Ask: ask})
+				}
+			}
+		}
+	}
+
+	return moves
+}
+
+// Get a bit for each thing the player can afford
+func (m *MCTSBot) getAffordable(p *entities.Player) int {
+	res := 0
+	for i, bt := range []entities.BuildableType{entities.BTCity, entities.BTSettlement, entities.BTRoad} {
+		if p.CanBuild(bt) == nil {
+			res |= 1 << i
+		}
+	}
+
+	if m.g.Mode.UsesBaseCards() && p.CanBuyDevelopmentCard() {
+		res |= 1 << 3
+	}
+
+	if m.g.Mode == entities.CitiesAndKnights {
+		if p.CanBuild(entities.BTKnight1) == nil {
+			res |= 1 << 4
+		}
+		for i, ct := range [3]entities.CardType{entities.CardTypePaper, entities.CardTypeCloth, entities.CardTypeCoin} {
+			if m.g.CanBuildImprovement(p, ct) == nil {
+				res |= 1 << (5 + i)
+			}
+		}
+	}
+
+	return res
+}
+
+// Run playouts for the moves on copies of a snapshot of the game,
+// choosing which one to try with UCB1
+// The n-th playout of every move uses the same dice and hidden cards
+// so that moves are compared on equal luck
+// Returns the most visited move
+// Does not touch the game, called without the lock
+func (m *MCTSBot) search(snapshot *Game, order uint16, level MCTSLevel, moves []mctsMove) mctsMove {
+	nodes := make([]*mctsNode, len(moves))
+	for i, move := range moves {
+		nodes[i] = &mctsNode{move: move}
+	}
+
+	seeds := make([]int64, 0, level.Rollouts)
+	start := time.Now()
+	total := level.Rollouts * len(moves)
+	if total > level.MaxRollouts {
+		total = level.MaxRollouts
+	}
+	for i := 0; i < total; i++ {
+		if !snapshot.Headless && time.Since(start) > level.MoveTime {
+			break
+		}
+
+		var node *mctsNode
+		best := math.Inf(-1)
+		for _, n := range nodes {
+			if n.visits == 0 {
+				node = n
+				break
+			}
+
+			ucb := n.reward/float64(n.visits) +
+				0.5*math.Sqrt(math.Log(float64(i))/float64(n.visits))
+			if ucb > best {
+				best = ucb
+				node = n
+			}
+		}
+
+		if node.visits == len(seeds) {
+			seeds = append(seeds, m.rand.Int63())
+		}
+		node.reward += m.rollout(snapshot, order, node.move, seeds[node.visits], level.Rounds)
+		node.visits++
+	}
+
+	best := nodes[0]
+	for _, n := range nodes[1:] {
+		if n.visits > best.visits ||
+			(n.visits == best.visits && n.reward > best.reward) {
+			best = n
+		}
+	}
+	return best.move
+}
+
+// Play a move on a copy of the snapshot and a few rounds after it
+// Returns a reward between 0 and 1
+func (m *MCTSBot) rollout(snapshot *Game, order uint16, move mctsMove, seed int64, rounds int) float64 {
+	c := snapshot.copyClone()
+
+	// The real seed would tell the copy the coming rolls
+	c.Settings.Seed = seed
+	c.Rand = entities.NewRand(seed)
+	cp := c.Players[order]
+	m.determinize(c, cp)
+
+	// Play out with the default AI to keep playouts fast
+	for _, op := range c.Players {
+		op.SetIsBot(true)
+		op.BotStrategy = BotStrategyDefault
+	}
+
+	var err error
+	if move.Type == mctsMoveEndTurn {
+		err = c.EndTurn(cp)
+	} else {
+		err = m.apply(c, cp, move)
+	}
+	if err != nil {
+		return 0
+	}
+
+	maxTurns := rounds * len(c.Players)
+	c.Simulate(maxTurns*200, func(turn int) bool {
+		return turn < maxTurns
+	})
+
+	return m.evaluate(c, cp)
+}
+
+// Re-deal cards the player cannot see so the search does not cheat
+// Opponents keep the number of cards they hold
+func (m *MCTSBot) determinize(c *Game, cp *entities.Player) {
+	opponents := make([]*entities.Player, 0)
+	for _, op := range c.Players {
+		if op != cp {
+			opponents = append(opponents, op)
+		}
+	}
+
+	// Resource and commodity cards
+	pool := make([]entities.CardType, 0)
+	for _, op := range opponents {
+		for _, t := range op.CurrentHand.GetCardTypes() {
+			deck := op.CurrentHand.GetCardDeck(t)
+			for i := 0; i < int(deck.Quantity); i++ {
+				pool = append(pool, t)
+			}
+		}
+	}
+	c.Rand.Shuffle(len(pool), func(i, j int) { pool[i], pool[j] = pool[j], pool[i] })
+
+	for _, op := range opponents {
+		count := int(op.CurrentHand.GetCardCount())
+		for _, t := range op.CurrentHand.GetCardTypes() {
+			op.CurrentHand.GetCardDeck(t).Quantity = 0
+		}
+		for _, t := range pool[:count] {
+			op.CurrentHand.UpdateCards(t, 1)
+		}
+		pool = pool[count:]
+	}
+
+	// Undrawn event cards, the clone has the order of the real deck
+	if c.isEventCards() && c.EventCardCursor < len(c.EventCardOrder) {
+		events := c.EventCardOrder[c.EventCardCursor:]
+		c.Rand.Shuffle(len(events), func(i, j int) { events[i], events[j] = events[j], events[i] })
+	}
+
+	if c.Mode == entities.CitiesAndKnights {
+		// Progress cards of opponents and the undrawn decks, by colour
+		for _, ct := range [3]entities.CardType{entities.CardTypePaper, entities.CardTypeCloth, entities.CardTypeCoin} {
+			c.Bank.DevelopmentCardOrder[ct] = m.redeal(c, opponents, c.Bank.DevelopmentCardOrder[ct], ct)
+		}
+		for _, op := range opponents {
+			for _, deck := range op.CurrentHand.DevelopmentCardDeckMap {
+				deck.CanUse = false
+			}
+			c.ComputeProgressCardsUsable(op)
+		}
+		return
+	}
+
+	// Unused development cards of opponents and the undrawn deck
+	order := c.Bank.DevelopmentCardOrder[0]
+	devPool := m.redeal(c, opponents, order[c.Bank.DevelopmentCardCursor:], 0)
+	for _, op := range opponents {
+		for _, deck := range op.CurrentHand.DevelopmentCardDeckMap {
+			deck.CanUse = deck.Quantity > 0
+		}
+	}
+
+	c.Bank.DevelopmentCardOrder[0] = append(order[:c.Bank.DevelopmentCardCursor:c.Bank.DevelopmentCardCursor], devPool...)
+}
+
+// Pool the cards of a deck type held by opponents with the undrawn deck
+// and deal each opponent as many as they held, returns the new deck
+func (m *MCTSBot) redeal(c *Game, opponents []*entities.Player, deck []entities.DevelopmentCardType, deckType entities.CardType) []entities.DevelopmentCardType {
+	// Cards played as soon as they are drawn are never held
+	pool := make([]entities.DevelopmentCardType, 0, len(deck))
+	aside := make([]entities.DevelopmentCardType, 0)
+	for _, t := range deck {
+		if t == entities.ProgressCoinConstitution || t == entities.ProgressPaperPrinter {
+			aside = append(aside, t)
+		} else {
+			pool = append(pool, t)
+		}
+	}
+
+	counts := make([]int, len(opponents))
+	for i, op := range opponents {
+		for _, t := range op.CurrentHand.GetDevelopmentCardTypes() {
+			if entities.GetDevelopmentCardDeckType(t) != deckType {
+				continue
+			}
+			d := op.CurrentHand.DevelopmentCardDeckMap[t]
+			for j := 0; j < int(d.Quantity); j++ {
+				pool = append(pool, t)
+			}
+			counts[i] += int(d.Quantity)
+			d.Quantity = 0
+		}
+	}
+	c.Rand.Shuffle(len(pool), func(i, j int) { pool[i], pool[j] = pool[j], pool[i] })
+
+	for i, op := range opponents {
+		for _, t := range pool[:counts[i]] {
+			op.CurrentHand.DevelopmentCardDeckMap[t].Quantity++
+		}
+		pool = pool[counts[i]:]
+	}
+
+	pool = append(pool, aside...)
+	c.Rand.Shuffle(len(pool), func(i, j int) { pool[i], pool[j] = pool[j], pool[i] })
+	return pool
+}
+
+// Get the chance of winning from the position
+func (m *MCTSBot) evaluate(c *Game, cp *entities.Player) float64 {
+	if c.GameOver {
+		if c.GetWinner() == int(cp.Order) {
+			return 1
+		}
+		return 0
+	}
+
+	score := func(p *entities.Player) float64 {
+		production := 0.0
+		for _, s := range m.getTileScoreMap(p) {
+			production += s / 36.0
+		}
+
+		// Room to expand is worth something until it is used
+		spots := math.Min(float64(len(p.GetBuildLocationsSettlement(c.Graph, false, false))), 2)
+		cards := math.Min(float64(p.CurrentHand.GetCardCount()), float64(c.GetDiscardLimit(p)))
+		return float64(c.GetVictoryPoints(p, false)) + 2*production + 0.3*spots + 0.05*cards
+	}
+
+	best := math.Inf(-1)
+	for _, op := range c.Players {
+		if op != cp {
+			best = math.Max(best, score(op))
+		}
+	}
+
+	return 1 / (1 + math.Exp((best-score(cp))/2))
+}
+
+func (m *MCTSBot) apply(g *Game, p *entities.Player, move mctsMove) error {
+	switch move.Type {
+	case mctsMoveCity:
+		return g.BuildCity(p, move.C)
+	case mctsMoveSettlement:
+		return g.BuildSettlement(p, move.C)
+	case mctsMoveRoad:
+		return g.BuildRoad(p, move.E)
+	case mctsMoveDevCard:
+		return g.BuyDevelopmentCard(p)
+	case mctsMoveUseDevCard:
+		return g.UseDevelopmentCard(p, entities.DevelopmentCardType(move.Card))
+	case mctsMoveImprovement:
+		return g.BuildCityImprovement(p, entities.CardType(move.Card))
+	case mctsMoveKnight:
+		return g.BuildKnight(p, move.C)
+	case mctsMoveActivateKnight:
+		return g.ActivateKnight(p, move.C)
+	case mctsMoveBankTrade:
+		ratios := g.GetRatiosForPlayer(p)
+		details := &entities.TradeOfferDetails{}
+		details.Give[move.Give] = ratios[move.Give]
+		details.Ask[move.Ask] = 1
+		return g.TradeWithBank(p, details)
+	}
+	return nil
+}
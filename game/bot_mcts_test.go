@@ -0,0 +1,142 @@
+package game
+
+import (
+	"imperials/entities"
+	"imperials/memstore"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// Clone of a game of bots after a few turns, for the player of order 0
+func newDeterminizeTestGame(t *testing.T, id string, settings entities.GameSettings, rules *entities.AdvancedSettings) (*Game, *Game) {
+	t.Helper()
+
+	g := newTestGame(t, memstore.NewMemStore(), id, settings, rules, 4)
+	playTestTurns(t, g, 12)
+	if g.GameOver {
+		t.Fatal("game is over")
+	}
+
+	c, err := g.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Settings.Seed = 5
+	c.Rand = entities.NewRand(5)
+	return g, c
+}
+
+func TestDeterminizeEventDeck(t *testing.T) {
+	g, c := newDeterminizeTestGame(t, "determinize-events", testSettings(entities.Base, 21), &entities.AdvancedSettings{EventCards: true})
+
+	cursor := c.EventCardCursor
+	if cursor == 0 || cursor >= len(c.EventCardOrder) {
+		t.Fatalf("no cards drawn from the event deck, cursor %d", cursor)
+	}
+
+	(&MCTSBot{}).determinize(c, c.Players[0])
+
+	if !reflect.DeepEqual(c.EventCardOrder[:cursor], g.EventCardOrder[:cursor]) {
+		t.Fatal("drawn event cards changed")
+	}
+	if reflect.DeepEqual(c.EventCardOrder[cursor:], g.EventCardOrder[cursor:]) {
+		t.Fatal("undrawn event cards are in the real order")
+	}
+
+	undrawn := append([]int{}, c.EventCardOrder[cursor:]...)
+	real := append([]int{}, g.EventCardOrder[cursor:]...)
+	sort.Ints(undrawn)
+	sort.Ints(real)
+	if !reflect.DeepEqual(undrawn, real) {
+		t.Fatal("undrawn event cards are not the same cards")
+	}
+}
+
+// Cards of a deck type held by each player and left in the deck
+func progressCounts(c *Game, ct entities.CardType) ([]int, map[entities.DevelopmentCardType]int) {
+	held := make([]int, len(c.Players))
+	all := make(map[entities.DevelopmentCardType]int)
+	for i, p := range c.Players {
+		for _, t := range p.CurrentHand.GetDevelopmentCardTypes() {
+			if entities.GetDevelopmentCardDeckType(t) == ct {
+				q := int(p.CurrentHand.DevelopmentCardDeckMap[t].Quantity)
+				held[i] += q
+				all[t] += q
+			}
+		}
+	}
+	for _, t := range c.Bank.DevelopmentCardOrder[ct] {
+		all[t]++
+	}
+	return held, all
+}
+
+func TestDeterminizeProgressCards(t *testing.T) {
+	_, c := newDeterminizeTestGame(t, "determinize-progress", testSettings(entities.CitiesAndKnights, 22), nil)
+
+	// Every player holds the top cards of each deck
+	colours := []entities.CardType{entities.CardTypePaper, entities.CardTypeCloth, entities.CardTypeCoin}
+	for _, ct := range colours {
+		for _, p := range c.Players {
+			for k := 0; k < 2; k++ {
+				deck := c.Bank.DevelopmentCardOrder[ct]
+				if deck[0] == entities.ProgressCoinConstitution || deck[0] == entities.ProgressPaperPrinter {
+					deck[0], deck[len(deck)-1] = deck[len(deck)-1], deck[0]
+				}
+				p.CurrentHand.DevelopmentCardDeckMap[deck[0]].Quantity++
+				c.Bank.DevelopmentCardOrder[ct] = deck[1:]
+			}
+		}
+	}
+
+	cp := c.Players[0]
+	own := make(map[entities.DevelopmentCardType]int16)
+	for ty, deck := range cp.CurrentHand.DevelopmentCardDeckMap {
+		own[ty] = deck.Quantity
+	}
+
+	for _, ct := range colours {
+		held, all := progressCounts(c, ct)
+		changed := false
+
+		for i := 0; i < 10; i++ {
+			before := c.Players[1].CurrentHand.GetDevelopmentCardTypes()
+			quantities := make([]int16, len(before))
+			for j, ty := range before {
+				quantities[j] = c.Players[1].CurrentHand.DevelopmentCardDeckMap[ty].Quantity
+			}
+
+			(&MCTSBot{}).determinize(c, cp)
+
+			h, a := progressCounts(c, ct)
+			if !reflect.DeepEqual(h, held) {
+				t.Fatalf("deck %d: players hold %v cards, held %v", ct, h, held)
+			}
+			if !reflect.DeepEqual(a, all) {
+				t.Fatalf("deck %d: cards changed from %v to %v", ct, all, a)
+			}
+			for j, ty := range before {
+				if c.Players[1].CurrentHand.DevelopmentCardDeckMap[ty].Quantity != quantities[j] {
+					changed = true
+				}
+			}
+		}
+
+		if !changed {
+			t.Errorf("deck %d: cards of an opponent were never dealt again", ct)
+		}
+	}
+
+	for ty, deck := range cp.CurrentHand.DevelopmentCardDeckMap {
+		if deck.Quantity != own[ty] {
+			t.Fatal("cards of the player changed")
+		}
+	}
+	for _, p := range c.Players {
+		if p.CurrentHand.DevelopmentCardDeckMap[entities.ProgressCoinConstitution].Quantity > 0 ||
+			p.CurrentHand.DevelopmentCardDeckMap[entities.ProgressPaperPrinter].Quantity > 0 {
+			t.Fatal("a card played when drawn is held")
+		}
+	}
+}
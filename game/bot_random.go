@@ -0,0 +1,132 @@
+package game
+
+import (
+	"imperials/entities"
+	"log"
+)
+
+// Makes every decision at random, a baseline for other strategies
+type RandomBot struct {
+	g         *Game
+	failedDev map[entities.DevelopmentCardType]bool
+}
+
+func NewRandomBot(g *Game) *RandomBot {
+	return &RandomBot{g: g}
+}
+
+func (r *RandomBot) Tick(p *entities.Player) bool {
+	actions := make([]func() error, 0)
+
+	if p.CanBuild(entities.BTCity) == nil {
+		if locs := p.GetBuildLocationsCity(r.g.Graph); len(locs) > 0 {
+			actions = append(actions, func() error {
+				return r.g.BuildCity(p, r.g.randomVertex(locs).C)
+			})
+		}
+	}
+
+	if p.CanBuild(entities.BTSettlement) == nil {
+		if locs := p.GetBuildLocationsSettlement(r.g.Graph, false, false); len(locs) > 0 {
+			actions = append(actions, func() error {
+				return r.g.BuildSettlement(p, r.g.randomVertex(locs).C)
+			})
+		}
+	}
+
+	if p.CanBuild(entities.BTRoad) == nil {
+		if locs := r.g.GetRoadLocations(p); len(locs) > 0 {
+			actions = append(actions, func() error {
+				return r.g.BuildRoad(p, r.g.randomEdge(locs).C)
+			})
+		}
+	}
+
+	if r.g.Mode.UsesBaseCards() && p.CanBuyDevelopmentCard() {
+		actions = append(actions, func() error {
+			return r.g.BuyDevelopmentCard(p)
+		})
+	}
+
+	for _, t := range p.CurrentHand.GetDevelopmentCardTypes() {
+		deck := p.CurrentHand.DevelopmentCardDeckMap[t]
+		if deck.CanUse && deck.Quantity > 0 && !r.failedDev[t] {
+			t := t
+			actions = append(actions, func() error {
+				err := r.g.UseDevelopmentCard(p, t)
+				if err != nil {
+					r.failedDev[t] = true
+				}
+				return nil
+			})
+		}
+	}
+
+	if len(actions) == 0 {
+		return false
+	}
+
+	if err := actions[r.g.Rand.Intn(len(actions))](); err != nil {
+		log.Println("[BUG] Random bot failed to act", err)
+		return false
+	}
+	return true
+}
+
+func (r *RandomBot) Reset() {
+	r.failedDev = make(map[entities.DevelopmentCardType]bool)
+}
+
+func (r *RandomBot) ChooseBestVertexSettlement(p *entities.Player, allowed []*entities.Vertex) *entities.Vertex {
+	if len(allowed) == 0 {
+		return nil
+	}
+	return r.g.randomVertex(allowed)
+}
+
+func (r *RandomBot) ChooseBestEdgeRoad(p *entities.Player, allowed []*entities.Edge) *entities.Edge {
+	if len(allowed) == 0 {
+		return nil
+	}
+	return r.g.randomEdge(allowed)
+}
+
+func (r *RandomBot) GetRobberTile(p *entities.Player, allowed []*entities.Tile) *entities.Tile {
+	return r.g.randomTile(allowed)
+}
+
+func (r *RandomBot) ChooseRobberVictim(p *entities.Player, choices []*entities.Player) *entities.Player {
+	return choices[r.g.Rand.Intn(len(choices))]
+}
+
+func (r *RandomBot) ChooseDiscard(p *entities.Player, quantity int) [9]int {
+	return NewAI(r.g).ChooseDiscard(p, quantity)
+}
+
+func (r *RandomBot) ShouldAcceptOffer(p *entities.Player, offer *entities.TradeOffer) bool {
+	for i, q := range offer.Details.Ask {
+		deck := p.CurrentHand.GetCardDeck(entities.CardType(i))
+		if q > 0 && (deck == nil || int(deck.Quantity) < q) {
+			return false
+		}
+	}
+	return r.g.Rand.Intn(2) == 0
+}
+
+func (r *RandomBot) ChooseMonopolyResource(p *entities.Player) entities.CardType {
+	return entities.CardType(r.g.Rand.Intn(5) + 1)
+}
+
+func (r *RandomBot) ChooseMerchantLocation(p *entities.Player, allowed []*entities.Tile) *entities.Tile {
+	return r.g.randomTile(allowed)
+}
+
+func (r *RandomBot) ChooseMerchantFleet(p *entities.Player) [9]int {
+	res := [9]int{0, 0, 0, 0, 0, 0, 0, 0, 0}
+	if t := p.CurrentHand.ChooseRandomCardType(r.g.Rand); t != nil {
+		res[*t] = 1
+	} else {
+		res[entities.CardTypeWood] = 1
+	}
+	return res
+}
@@ -0,0 +1,455 @@
+package game
+
+import (
+	"errors"
+	"imperials/entities"
+)
+
+// Rebuild a copy of the game by replaying its journal
+// The copy is headless and shares no state with the original,
+// offers and pending actions are not carried over
+func (g *Game) Clone() (*Game, error) {
+	if g.InitPhase {
+		return nil, errors.New("cannot clone during the init phase")
+	}
+
+	c := &Game{
+		Store:            &journalStore{entries: g.j.log[:len(g.j.log):len(g.j.log)]},
+		Headless:         true,
+		Settings:         g.Settings,
+		AdvancedSettings: g.AdvancedSettings,
+	}
+
+	if _, err := c.Initialize(g.ID, g.NumPlayers); err != nil {
+		return nil, err
+	}
+
+	if c.j.index != g.j.index {
+		return nil, errors.New("failed to replay journal")
+	}
+
+	return c, nil
+}
+
+// Copy a clone in memory, much faster than replaying its journal
+// Only for clones, offers, pending actions and bots are not copied
+// and the copy starts the sequence of its last journal entry again
+func (g *Game) copyClone() *Game {
+	c := &Game{
+		ID:               g.ID,
+		Mode:             g.Mode,
+		Initialized:      g.Initialized,
+		Store:            &journalStore{entries: g.j.log[:len(g.j.log):len(g.j.log)]},
+		Settings:         g.Settings,
+		AdvancedSettings: g.AdvancedSettings,
+
+		DiceState:     g.DiceState,
+		LastRollWhite: g.LastRollWhite,
+		LastRollRed:   g.LastRollRed,
+		LastRollEvent: g.LastRollEvent,
+
+		NumPlayers:          g.NumPlayers,
+		MerchantFleets:      g.MerchantFleets,
+		BarbarianPosition:   g.BarbarianPosition,
+		NumBarbarianAttacks: g.NumBarbarianAttacks,
+
+		InitPhase:         g.InitPhase,
+		GameOver:          g.GameOver,
+		SpecialBuildPhase: g.SpecialBuildPhase,
+		ShipMoved:         g.ShipMoved,
+		pirateMoved:       g.pirateMoved,
+
+		EventCardOrder:  append([]int(nil), g.EventCardOrder...),
+		EventCardCursor: g.EventCardCursor,
+		Event:           g.Event,
+
+		Headless:     true,
+		TimerVals:    g.TimerVals,
+		DispCoordMap: g.DispCoordMap,
+
+		OfferCounter:  g.OfferCounter,
+		CurrentOffers: make([]*entities.TradeOffer, 0),
+
+		seedGiven: g.seedGiven,
+	}
+
+	c.j.g = c
+	c.j.Init()
+	c.j.index = g.j.index
+	c.j.log = g.j.log[:len(g.j.log):len(g.j.log)]
+
+	c.j.reseeds = g.j.reseeds
+	c.Rand = entities.NewRand(c.Settings.Seed + int64(c.j.reseeds))
+
+	// Board, placements are added with the players
+	tiles := make(map[*entities.Tile]*entities.Tile, len(g.Tiles))
+	c.Tiles = make(map[entities.Coordinate]*entities.Tile, len(g.Tiles))
+	for k, t := range g.Tiles {
+		nt := *t
+		tiles[t] = &nt
+		c.Tiles[k] = &nt
+	}
+	copyTiles := func(ts []*entities.Tile) []*entities.Tile {
+		res := make([]*entities.Tile, len(ts))
+		for i, t := range ts {
+			res[i] = tiles[t]
+		}
+		return res
+	}
+
+	c.Vertices = make(map[entities.Coordinate]*entities.Vertex, len(g.Vertices))
+	for k, v := range g.Vertices {
+		c.Vertices[k] = &entities.Vertex{C: v.C, AdjacentTiles: copyTiles(v.AdjacentTiles)}
+	}
+
+	c.Edges = make(map[entities.EdgeCoordinate]*entities.Edge, len(g.Edges))
+	for k, e := range g.Edges {
+		ne := *e
+		ne.Placement = nil
+		ne.AdjacentTiles = copyTiles(e.AdjacentTiles)
+		c.Edges[k] = &ne
+	}
+
+	c.Graph = &entities.Graph{Tiles: c.Tiles, Vertices: c.Vertices, Edges: c.Edges}
+
+	c.Ports = make([]*entities.Port, len(g.Ports))
+	for i, port := range g.Ports {
+		np := *port
+		np.Vertices = make([]*entities.Vertex, len(port.Vertices))
+		for j, v := range port.Vertices {
+			np.Vertices[j] = c.Vertices[v.C]
+		}
+		if port.Edge != nil {
+			np.Edge = c.Edges[port.Edge.C]
+		}
+		c.Ports[i] = &np
+	}
+
+	c.Robber = &entities.Robber{Tile: tiles[g.Robber.Tile]}
+	c.Pirate = &entities.Robber{Tile: tiles[g.Pirate.Tile]}
+
+	c.Camels = make([]*entities.Edge, len(g.Camels))
+	for i, e := range g.Camels {
+		c.Camels[i] = c.Edges[e.C]
+	}
+	if g.Camels == nil {
+		c.Camels = nil
+	}
+
+	c.Barbarians = make(map[entities.Coordinate]int, len(g.Barbarians))
+	for k, n := range g.Barbarians {
+		c.Barbarians[k] = n
+	}
+
+	// Players and their pieces
+	players := make(map[*entities.Player]*entities.Player, len(g.Players))
+	c.Players = make([]*entities.Player, len(g.Players))
+	for i, p := range g.Players {
+		c.Players[i] = c.copyPlayer(p)
+		players[p] = c.Players[i]
+	}
+
+	c.CurrentPlayer = players[g.CurrentPlayer]
+	c.SpecialBuildStarter = players[g.SpecialBuildStarter]
+
+	if g.Merchant != nil {
+		c.Merchant = &entities.Merchant{Tile: tiles[g.Merchant.Tile], Owner: players[g.Merchant.Owner]}
+	}
+
+	evp := *g.ExtraVictoryPoints
+	evp.LongestRoadHolder = players[evp.LongestRoadHolder]
+	evp.LargestArmyHolder = players[evp.LargestArmyHolder]
+	evp.PrinterHolder = players[evp.PrinterHolder]
+	evp.ConstitutionHolder = players[evp.ConstitutionHolder]
+	if evp.DefenderPoints != nil {
+		evp.DefenderPoints = make([]*entities.Player, len(g.ExtraVictoryPoints.DefenderPoints))
+		for i, p := range g.ExtraVictoryPoints.DefenderPoints {
+			evp.DefenderPoints[i] = players[p]
+		}
+	}
+	if evp.Metropolis != nil {
+		evp.Metropolis = make(map[entities.CardType]*entities.Player, len(g.ExtraVictoryPoints.Metropolis))
+		for k, p := range g.ExtraVictoryPoints.Metropolis {
+			evp.Metropolis[k] = players[p]
+		}
+	}
+	if evp.SettledIslands != nil {
+		evp.SettledIslands = make([]map[int]bool, len(g.ExtraVictoryPoints.SettledIslands))
+		for i, islands := range g.ExtraVictoryPoints.SettledIslands {
+			evp.SettledIslands[i] = make(map[int]bool, len(islands))
+			for k, v := range islands {
+				evp.SettledIslands[i][k] = v
+			}
+		}
+	}
+	c.ExtraVictoryPoints = &evp
+
+	// Cards
+	c.Bank = &entities.Bank{
+		Hand:                  copyHand(g.Bank.Hand),
+		DevelopmentCardOrder:  make(map[entities.CardType][]entities.DevelopmentCardType, len(g.Bank.DevelopmentCardOrder)),
+		DevelopmentCardCursor: g.Bank.DevelopmentCardCursor,
+	}
+	for k, order := range g.Bank.DevelopmentCardOrder {
+		c.Bank.DevelopmentCardOrder[k] = append([]entities.DevelopmentCardType(nil), order...)
+	}
+	c.DevelopmentCardOrder = append([]entities.DevelopmentCardType(nil), g.DevelopmentCardOrder...)
+
+	// Stats
+	diceStats := *g.DiceStats
+	if diceStats.Deck != nil {
+		deck := *diceStats.Deck
+		deck.Cards = append([]int(nil), deck.Cards...)
+		diceStats.Deck = &deck
+	}
+	c.DiceStats = &diceStats
+
+	c.Stats = &entities.GameStats{
+		Turns:   g.Stats.Turns,
+		Players: make([]*entities.PlayerStats, len(g.Stats.Players)),
+		Tiles:   make(map[entities.Coordinate]*entities.TileStats, len(g.Stats.Tiles)),
+	}
+	for i, ps := range g.Stats.Players {
+		nps := *ps
+		nps.Trades = append([]int(nil), ps.Trades...)
+		nps.TradeVolume = append([]int(nil), ps.TradeVolume...)
+		nps.VictoryPoints = append([]int(nil), ps.VictoryPoints...)
+		nps.Openings = append([]entities.Coordinate(nil), ps.Openings...)
+		nps.CardsPlayed = make(map[entities.DevelopmentCardType]int, len(ps.CardsPlayed))
+		for k, n := range ps.CardsPlayed {
+			nps.CardsPlayed[k] = n
+		}
+		c.Stats.Players[i] = &nps
+	}
+	for k, ts := range g.Stats.Tiles {
+		nts := *ts
+		c.Stats.Tiles[k] = &nts
+	}
+
+	return c
+}
+
+// Copy a player of another game with its pieces on the board of this one
+func (g *Game) copyPlayer(p *entities.Player) *entities.Player {
+	np := *p
+	np.CurrentHand = copyHand(p.CurrentHand)
+	np.PendingAction = nil
+	np.MessageChannel = nil
+	np.Expect = make(chan interface{}, 4)
+	np.Rules = g.rules()
+	np.Embargos = append([]bool(nil), p.Embargos...)
+
+	np.BuildablesLeft = make(map[entities.BuildableType]int, len(p.BuildablesLeft))
+	for k, n := range p.BuildablesLeft {
+		np.BuildablesLeft[k] = n
+	}
+	np.Improvements = make(map[int]int, len(p.Improvements))
+	for k, n := range p.Improvements {
+		np.Improvements[k] = n
+	}
+
+	np.VertexPlacements = make([]entities.VertexBuildable, 0, len(p.VertexPlacements))
+	for _, vp := range p.VertexPlacements {
+		var nvp entities.VertexBuildable
+		switch b := vp.(type) {
+		case *entities.Settlement:
+			nb := *b
+			nvp = &nb
+		case *entities.City:
+			nb := *b
+			nvp = &nb
+		case *entities.Knight:
+			nb := *b
+			nvp = &nb
+		default:
+			continue
+		}
+
+		v := g.Vertices[vp.GetLocation().C]
+		nvp.SetOwner(&np)
+		nvp.SetLocation(v)
+		v.Placement = nvp
+		np.VertexPlacements = append(np.VertexPlacements, nvp)
+	}
+
+	np.EdgePlacements = make([]entities.EdgeBuildable, 0, len(p.EdgePlacements))
+	for _, ep := range p.EdgePlacements {
+		e := g.Edges[ep.GetLocation().C]
+		var nep entities.EdgeBuildable
+		switch b := ep.(type) {
+		case *entities.Road:
+			nb := *b
+			nb.Location = e
+			nep = &nb
+		case *entities.Ship:
+			nb := *b
+			nb.Location = e
+			nep = &nb
+		default:
+			continue
+		}
+
+		nep.SetOwner(&np)
+		e.Placement = nep
+		np.EdgePlacements = append(np.EdgePlacements, nep)
+	}
+
+	return &np
+}
+
+func copyHand(h *entities.Hand) *entities.Hand {
+	nh := &entities.Hand{
+		CardDeckMap:            make(map[entities.CardType]*entities.CardDeck, len(h.CardDeckMap)),
+		DevelopmentCardDeckMap: make(map[entities.DevelopmentCardType]*entities.DevelopmentCardDeck, len(h.DevelopmentCardDeckMap)),
+	}
+	for k, d := range h.CardDeckMap {
+		nd := *d
+		nh.CardDeckMap[k] = &nd
+	}
+	for k, d := range h.DevelopmentCardDeckMap {
+		nd := *d
+		nh.DevelopmentCardDeckMap[k] = &nd
+	}
+	return nh
+}
+
+// Store that only serves a journal, used by clones
+type journalStore struct {
+	entries [][]byte
+}
+
+func (s *journalStore) Init(id string) error {
+	return nil
+}
+
+func (s *journalStore) CreateGameIfNotExists(id string) error {
+	return nil
+}
+
+func (s *journalStore) CreateGameStateIfNotExists(id string, state []byte) error {
+	return nil
+}
+
+func (s *journalStore) WriteGameServer(id string) error {
+	return nil
+}
+
+func (s *journalStore) WriteGameStarted(id string) error {
+	return nil
+}
+
+func (s *journalStore) WriteGameFinished(id string) error {
+	return nil
+}
+
+func (s *journalStore) WriteGameCompletedForUser(id string) error {
+	return nil
+}
+
+func (s *journalStore) WriteGamePlayers(id string, numPlayers int32) error {
+	return nil
+}
+
+func (s *journalStore) WriteGameActivePlayers(id string, numPlayers int32, host string) error {
+	return nil
+}
+
+func (s *journalStore) WriteGamePrivacy(id string, private bool) error {
+	return nil
+}
+
+func (s *journalStore) WriteGameSettings(id string, settings []byte) error {
+	return nil
+}
+
+func (s *journalStore) WriteJournalEntries(id string, entries [][]byte) error {
+	return nil
+}
+
+func (s *journalStore) TruncateJournal(id string, length int) error {
+	return nil
+}
+
+func (s *journalStore) WriteGameState(id string, state []byte) error {
+	return nil
+}
+
+func (s *journalStore) WriteGameReport(id string, report []byte) error {
+	return nil
+}
+
+func (s *journalStore) WriteGameIdForUser(gameId, userId string, settings *entities.GameSettings) error {
+	return nil
+}
+
+func (s *journalStore) WriteRatings(ratings []*entities.Rating) error {
+	return nil
+}
+
+func (s *journalStore) ReadJournal(id string) ([][]byte, error) {
+	return s.entries, nil
+}
+
+func (s *journalStore) ReadGamePlayers(id string) (int, error) {
+	return 0, nil
+}
+
+func (s *journalStore) ReadUser(id string) (map[string]interface{}, error) {
+	return nil, nil
+}
+
+func (s *journalStore) ReadRatings(userId string) ([]*entities.Rating, error) {
+	return nil, nil
+}
+
+func (s *journalStore) ReadUserGames(userId string, limit int) ([]*entities.UserGame, error) {
+	return nil, nil
+}
+
+func (s *journalStore) ReadLeaderboard(mode entities.GameMode, players int, limit int) ([]*entities.Rating, error) {
+	return nil, nil
+}
+
+func (s *journalStore) GetOfficalMapNames() []string {
+	return nil
+}
+
+func (s *journalStore) GetMap(name string) *entities.MapDefinition {
+	return nil
+}
+
+func (s *journalStore) GetMapInfo(name string) *entities.MapInfo {
+	return nil
+}
+
+func (s *journalStore) GetMapVersion(name string, version int) *entities.MapInfo {
+	return nil
+}
+
+func (s *journalStore) GetMapVersions(name string) ([]*entities.MapInfo, error) {
+	return nil, nil
+}
+
+func (s *journalStore) CreateMapInfo(info *entities.MapInfo) (*entities.MapInfo, error) {
+	return info, nil
+}
+
+func (s *journalStore) WriteMapInfo(info *entities.MapInfo) (*entities.MapInfo, error) {
+	return info, nil
+}
+
+func (s *journalStore) DeleteMap(name string) error {
+	return nil
+}
+
+func (s *journalStore) CheckIfJournalExists(id string) (bool, error) {
+	return len(s.entries) > 0, nil
+}
+
+func (s *journalStore) TerminateGame(id string) error {
+	return nil
+}
+
+func (s *journalStore) GetAllMapNamesForUser(userId string, exclude bool) ([]string, error) {
+	return nil, nil
+}
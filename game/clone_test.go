@@ -0,0 +1,81 @@
+package game
+
+import (
+	"imperials/entities"
+	"imperials/memstore"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// State of the players and pieces on the board
+func testGameState(t *testing.T, g *Game) []interface{} {
+	t.Helper()
+
+	// Maps are not in a fixed order
+	vertices, edges := g.getPlacements()
+	pieces := make([]string, 0)
+	for _, vp := range vertices {
+		b, err := msgpack.Marshal(vp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pieces = append(pieces, string(b))
+	}
+	for _, ep := range edges {
+		b, err := msgpack.Marshal(ep)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pieces = append(pieces, string(b))
+	}
+	sort.Strings(pieces)
+
+	return []interface{}{
+		g.GenerateStoreGameState(),
+		pieces,
+		*g.Robber.Tile,
+		g.Bank.Hand,
+		g.Bank.DevelopmentCardCursor,
+		g.CurrentPlayer.Order,
+		g.DiceState,
+	}
+}
+
+func TestCopyClone(t *testing.T) {
+	for _, mode := range []entities.GameMode{entities.Base, entities.CitiesAndKnights, entities.Seafarers} {
+		g := newTestGame(t, memstore.NewMemStore(), "copy-clone", testSettings(mode, 31), nil, 3)
+		playTestTurns(t, g, 9)
+		if g.GameOver {
+			t.Fatal("game is over")
+		}
+
+		c, err := g.Clone()
+		if err != nil {
+			t.Fatal(err)
+		}
+		state := testGameState(t, c)
+
+		cc := c.copyClone()
+		if !reflect.DeepEqual(testGameState(t, cc), state) {
+			t.Fatalf("mode %d: copy differs from the clone", mode)
+		}
+
+		// The copy plays on like a clone, bots of the game have their own state
+		other, err := g.Clone()
+		if err != nil {
+			t.Fatal(err)
+		}
+		playTestTurns(t, other, 6)
+		playTestTurns(t, cc, 6)
+		if !reflect.DeepEqual(testGameState(t, cc), testGameState(t, other)) {
+			t.Fatalf("mode %d: copy played differently from a clone", mode)
+		}
+
+		if !reflect.DeepEqual(testGameState(t, c), state) {
+			t.Fatalf("mode %d: playing the copy changed the clone", mode)
+		}
+	}
+}
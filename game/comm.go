@@ -7,7 +7,7 @@ import (
 )
 
 func (g *Game) BroadcastMessage(msg *entities.Message) {
-	if g.j.playing || !g.Initialized {
+	if g.j.playing || !g.Initialized || g.Headless {
 		return
 	}
 
@@ -24,7 +24,7 @@ func (g *Game) BroadcastState() {
 }
 
 func (g *Game) SendPlayerSecret(p *entities.Player) {
-	if g.j.playing || !g.Initialized {
+	if g.j.playing || !g.Initialized || g.Headless {
 		return
 	}
 
@@ -69,6 +69,18 @@ func (g *Game) BlockForAction(
 	g.SetPendingAction(p, action)
 	p.ClearExpect()
 
+	// Bots never answer, so a headless game skips straight to the default
+	if g.Headless && p.GetIsBot() {
+		if timeout > 0 {
+			p.TimeLeft = oldTimeLeft
+		}
+		if pauseTicker {
+			g.TickerPause = false
+		}
+		p.ClearPendingAction()
+		return nil, nil
+	}
+
 	g.Unlock()
 
 	getExpectWithTimeout := func(g *Game, p *entities.Player) interface{} {
@@ -117,6 +129,29 @@ func (g *Game) BlockForAction(
 	return exp, nil
 }
 
+// Run f, which locks the mutex itself, alongside other prompts
+// Expects a locked mutex. Headless games run f in place so that
+// bots answer in a fixed order.
+func (g *Game) fork(f func()) {
+	if g.Headless {
+		g.Unlock()
+		f()
+		g.Lock()
+		return
+	}
+
+	go f()
+}
+
+// Run f in the background, a headless game waits for it before the next tick
+func (g *Game) goAsync(f func()) {
+	g.async.Add(1)
+	go func() {
+		defer g.async.Done()
+		f()
+	}()
+}
+
 func (g *Game) resetTimeLeft() {
 	for _, p := range g.Players {
 		p.TimeLeft = 0
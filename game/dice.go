@@ -3,7 +3,6 @@ package game
 import (
 	"errors"
 	"imperials/entities"
-	"math/rand"
 	"strconv"
 	"sync"
 
@@ -23,8 +22,27 @@ func (g *Game) RollDice(p *entities.Player, givenRedRoll int, givenWhiteRoll int
 	g.ActionMutex.Lock()
 	defer g.ActionMutex.Unlock()
 
-	redRoll := rand.Intn(6) + 1
-	whiteRoll := rand.Intn(6) + 1
+	// Nobody gets robbed before their first turn
+	rules := g.rules()
+	noSeven := rules != nil && rules.NoSevensFirstRound && g.isFirstRound()
+
+	var card *entities.EventCard
+	var redRoll, whiteRoll int
+	if g.isEventCards() {
+		// Do not waste a card on a roll that fails
+		if g.DiceState == 1 {
+			return errors.New("already rolled for this turn")
+		}
+
+		c := g.drawEventCard(noSeven)
+		card = &c
+		redRoll, whiteRoll = c.RedRoll, c.WhiteRoll
+	} else {
+		redRoll, whiteRoll = g.drawDice(false)
+		for noSeven && redRoll+whiteRoll == 7 {
+			redRoll, whiteRoll = g.drawDice(true)
+		}
+	}
 
 	if givenRedRoll != 0 {
 		redRoll = givenRedRoll
@@ -40,7 +58,7 @@ func (g *Game) RollDice(p *entities.Player, givenRedRoll int, givenWhiteRoll int
 	}
 
 	if g.Mode == entities.CitiesAndKnights {
-		dieRollState.EventRoll = rand.Intn(6) + 1
+		dieRollState.EventRoll = g.Rand.Intn(6) + 1
 	}
 
 	g.BroadcastMessage(&entities.Message{
@@ -53,6 +71,10 @@ func (g *Game) RollDice(p *entities.Player, givenRedRoll int, givenWhiteRoll int
 		g.RollEventDiceWith(dieRollState.EventRoll)
 	}
 
+	if card != nil {
+		g.playEventCard(*card)
+	}
+
 	for _, p := range g.Players {
 		g.SendPlayerSecret(p)
 	}
@@ -67,7 +89,11 @@ func (g *Game) RollDiceWith(redRoll int, whiteRoll int) (*entities.DieRollState,
 	if g.DiceState == 1 {
 		return nil, errors.New("already rolled for this turn")
 	}
+	g.countExpectedProduction()
 	g.DiceStats.Rolls[(redRoll+whiteRoll)-1]++
+	if g.isBalancedDice() {
+		g.getDiceDeck().Remove(redRoll, whiteRoll)
+	}
 	g.DiceState = 1
 	g.LastRollRed = redRoll
 	g.LastRollWhite = whiteRoll
@@ -85,7 +111,7 @@ func (g *Game) RollDiceWith(redRoll int, whiteRoll int) (*entities.DieRollState,
 		// performing the robber movement.
 		// See the top comment of RollDice7 for more details
 		if !g.j.playing {
-			go g.RollDice7(dieRollState)
+			g.goAsync(func() { g.RollDice7(dieRollState) })
 		}
 		return dieRollState, nil
 	}
@@ -119,7 +145,7 @@ func (g *Game) RollDiceWith(redRoll int, whiteRoll int) (*entities.DieRollState,
 		return nil, err
 	}
 	for _, tile := range numberTiles {
-		if g.Robber.Tile == tile {
+		if g.Robber.Tile == tile || g.isConquered(tile) {
 			continue
 		}
 
@@ -157,7 +183,14 @@ func (g *Game) RollDiceWith(redRoll int, whiteRoll int) (*entities.DieRollState,
 
 	// Give cards
 	for _, tile := range numberTiles {
+		if g.isConquered(tile) {
+			continue
+		}
+
 		if g.Robber.Tile == tile {
+			for _, placement := range g.Graph.GetTilePlacements(tile) {
+				g.Stats.AddBlocked(tile, placement.GetOwner().Order, getQuantity(placement))
+			}
 			continue
 		}
 
@@ -166,6 +199,7 @@ func (g *Game) RollDiceWith(redRoll int, whiteRoll int) (*entities.DieRollState,
 				owner := placement.GetOwner()
 				quantity := getQuantity(placement)
 				goldCalls[owner.Order].Quantity += quantity
+				g.Stats.AddGold(tile, owner.Order, quantity)
 			}
 			continue
 		}
@@ -178,6 +212,7 @@ func (g *Game) RollDiceWith(redRoll int, whiteRoll int) (*entities.DieRollState,
 
 			player.CurrentHand.UpdateCards(t, quantity)
 			dieRollState.PlayerHandDeltas[player.Order].UpdateCards(entities.CardType(tile.Type), quantity)
+			g.Stats.AddProduction(tile, player.Order, t, quantity)
 
 			dieRollState.GainInfo = append(dieRollState.GainInfo, entities.CardMoveInfo{
 				Tile:        tile,
@@ -225,6 +260,15 @@ func (g *Game) RollDiceWith(redRoll int, whiteRoll int) (*entities.DieRollState,
 		}
 	}
 
+	if g.Mode.HasFish() && entities.IsLakeRoll(roll) {
+		g.produceFish()
+	}
+
+	// Barbarians land after the production, they are journaled on their own
+	if !g.j.playing && g.Mode.HasBarbarianAttack() {
+		g.landRandomBarbarian()
+	}
+
 	if !g.j.playing && g.Mode == entities.CitiesAndKnights {
 		for _, p := range g.Players {
 			if p.Improvements[int(entities.CardTypePaper)] >= 3 && dieRollState.PlayerHandDeltas[p.Order].GetCardCount() == 0 {
@@ -236,7 +280,7 @@ func (g *Game) RollDiceWith(redRoll int, whiteRoll int) (*entities.DieRollState,
 	if !g.j.playing {
 		for _, call := range goldCalls {
 			if call.Quantity > 0 {
-				go g.GiveGold(goldCalls)
+				g.goAsync(func() { g.GiveGold(goldCalls) })
 				break
 			}
 		}
@@ -315,8 +359,14 @@ func (g *Game) RollDice7(state *entities.DieRollState) {
 		return
 	}
 
+	// The friendly robber may protect every tile
+	if err := g.ensureRobberCanMove(); err != nil {
+		g.SendError(err, g.CurrentPlayer)
+		return
+	}
+
 	g.MoveRobberInteractive()
-	if g.Robber.Tile.Type == entities.TileTypeDesert && g.Settings.Advanced && g.AdvancedSettings.RerollOn7 {
+	if !g.pirateMoved && g.Robber.Tile.Type == entities.TileTypeDesert && g.Settings.Advanced && g.AdvancedSettings.RerollOn7 {
 		g.DiceState = 0
 		g.CurrentPlayer.TimeLeft = g.TimerVals.DiceRoll
 		g.BroadcastState()
@@ -354,7 +404,8 @@ func (g *Game) DiscardHalfCards(players []*entities.Player, force bool) {
 			wg.Add(1)
 			p.ClearExpect()
 
-			go func(wg *sync.WaitGroup, p *entities.Player, action *entities.PlayerActionSelectCards) {
+			p := p
+			g.fork(func() {
 				defer wg.Done()
 
 				defer g.Unlock()
@@ -375,6 +426,9 @@ func (g *Game) DiscardHalfCards(players []*entities.Player, force bool) {
 				err = mapstructure.Decode(exp, &resp)
 				if err != nil || len(resp) != 9 {
 					resp = make([]float64, 9)
+					for t, q := range g.botStrategy(p).ChooseDiscard(p, action.Quantity) {
+						resp[t] = float64(q)
+					}
 				}
 
 				sum := 0
@@ -392,7 +446,7 @@ func (g *Game) DiscardHalfCards(players []*entities.Player, force bool) {
 				}
 
 				for sum < action.Quantity {
-					t := p.CurrentHand.ChooseRandomCardType()
+					t := p.CurrentHand.ChooseRandomCardType(g.Rand)
 					if t == nil {
 						break
 					}
@@ -400,11 +454,12 @@ func (g *Game) DiscardHalfCards(players []*entities.Player, force bool) {
 					g.MoveCards(int(p.Order), -1, *t, 1, true, false)
 					sum++
 				}
+				g.countDiscard(p, sum)
 
 				p.SendAction(&entities.PlayerAction{Type: entities.PlayerActionTypeSelectCardsDone})
 				g.SendPlayerSecret(p)
 				g.BroadcastState()
-			}(&wg, p, action)
+			})
 		}
 	}
 
@@ -417,10 +472,20 @@ func (g *Game) DiscardHalfCards(players []*entities.Player, force bool) {
 	g.TickerPause = false
 }
 
-func (g *Game) MoveRobberInteractive() error {
-	// Move the robber
+// Tiles the robber or pirate can be moved to
+func (g *Game) getRobberTiles() []*entities.Tile {
 	tiles := make([]*entities.Tile, 0)
-	for _, t := range g.Graph.Tiles {
+	for _, c := range sortedCoordinates(g.Tiles) {
+		t := g.Tiles[c]
+
+		// The pirate moves on the sea instead of the robber
+		if g.Mode == entities.Seafarers && !t.IsLand() {
+			if t.Type == entities.TileTypeSea && g.Pirate.Tile != t {
+				tiles = append(tiles, t)
+			}
+			continue
+		}
+
 		if (g.Robber.Tile != t ||
 			(g.Robber.Tile.Type == entities.TileTypeDesert && g.Settings.Advanced && g.AdvancedSettings.RerollOn7)) &&
 			!t.Fog {
@@ -428,6 +493,21 @@ func (g *Game) MoveRobberInteractive() error {
 		}
 	}
 
+	allowed := make([]*entities.Tile, 0, len(tiles))
+	for _, t := range tiles {
+		if g.isRobberTileAllowed(t) {
+			allowed = append(allowed, t)
+		}
+	}
+	return allowed
+}
+
+func (g *Game) MoveRobberInteractive() error {
+	tiles := g.getRobberTiles()
+	if len(tiles) == 0 {
+		return errors.New("the robber has nowhere to go")
+	}
+
 	robberAction := &entities.PlayerActionChooseTile{
 		Allowed: tiles,
 	}
@@ -446,29 +526,54 @@ func (g *Game) MoveRobberInteractive() error {
 	mapstructure.Decode(exp, &resp)
 
 	selTile := g.Graph.Tiles[resp]
-	if selTile == nil {
-		selTile = g.ai.GetRobberTile(g.CurrentPlayer, robberAction.Allowed)
+	if !containsTile(tiles, selTile) {
+		selTile = g.botStrategy(g.CurrentPlayer).GetRobberTile(g.CurrentPlayer, robberAction.Allowed)
+	}
+
+	g.pirateMoved = g.Mode == entities.Seafarers && selTile.Type == entities.TileTypeSea
+	if g.pirateMoved {
+		g.Pirate.Move(selTile)
+		g.j.WSetPirate(selTile)
+	} else {
+		g.Robber.Move(selTile)
+		g.j.WSetRobber(selTile)
 	}
 
-	g.Robber.Move(selTile)
-	g.j.WSetRobber(selTile)
 	g.BroadcastState()
 	return nil
 }
 
+func containsTile(tiles []*entities.Tile, t *entities.Tile) bool {
+	for _, o := range tiles {
+		if o == t {
+			return true
+		}
+	}
+	return false
+}
+
 func (g *Game) StealCardWithRobber() error {
 	// Check if anyone to steal from
 	stealChoicesSlice := make([]*entities.Player, 0)
 	stealChoices := make([]bool, len(g.Players))
-	for _, vp := range g.Graph.GetTilePlacements(g.Robber.Tile) {
-		if vp.GetType() != entities.BTSettlement && vp.GetType() != entities.BTCity {
-			continue
+	if g.pirateMoved {
+		for _, o := range g.getPirateVictims() {
+			if o != g.CurrentPlayer && o.CurrentHand.GetCardCount() > 0 {
+				stealChoices[o.Order] = true
+				stealChoicesSlice = append(stealChoicesSlice, o)
+			}
 		}
+	} else {
+		for _, vp := range g.Graph.GetTilePlacements(g.Robber.Tile) {
+			if vp.GetType() != entities.BTSettlement && vp.GetType() != entities.BTCity {
+				continue
+			}
 
-		o := vp.GetOwner()
-		if !stealChoices[o.Order] && o != g.CurrentPlayer && o.CurrentHand.GetCardCount() > 0 {
-			stealChoices[o.Order] = true
-			stealChoicesSlice = append(stealChoicesSlice, vp.GetOwner())
+			o := vp.GetOwner()
+			if !stealChoices[o.Order] && o != g.CurrentPlayer && o.CurrentHand.GetCardCount() > 0 {
+				stealChoices[o.Order] = true
+				stealChoicesSlice = append(stealChoicesSlice, vp.GetOwner())
+			}
 		}
 	}
 
@@ -492,17 +597,9 @@ func (g *Game) StealCardWithRobber() error {
 		}
 
 		err = mapstructure.Decode(exp, &stoleOrder)
-		if err != nil || stoleOrder < 0 || stoleOrder > len(g.Players) || !stealChoices[stoleOrder] {
-			maxScore := 0
-			for i, can := range stealChoices {
-				if can {
-					score := g.GetVictoryPoints(g.Players[i], true)
-					if score > maxScore {
-						stoleOrder = i
-						maxScore = score
-					}
-				}
-			}
+		if err != nil || stoleOrder < 0 || stoleOrder >= len(g.Players) || !stealChoices[stoleOrder] {
+			victim := g.botStrategy(g.CurrentPlayer).ChooseRobberVictim(g.CurrentPlayer, stealChoicesSlice)
+			stoleOrder = int(victim.Order)
 		}
 	}
 
@@ -511,9 +608,10 @@ func (g *Game) StealCardWithRobber() error {
 }
 
 func (g *Game) stealRandomCard(stealer *entities.Player, victim *entities.Player) {
-	cardType := victim.CurrentHand.ChooseRandomCardType()
+	cardType := victim.CurrentHand.ChooseRandomCardType(g.Rand)
 	if cardType != nil {
 		g.MoveCards(int(victim.Order), int(stealer.Order), *cardType, 1, true, true)
+		g.countSteal(victim, stealer)
 	}
 
 	g.SendPlayerSecret(stealer)
@@ -554,7 +652,8 @@ func (g *Game) GiveGold(calls []GoldCall) {
 		wg.Add(1)
 		call.Player.ClearExpect()
 
-		go func(wg *sync.WaitGroup, p *entities.Player, action *entities.PlayerActionSelectCards) {
+		p := call.Player
+		g.fork(func() {
 			defer wg.Done()
 
 			defer g.Unlock()
@@ -600,7 +699,7 @@ func (g *Game) GiveGold(calls []GoldCall) {
 					break
 				}
 
-				ct := entities.CardType(rand.Intn(5) + 1)
+				ct := entities.CardType(g.Rand.Intn(5) + 1)
 				if g.Bank.Hand.GetCardDeck(ct).Quantity > 0 {
 					g.MoveCards(-1, int(p.Order), ct, 1, true, false)
 					sum++
@@ -610,7 +709,7 @@ func (g *Game) GiveGold(calls []GoldCall) {
 			p.SendAction(&entities.PlayerAction{Type: entities.PlayerActionTypeSelectCardsDone})
 			g.SendPlayerSecret(p)
 			g.BroadcastState()
-		}(&wg, call.Player, action)
+		})
 	}
 
 	g.TickerPause = true
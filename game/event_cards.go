@@ -0,0 +1,77 @@
+package game
+
+import (
+	"imperials/entities"
+)
+
+func (g *Game) isEventCards() bool {
+	rules := g.rules()
+	return rules != nil && rules.EventCards
+}
+
+func (g *Game) shuffleEventCards() {
+	g.EventCardOrder = entities.NewEventCardOrder(g.Rand)
+	g.EventCardCursor = 0
+	g.j.WEventCardOrder(g.EventCardOrder)
+}
+
+// Draw the next card, shuffling the deck when the reserve is reached
+// Sevens are put aside with noSeven
+func (g *Game) drawEventCard(noSeven bool) entities.EventCard {
+	for {
+		if g.EventCardCursor >= len(g.EventCardOrder)-entities.EventDeckReserve {
+			g.shuffleEventCards()
+		}
+
+		card := entities.GetEventCard(g.EventCardOrder[g.EventCardCursor], g.rules().EventCardEvents)
+		g.EventCardCursor++
+		g.j.WEventCardCursor(g.EventCardCursor)
+
+		if !noSeven || card.GetRoll() != 7 {
+			return card
+		}
+	}
+}
+
+// Tell everyone about the card and play its event
+func (g *Game) playEventCard(card entities.EventCard) {
+	g.BroadcastMessage(&entities.Message{
+		Location: entities.WsMsgLocationGame,
+		Type:     entities.MessageTypeEventCard,
+		Data: entities.EventCardInfo{
+			Card: card,
+			Left: len(g.EventCardOrder) - entities.EventDeckReserve - g.EventCardCursor,
+		},
+	})
+
+	if card.Event == entities.EventNone {
+		return
+	}
+	g.setEvent(card.Event)
+
+	switch card.Event {
+	case entities.EventPlague:
+		for _, p := range g.Players {
+			if p.CurrentHand.GetCardDeck(entities.CardTypeWool).Quantity > 0 {
+				g.MoveCards(int(p.Order), -1, entities.CardTypeWool, 1, true, false)
+			}
+		}
+
+	case entities.EventGoodNeighbour:
+		// Cards are chosen before any is given so nobody passes on a gift
+		gifts := make([]*entities.CardType, len(g.Players))
+		for i, p := range g.Players {
+			gifts[i] = p.CurrentHand.ChooseRandomCardType(g.Rand)
+		}
+		for i, t := range gifts {
+			if t != nil {
+				g.MoveCards(i, (i+1)%len(g.Players), *t, 1, true, true)
+			}
+		}
+	}
+}
+
+func (g *Game) setEvent(event entities.EventType) {
+	g.Event = event
+	g.j.WSetEvent(event)
+}
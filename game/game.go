@@ -31,6 +31,7 @@ type (
 		Spectators           []*entities.Player
 		CurrentPlayer        *entities.Player
 		Robber               *entities.Robber
+		Pirate               *entities.Robber
 		Merchant             *entities.Merchant
 		MerchantFleets       [9]int
 		BarbarianPosition    int
@@ -49,6 +50,27 @@ type (
 		SpecialBuildPhase   bool
 		SpecialBuildStarter *entities.Player
 
+		// Seafarers
+		ShipMoved   bool
+		pirateMoved bool
+		islands     map[entities.Coordinate]int
+
+		// Traders & Barbarians
+		Camels     []*entities.Edge
+		Barbarians map[entities.Coordinate]int
+
+		// Event cards drawn instead of rolling the dice
+		EventCardOrder  []int
+		EventCardCursor int
+		Event           entities.EventType
+
+		// Played only by bots, driven by Simulate instead of the Ticker
+		Headless bool
+
+		// Imported from a replay, it can be watched but not played
+		ReadOnly bool
+		async    sync.WaitGroup
+
 		Ticker      *time.Ticker
 		TickerPause bool
 		TickerStop  chan bool
@@ -56,13 +78,20 @@ type (
 
 		DispCoordMap map[entities.Coordinate]entities.FloatCoordinate
 
-		j  Journal
-		ai AI
+		j    Journal
+		bots []BotStrategy
 
 		OfferCounter  int
 		CurrentOffers []*entities.TradeOffer
 
 		DiceStats *entities.DiceStats
+		Stats     *entities.GameStats
+
+		// Seeded from Settings.Seed, use instead of math/rand
+		Rand *rand.Rand
+
+		// The seed was given to the game instead of chosen when it started
+		seedGiven bool
 
 		mutex       sync.Mutex
 		ActionMutex sync.Mutex
@@ -92,14 +121,26 @@ type (
 		WriteGamePrivacy(id string, private bool) error
 		WriteGameSettings(id string, settings []byte) error
 		WriteJournalEntries(id string, entries [][]byte) error
+		TruncateJournal(id string, length int) error
 		WriteGameState(id string, state []byte) error
+		WriteGameReport(id string, report []byte) error
 		WriteGameIdForUser(gameId, userId string, settings *entities.GameSettings) error
+		WriteRatings(ratings []*entities.Rating) error
 		ReadJournal(id string) ([][]byte, error)
 		ReadGamePlayers(id string) (int, error)
 		ReadUser(id string) (map[string]interface{}, error)
+		ReadRatings(userId string) ([]*entities.Rating, error)
+		ReadUserGames(userId string, limit int) ([]*entities.UserGame, error)
+		ReadLeaderboard(mode entities.GameMode, players int, limit int) ([]*entities.Rating, error)
 		GetOfficalMapNames() []string
 		GetAllMapNamesForUser(userId string, exclude bool) ([]string, error)
 		GetMap(name string) *entities.MapDefinition
+		GetMapInfo(name string) *entities.MapInfo
+		GetMapVersion(name string, version int) *entities.MapInfo
+		GetMapVersions(name string) ([]*entities.MapInfo, error)
+		CreateMapInfo(info *entities.MapInfo) (*entities.MapInfo, error)
+		WriteMapInfo(info *entities.MapInfo) (*entities.MapInfo, error)
+		DeleteMap(name string) error
 		CheckIfJournalExists(id string) (bool, error)
 		TerminateGame(id string) error
 	}
@@ -131,7 +172,7 @@ func (game *Game) Initialize(id string, numPlayers uint16) (*Game, error) {
 	}()
 
 	gameMode := game.Settings.Mode
-	if gameMode != entities.Base && gameMode != entities.CitiesAndKnights {
+	if !gameMode.IsValid() {
 		gameMode = entities.Base
 	}
 
@@ -140,7 +181,6 @@ func (game *Game) Initialize(id string, numPlayers uint16) (*Game, error) {
 	game.ID = id
 
 	// Init
-	game.ai.g = game
 	game.j.g = game
 	game.j.Init()
 	if val, err := game.Store.CheckIfJournalExists(id); err == nil && val {
@@ -148,11 +188,13 @@ func (game *Game) Initialize(id string, numPlayers uint16) (*Game, error) {
 	}
 	game.InitPhase = true
 
-	rand.Seed(time.Now().UnixNano())
+	game.initRand()
 
 	// Start game ticker
-	game.Ticker = time.NewTicker(1000 * time.Millisecond)
-	go game.TickWatcher()
+	if !game.Headless {
+		game.Ticker = time.NewTicker(1000 * time.Millisecond)
+		go game.TickWatcher()
+	}
 	game.TimerVals = TimerValues{
 		DiceRoll:     int(10 * entities.SpeedMultiplier[game.Settings.Speed]),
 		Turn:         int(60 * entities.SpeedMultiplier[game.Settings.Speed]),
@@ -178,6 +220,7 @@ func (game *Game) Initialize(id string, numPlayers uint16) (*Game, error) {
 	game.InitGraph()
 	game.InitWithGameMode()
 	game.DiceStats = &entities.DiceStats{}
+	game.Stats = entities.NewGameStats(int(numPlayers))
 
 	// At this point, all data structures should be initialized
 	// Check if journal exists and start processing journal instead if yes
@@ -212,15 +255,18 @@ func (game *Game) InitWithGameMode() error {
 	game.j.WSetGameSettings()
 
 	// Create players
-	players, err := entities.GetNewPlayers(game.Mode, game.NumPlayers)
+	players, err := entities.GetNewPlayers(game.Mode, game.NumPlayers, game.Rand)
 	if err != nil {
 		return err
 	}
+	for _, p := range players {
+		p.Rules = game.rules()
+	}
 	game.Players = players
 	game.CurrentPlayer = players[0]
 
 	// Init bank
-	game.Bank, _ = entities.GetNewBank(game.Mode)
+	game.Bank, _ = entities.GetNewBank(game.Mode, game.Rand)
 
 	// Extra points
 	game.ExtraVictoryPoints = &entities.ExtraVictoryPoints{}
@@ -252,6 +298,17 @@ func (game *Game) InitWithGameMode() error {
 		game.j.WDevelopmentCardOrder(game.Bank.DevelopmentCardOrder[0], 0)
 	}
 
+	if game.isEventCards() {
+		game.shuffleEventCards()
+	}
+
+	if game.Mode == entities.Seafarers {
+		game.ExtraVictoryPoints.SettledIslands = make([]map[int]bool, len(players))
+		for i := range players {
+			game.ExtraVictoryPoints.SettledIslands[i] = make(map[int]bool)
+		}
+	}
+
 	return nil
 }
 
@@ -266,6 +323,10 @@ func (game *Game) InitGraph() {
 		Edges:    game.Edges,
 	}
 	game.Robber = &entities.Robber{}
+	game.Pirate = &entities.Robber{}
+	game.islands = nil
+	game.Camels = nil
+	game.Barbarians = make(map[entities.Coordinate]int)
 	game.CurrentOffers = make([]*entities.TradeOffer, 0)
 }
 
@@ -279,7 +340,9 @@ func (g *Game) Terminate() {
 	}
 
 	g.Initialized = false
-	g.TickerStop <- true
+	if !g.Headless {
+		g.TickerStop <- true
+	}
 
 	for _, p := range g.Players {
 		p.Initialized = false
@@ -331,13 +394,13 @@ func (g *Game) Tick() {
 	}
 
 	// Is the ticker running
-	if g.TickerPause {
+	if g.TickerPause || g.ReadOnly {
 		return
 	}
 
 	g.CurrentPlayer.TimeLeft--
 	if g.CurrentPlayer.TimeLeft > 0 {
-		if g.ai.Tick() {
+		if g.tickBots() {
 			return
 		}
 
@@ -380,6 +443,11 @@ func (g *Game) ReplacePlayer(p *entities.Player) (*entities.Player, error) {
 		return nil, errors.New("game not initialized")
 	}
 
+	// Everyone watches an imported replay
+	if g.ReadOnly {
+		return nil, errors.New("game is read-only")
+	}
+
 	for _, player := range g.Players {
 		if player.Id == p.Id {
 			// Re-initialize the player's channel
@@ -450,6 +518,16 @@ func (g *Game) SetId(p *entities.Player, id string) {
 	g.j.WSetId(p, id)
 }
 
+// Mark a player that a bot took over, the game is then not rated
+// Mutex must be locked
+func (g *Game) SetAbandoned(p *entities.Player) {
+	if p.Abandoned || p.IsSpectator {
+		return
+	}
+	p.Abandoned = true
+	g.j.WSetAbandoned(p)
+}
+
 func (g *Game) SendError(err error, p *entities.Player) {
 	if err != nil {
 		p.SendMessage(&entities.Message{
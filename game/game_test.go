@@ -0,0 +1,70 @@
+package game
+
+import (
+	"imperials/entities"
+	"imperials/memstore"
+	"strconv"
+	"testing"
+)
+
+func testSettings(mode entities.GameMode, seed int64) entities.GameSettings {
+	return entities.GameSettings{
+		Mode:          mode,
+		MapName:       "Base",
+		DiscardLimit:  7,
+		VictoryPoints: 10,
+		MaxPlayers:    4,
+		Speed:         entities.NormalSpeed,
+		Seed:          seed,
+	}
+}
+
+// Headless game of bots that writes its journal to the store
+// Rules are the variants played, nil for the standard rules
+func newTestGame(t *testing.T, store *memstore.MemStore, id string, settings entities.GameSettings, rules *entities.AdvancedSettings, numPlayers int) *Game {
+	t.Helper()
+
+	store.Init(id)
+	g := &Game{Store: store, Headless: true, Settings: settings}
+	if rules != nil {
+		g.Settings.Advanced = true
+		g.AdvancedSettings = *rules
+	}
+	if _, err := g.Initialize(id, uint16(numPlayers)); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, p := range g.Players {
+		g.SetUsername(p, "Bot"+strconv.Itoa(i)+"*")
+		g.SetBotStrategy(p, BotStrategyDefault)
+	}
+	return g
+}
+
+// Game loaded from the journal another game wrote to the store
+func replayTestGame(t *testing.T, store *memstore.MemStore, id string, settings entities.GameSettings, numPlayers int) *Game {
+	t.Helper()
+
+	// The seed comes from the journal
+	settings.Seed = 0
+	g := &Game{Store: store, Headless: true, Settings: settings}
+	if _, err := g.Initialize(id, uint16(numPlayers)); err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+// Play turns of bots until the game is over or has played enough turns
+func playTestTurns(t *testing.T, g *Game, turns int) {
+	t.Helper()
+	if turns == 0 {
+		return
+	}
+
+	err := g.Simulate(turns*1000, func(turn int) bool {
+		return turn < turns
+	})
+	if err != nil && !g.GameOver {
+		t.Fatal(err)
+	}
+}
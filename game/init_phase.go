@@ -71,7 +71,7 @@ func (g *Game) startInitPhase() {
 
 		err = build(g, C)
 		if err != nil {
-			C = g.ai.ChooseBestVertexSettlement(p, AllowedVertices).C
+			C = g.botStrategy(p).ChooseBestVertexSettlement(p, AllowedVertices).C
 			build(g, C)
 		}
 		builtVertex, _ := g.Graph.GetVertex(C)
@@ -116,7 +116,7 @@ func (g *Game) startInitPhase() {
 
 		err = g.BuildRoad(p, C)
 		if err != nil {
-			C = g.ai.ChooseBestEdgeRoad(p, AllowedEdges).C
+			C = g.botStrategy(p).ChooseBestEdgeRoad(p, AllowedEdges).C
 			g.BuildRoad(p, C)
 		}
 	}
@@ -142,7 +142,7 @@ func (g *Game) startInitPhase() {
 	g.j.WSetInitPhase(g.InitPhase)
 	g.SendPlayerSecret(g.CurrentPlayer)
 	g.BroadcastState()
-	g.ai.Reset()
+	g.resetBots()
 }
 
 func (g *Game) simuateInit() {
@@ -21,6 +21,21 @@ type (
 		g       *Game
 		pending chan []byte
 		index   int
+
+		// Every entry of the game, used to clone it
+		log [][]byte
+
+		// Journal index before each action that can be undone,
+		// cleared by any entry written outside such an action
+		undo       []int
+		reversible bool
+
+		// The reversible action being written showed hidden information
+		revealed bool
+
+		// Sequences started, goes on after an undo so that the
+		// rolls and draws of the undone actions are not repeated
+		reseeds int
 	}
 
 	PortEntry struct {
@@ -65,6 +80,12 @@ func (j *Journal) Write(v JournalEntry) {
 		log.Println(err)
 		return
 	}
+	j.log = append(j.log, b)
+	j.g.reseed()
+
+	if !j.reversible {
+		j.undo = nil
+	}
 
 	select {
 	case j.pending <- b:
@@ -104,9 +125,14 @@ func (j *Journal) Play() {
 
 		j.play(&e)
 		j.index = e.Index
+		j.g.reseed()
 	}
+	j.log = byteEntries
 
-	log.Println("Journal replay done")
+	// Clones replay all the time
+	if !j.g.Headless {
+		log.Println("Journal replay done")
+	}
 }
 
 func (j *Journal) setNotPlaying() {
@@ -123,6 +149,11 @@ const (
 	JSetInitPhase          = 1007
 	JSetGameSettings       = 1008
 	JSetAdvancedSettings   = 1009
+	JSetIslandBonus        = 1010
+	JSetRivers             = 1011
+	JEventCardOrder        = 1012
+	JEventCardCursor       = 1013
+	JSetReadOnly           = 1014
 
 	JSetRobber       = 1101
 	JVertexBuild     = 1102
@@ -135,19 +166,37 @@ const (
 	JMerchant        = 1109
 	JGiveProgress    = 1110
 	JMovePlacement   = 1111
+	JSetPirate       = 1112
+	JMoveShip        = 1113
+	JPlaceCamel      = 1114
+	JLandBarbarian   = 1115
 
 	JEndTurn       = 1201
 	JRollDice      = 1202
 	JRollEventDice = 1203
 	JSpecialBuild  = 1204
+	JSetEvent      = 1205
+	JUndo          = 1206
 
 	JUpdateCard              = 1301
 	JUpdateResources         = 1302
 	JUpdateDevelopmentCard   = 1303
 	JReinsertDevelopmentCard = 1304
-
-	JSetUsername = 1401
-	JSetId       = 1402
+	JSetFish                 = 1305
+	JSetGold                 = 1306
+	JSetCardsBought          = 1307
+
+	JSetUsername      = 1401
+	JSetId            = 1402
+	JSetBotStrategy   = 1403
+	JSetBotDifficulty = 1404
+	JSetAbandoned     = 1405
+
+	JCountSteal   = 1501
+	JCountDiscard = 1502
+	JCountTrade   = 1503
+	JCountCard    = 1504
+	JCountTurn    = 1505
 )
 
 func (j *Journal) play(e *JournalEntry) {
@@ -208,10 +257,54 @@ func (j *Journal) play(e *JournalEntry) {
 		j.PSetUsername(e)
 	case JSetId:
 		j.PSetId(e)
+	case JSetBotStrategy:
+		j.PSetBotStrategy(e)
+	case JSetBotDifficulty:
+		j.PSetBotDifficulty(e)
+	case JSetAbandoned:
+		j.PSetAbandoned(e)
 	case JSetGameSettings:
 		j.PSetGameSettings(e)
 	case JSetAdvancedSettings:
 		j.PSetAdvancedSettings(e)
+	case JSetIslandBonus:
+		j.PSetIslandBonus(e)
+	case JSetPirate:
+		j.PSetPirate(e)
+	case JMoveShip:
+		j.PMoveShip(e)
+	case JSetRivers:
+		j.PSetRivers(e)
+	case JPlaceCamel:
+		j.PPlaceCamel(e)
+	case JLandBarbarian:
+		j.PLandBarbarian(e)
+	case JSetFish:
+		j.PSetFish(e)
+	case JSetGold:
+		j.PSetGold(e)
+	case JSetCardsBought:
+		j.PSetDevelopmentCardsBought(e)
+	case JEventCardOrder:
+		j.PEventCardOrder(e)
+	case JEventCardCursor:
+		j.PEventCardCursor(e)
+	case JSetEvent:
+		j.PSetEvent(e)
+	case JUndo:
+		j.PUndo(e)
+	case JSetReadOnly:
+		j.PSetReadOnly(e)
+	case JCountSteal:
+		j.PCountSteal(e)
+	case JCountDiscard:
+		j.PCountDiscard(e)
+	case JCountTrade:
+		j.PCountTrade(e)
+	case JCountCard:
+		j.PCountCard(e)
+	case JCountTurn:
+		j.PCountTurn(e)
 	}
 }
 
@@ -265,6 +358,16 @@ func (j *Journal) WSetRobber(tile *entities.Tile) {
 	j.Write(JournalEntry{Type: JSetRobber, Fields: []interface{}{tile.Center}})
 }
 
+func (j *Journal) PSetPirate(e *JournalEntry) {
+	var center entities.Coordinate
+	mapstructure.Decode(e.Fields[0], &center)
+	j.g.Pirate.Move(j.g.Tiles[center])
+}
+
+func (j *Journal) WSetPirate(tile *entities.Tile) {
+	j.Write(JournalEntry{Type: JSetPirate, Fields: []interface{}{tile.Center}})
+}
+
 func (j *Journal) PVertexBuild(e *JournalEntry) {
 	var C entities.Coordinate
 	var playerOrder uint16
@@ -387,6 +490,11 @@ func (j *Journal) PEdgeBuild(e *JournalEntry) {
 		if err != nil {
 			log.Println("error building road when playing journal: ", err)
 		}
+	case entities.BTShip:
+		err := j.g.BuildShip(player, C)
+		if err != nil {
+			log.Println("error building ship when playing journal: ", err)
+		}
 	}
 }
 
@@ -541,6 +649,24 @@ func (j *Journal) WMovePlacement(from *entities.Vertex, to *entities.Vertex) {
 	}})
 }
 
+func (j *Journal) PMoveShip(e *JournalEntry) {
+	var fromC entities.EdgeCoordinate
+	var toC entities.EdgeCoordinate
+	mapstructure.Decode(e.Fields[0], &fromC)
+	mapstructure.Decode(e.Fields[1], &toC)
+
+	err := j.g.MoveShip(j.g.CurrentPlayer, fromC, toC)
+	if err != nil {
+		log.Println("error moving ship when playing journal: ", err)
+	}
+}
+
+func (j *Journal) WMoveShip(from *entities.Edge, to *entities.Edge) {
+	j.Write(JournalEntry{Type: JMoveShip, Fields: []interface{}{
+		from.C, to.C,
+	}})
+}
+
 func (j *Journal) WEndTurn(p *entities.Player) {
 	j.Write(JournalEntry{Type: JEndTurn, Fields: []interface{}{
 		p.Order,
@@ -799,6 +925,42 @@ func (j *Journal) PSetId(e *JournalEntry) {
 	j.g.Players[order].Id = id
 }
 
+func (j *Journal) WSetBotStrategy(p *entities.Player, name string) {
+	j.Write(JournalEntry{Type: JSetBotStrategy, Fields: []interface{}{
+		p.Order, name,
+	}})
+}
+
+func (j *Journal) PSetBotStrategy(e *JournalEntry) {
+	order := e.Fields[0].(uint16)
+	name := e.Fields[1].(string)
+	j.g.SetBotStrategy(j.g.Players[order], name)
+}
+
+func (j *Journal) WSetBotDifficulty(p *entities.Player, difficulty string) {
+	j.Write(JournalEntry{Type: JSetBotDifficulty, Fields: []interface{}{
+		p.Order, difficulty,
+	}})
+}
+
+func (j *Journal) PSetBotDifficulty(e *JournalEntry) {
+	order := e.Fields[0].(uint16)
+	difficulty := e.Fields[1].(string)
+	j.g.SetBotDifficulty(j.g.Players[order], difficulty)
+}
+
+func (j *Journal) WSetAbandoned(p *entities.Player) {
+	j.Write(JournalEntry{Type: JSetAbandoned, Fields: []interface{}{
+		p.Order,
+	}})
+}
+
+func (j *Journal) PSetAbandoned(e *JournalEntry) {
+	var order uint16
+	mapstructure.Decode(e.Fields[0], &order)
+	j.g.Players[order].Abandoned = true
+}
+
 func (j *Journal) WSetGameSettings() {
 	j.Write(JournalEntry{Type: JSetGameSettings, Fields: []interface{}{
 		j.g.Settings,
@@ -826,3 +988,238 @@ func (j *Journal) PSetAdvancedSettings(e *JournalEntry) {
 
 	j.g.AdvancedSettings = settings
 }
+
+func (j *Journal) WSetIslandBonus(bonus int) {
+	j.Write(JournalEntry{Type: JSetIslandBonus, Fields: []interface{}{
+		bonus,
+	}})
+}
+
+func (j *Journal) PSetIslandBonus(e *JournalEntry) {
+	var bonus int
+	mapstructure.Decode(e.Fields[0], &bonus)
+	j.g.ExtraVictoryPoints.IslandBonus = bonus
+}
+
+func (j *Journal) WSetRivers(coords []entities.EdgeCoordinate) {
+	j.Write(JournalEntry{Type: JSetRivers, Fields: []interface{}{
+		coords,
+	}})
+}
+
+func (j *Journal) PSetRivers(e *JournalEntry) {
+	var coords []entities.EdgeCoordinate
+	mapstructure.Decode(e.Fields[0], &coords)
+	j.g.setRivers(coords)
+}
+
+func (j *Journal) WPlaceCamel(edge *entities.Edge) {
+	j.Write(JournalEntry{Type: JPlaceCamel, Fields: []interface{}{
+		edge.C,
+	}})
+}
+
+func (j *Journal) PPlaceCamel(e *JournalEntry) {
+	var c entities.EdgeCoordinate
+	mapstructure.Decode(e.Fields[0], &c)
+
+	edge, err := j.g.Graph.GetEdge(c)
+	if err != nil {
+		log.Println("error placing camel when playing journal: ", err)
+		return
+	}
+	j.g.placeCamel(edge)
+}
+
+func (j *Journal) WLandBarbarian(tile *entities.Tile) {
+	j.Write(JournalEntry{Type: JLandBarbarian, Fields: []interface{}{
+		tile.Center,
+	}})
+}
+
+func (j *Journal) PLandBarbarian(e *JournalEntry) {
+	var center entities.Coordinate
+	mapstructure.Decode(e.Fields[0], &center)
+
+	if tile, ok := j.g.Tiles[center]; ok {
+		j.g.landBarbarian(tile)
+	}
+}
+
+func (j *Journal) WSetFish(p *entities.Player) {
+	j.Write(JournalEntry{Type: JSetFish, Fields: []interface{}{
+		p.Order, p.Fish,
+	}})
+}
+
+func (j *Journal) PSetFish(e *JournalEntry) {
+	var order uint16
+	var fish int
+	mapstructure.Decode(e.Fields[0], &order)
+	mapstructure.Decode(e.Fields[1], &fish)
+	j.g.Players[order].Fish = fish
+}
+
+func (j *Journal) WSetGold(p *entities.Player) {
+	j.Write(JournalEntry{Type: JSetGold, Fields: []interface{}{
+		p.Order, p.Gold, p.GoldTrades,
+	}})
+}
+
+func (j *Journal) PSetGold(e *JournalEntry) {
+	var order uint16
+	var gold, trades int
+	mapstructure.Decode(e.Fields[0], &order)
+	mapstructure.Decode(e.Fields[1], &gold)
+	mapstructure.Decode(e.Fields[2], &trades)
+	j.g.Players[order].Gold = gold
+	j.g.Players[order].GoldTrades = trades
+}
+
+func (j *Journal) WSetDevelopmentCardsBought(p *entities.Player) {
+	j.Write(JournalEntry{Type: JSetCardsBought, Fields: []interface{}{
+		p.Order, p.DevelopmentCardsBought,
+	}})
+}
+
+func (j *Journal) PSetDevelopmentCardsBought(e *JournalEntry) {
+	var order uint16
+	var count int
+	mapstructure.Decode(e.Fields[0], &order)
+	mapstructure.Decode(e.Fields[1], &count)
+	j.g.Players[order].DevelopmentCardsBought = count
+}
+
+func (j *Journal) WEventCardOrder(order []int) {
+	j.Write(JournalEntry{Type: JEventCardOrder, Fields: []interface{}{
+		order,
+	}})
+}
+
+func (j *Journal) PEventCardOrder(e *JournalEntry) {
+	var order []int
+	err := mapstructure.Decode(e.Fields[0], &order)
+	if err != nil {
+		return
+	}
+
+	j.g.EventCardOrder = order
+	j.g.EventCardCursor = 0
+}
+
+func (j *Journal) WEventCardCursor(cursor int) {
+	j.Write(JournalEntry{Type: JEventCardCursor, Fields: []interface{}{
+		cursor,
+	}})
+}
+
+func (j *Journal) PEventCardCursor(e *JournalEntry) {
+	var cursor int
+	mapstructure.Decode(e.Fields[0], &cursor)
+	j.g.EventCardCursor = cursor
+}
+
+func (j *Journal) WSetEvent(event entities.EventType) {
+	j.Write(JournalEntry{Type: JSetEvent, Fields: []interface{}{
+		event,
+	}})
+}
+
+func (j *Journal) PSetEvent(e *JournalEntry) {
+	var event entities.EventType
+	mapstructure.Decode(e.Fields[0], &event)
+	j.g.Event = event
+}
+
+// Written after the journal is truncated by an undo
+func (j *Journal) WUndo() {
+	j.Write(JournalEntry{Type: JUndo, Fields: []interface{}{
+		j.reseeds,
+	}})
+}
+
+func (j *Journal) PUndo(e *JournalEntry) {
+	var reseeds int
+	mapstructure.Decode(e.Fields[0], &reseeds)
+	j.reseeds = reseeds
+}
+
+// Only added to the journal of imported replays
+func (j *Journal) PSetReadOnly(e *JournalEntry) {
+	j.g.ReadOnly = true
+}
+
+// The card moves of steals, discards, trades and played cards are
+// journaled on their own, these entries only keep the stats.
+// Victory points are kept since extra points are not always updated
+// while playing the journal.
+func (j *Journal) WCountSteal(victim *entities.Player, stealer *entities.Player) {
+	j.Write(JournalEntry{Type: JCountSteal, Fields: []interface{}{
+		victim.Order, stealer.Order,
+	}})
+}
+
+func (j *Journal) PCountSteal(e *JournalEntry) {
+	var victim, stealer uint16
+	mapstructure.Decode(e.Fields[0], &victim)
+	mapstructure.Decode(e.Fields[1], &stealer)
+	j.g.Stats.AddSteal(victim, stealer)
+}
+
+func (j *Journal) WCountDiscard(p *entities.Player, quantity int) {
+	j.Write(JournalEntry{Type: JCountDiscard, Fields: []interface{}{
+		p.Order, quantity,
+	}})
+}
+
+func (j *Journal) PCountDiscard(e *JournalEntry) {
+	var order uint16
+	var quantity int
+	mapstructure.Decode(e.Fields[0], &order)
+	mapstructure.Decode(e.Fields[1], &quantity)
+	j.g.Stats.AddDiscard(order, quantity)
+}
+
+func (j *Journal) WCountTrade(p *entities.Player, partner int, given int, taken int) {
+	j.Write(JournalEntry{Type: JCountTrade, Fields: []interface{}{
+		p.Order, partner, given, taken,
+	}})
+}
+
+func (j *Journal) PCountTrade(e *JournalEntry) {
+	var order uint16
+	var partner, given, taken int
+	mapstructure.Decode(e.Fields[0], &order)
+	mapstructure.Decode(e.Fields[1], &partner)
+	mapstructure.Decode(e.Fields[2], &given)
+	mapstructure.Decode(e.Fields[3], &taken)
+	j.g.Stats.AddTrade(order, partner, given, taken)
+}
+
+func (j *Journal) WCountCard(p *entities.Player, card entities.DevelopmentCardType) {
+	j.Write(JournalEntry{Type: JCountCard, Fields: []interface{}{
+		p.Order, card,
+	}})
+}
+
+func (j *Journal) PCountCard(e *JournalEntry) {
+	var order uint16
+	var card entities.DevelopmentCardType
+	mapstructure.Decode(e.Fields[0], &order)
+	mapstructure.Decode(e.Fields[1], &card)
+	j.g.Stats.AddCardPlayed(order, card)
+}
+
+func (j *Journal) WCountTurn(vps []int) {
+	j.Write(JournalEntry{Type: JCountTurn, Fields: []interface{}{
+		vps,
+	}})
+}
+
+func (j *Journal) PCountTurn(e *JournalEntry) {
+	var vps []int
+	if err := mapstructure.Decode(e.Fields[0], &vps); err != nil {
+		return
+	}
+	j.g.Stats.AddTurn(vps)
+}
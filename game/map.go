@@ -5,8 +5,6 @@ import (
 	"imperials/entities"
 	"log"
 	"math"
-	"math/rand"
-	"time"
 )
 
 const (
@@ -22,6 +20,16 @@ func generateNewMap(g *Game) error {
 	g.generateVertices()
 	g.generateEdges()
 
+	if g.Mode == entities.Seafarers {
+		g.ExtraVictoryPoints.IslandBonus = g.Settings.MapDefn.IslandBonus
+		g.j.WSetIslandBonus(g.ExtraVictoryPoints.IslandBonus)
+	}
+
+	if g.Mode.HasRivers() {
+		g.setRivers(g.Settings.MapDefn.RiverCoordinates)
+		g.j.WSetRivers(g.Settings.MapDefn.RiverCoordinates)
+	}
+
 	return nil
 }
 
@@ -33,13 +41,21 @@ func generateMapInner(g *Game, defn *entities.MapDefinition) error {
 		}
 	}
 
+	for _, row := range defn.TileNumbers {
+		for _, num := range row {
+			if num != 0 && (num < 2 || num > 12 || num == 7) {
+				return errors.New("invalid number")
+			}
+		}
+	}
+
 	// Check if number in tiles is valid tile type
 	tileCount := 0
 	for _, row := range defn.Map {
 		for _, tile := range row {
 			tileCount++
 			tileType := entities.TileType(tile)
-			if tileType < entities.TileTypeDesert || (tileType > entities.TileTypeRandom && tileType != entities.TileTypeGold) {
+			if tileType < entities.TileTypeDesert || (tileType > entities.TileTypeRandom && tileType != entities.TileTypeGold && tileType != entities.TileTypeLake) {
 				return errors.New("invalid tile type")
 			}
 		}
@@ -82,6 +98,9 @@ func generateMapInner(g *Game, defn *entities.MapDefinition) error {
 				if g.Tiles[center].Type == entities.TileTypeFog {
 					g.Tiles[center].Fog = true
 				}
+				if i < len(defn.TileNumbers) && j < len(defn.TileNumbers[i]) {
+					g.Tiles[center].Number = uint16(defn.TileNumbers[i][j])
+				}
 				g.j.WCreateTile(g.Tiles[center], dispX)
 			}
 
@@ -100,6 +119,16 @@ func generateMapInner(g *Game, defn *entities.MapDefinition) error {
 	}
 
 	g.assignTileTypes(defn.RandomTiles)
+
+	// The fishermen fish in a lake where the desert would be
+	if g.Mode.HasFish() {
+		for _, t := range g.Tiles {
+			if t.Type == entities.TileTypeDesert {
+				t.Type = entities.TileTypeLake
+			}
+		}
+	}
+
 	g.assignNumbers(defn.Numbers)
 
 	return nil
@@ -112,7 +141,8 @@ func (g *Game) addTile(center entities.Coordinate, dispX float64) {
 }
 
 func (g *Game) generateVertices() {
-	for _, tile := range g.Tiles {
+	for _, c := range sortedCoordinates(g.Tiles) {
+		tile := g.Tiles[c]
 		theta := math.Pi / 2
 
 		for _, c := range tile.GetVertexCoordinates() {
@@ -145,7 +175,8 @@ func (g *Game) addVertex(c entities.Coordinate) *entities.Vertex {
 }
 
 func (g *Game) generateEdges() {
-	for _, tile := range g.Tiles {
+	for _, c := range sortedCoordinates(g.Tiles) {
+		tile := g.Tiles[c]
 		for i, c := range tile.GetEdgeCoordinates() {
 			edge := g.addEdge(c)
 			edge.Orientation = uint16(i)
@@ -153,9 +184,9 @@ func (g *Game) generateEdges() {
 		}
 	}
 
-	// TODO: tile may also be sea hex
+	// Land next to the edge of the map or to a sea hex
 	for _, e := range g.Edges {
-		e.IsBeach = (len(e.AdjacentTiles) == 1)
+		e.IsBeach = e.HasLand() && (len(e.AdjacentTiles) == 1 || e.HasSea())
 	}
 }
 
@@ -198,14 +229,12 @@ func (g *Game) generatePorts() {
 		}
 	}
 
-	rand.Seed(time.Now().UnixNano())
-
 	for _, portType := range types {
 		if len(beachEdges) == 0 {
 			break
 		}
 
-		edge := beachEdges[rand.Intn(len(beachEdges))]
+		edge := beachEdges[g.Rand.Intn(len(beachEdges))]
 
 		vertex1, err1 := g.Graph.GetVertex(edge.C.C1)
 		vertex2, err2 := g.Graph.GetVertex(edge.C.C2)
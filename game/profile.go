@@ -0,0 +1,189 @@
+package game
+
+import (
+	"imperials/entities"
+	"sort"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+const (
+	// Openings and recent games in a profile
+	ProfileOpenings = 5
+	ProfileGames    = 10
+)
+
+// Profile of a user from the saved states of their games, newest first
+func BuildProfile(userId string, username string, games []*entities.UserGame, ratings []*entities.Rating) *entities.UserProfile {
+	profile := &entities.UserProfile{
+		ID:       userId,
+		Username: username,
+		Modes:    make([]*entities.ProfileRecord, 0),
+		Maps:     make([]*entities.ProfileRecord, 0),
+		Openings: make([]*entities.ProfileOpening, 0),
+		Ratings:  ratings,
+		Games:    make([]*entities.ProfileGame, 0),
+	}
+	if profile.Ratings == nil {
+		profile.Ratings = make([]*entities.Rating, 0)
+	}
+
+	modes := make(map[entities.GameMode]*entities.ProfileRecord)
+	maps := make(map[string]*entities.ProfileRecord)
+	openings := make(map[entities.ProfileOpening]*entities.ProfileOpening)
+	totalVp := 0
+
+	for _, g := range games {
+		var state StoreGameState
+		if err := msgpack.Unmarshal(g.State, &state); err != nil {
+			continue
+		}
+
+		var player *entities.PlayerState
+		for _, p := range state.PlayerStates {
+			if p.Id == userId {
+				player = p
+			}
+		}
+		if player == nil {
+			continue
+		}
+
+		var report *entities.GameReport
+		if g.Report != nil {
+			report = &entities.GameReport{}
+			if err := msgpack.Unmarshal(g.Report, report); err != nil {
+				report = nil
+			}
+		}
+
+		pg := getProfileGame(&state, report, player, ratings)
+		pg.CreatedAt = g.CreatedAt
+		if len(profile.Games) < ProfileGames {
+			profile.Games = append(profile.Games, pg)
+		}
+
+		if !pg.Finished {
+			continue
+		}
+
+		profile.Played++
+		totalVp += pg.VictoryPoints
+		if pg.Won {
+			profile.Won++
+		}
+
+		mode := modes[pg.Mode]
+		if mode == nil {
+			mode = &entities.ProfileRecord{Mode: pg.Mode}
+			modes[pg.Mode] = mode
+			profile.Modes = append(profile.Modes, mode)
+		}
+		addProfileRecord(mode, pg)
+
+		m := maps[pg.Map]
+		if m == nil {
+			m = &entities.ProfileRecord{Map: pg.Map}
+			maps[pg.Map] = m
+			profile.Maps = append(profile.Maps, m)
+		}
+		addProfileRecord(m, pg)
+
+		if report == nil || int(player.Order) >= len(report.Players) {
+			continue
+		}
+		for _, c := range report.Players[player.Order].Openings {
+			key := entities.ProfileOpening{Map: pg.Map, X: c.X, Y: c.Y}
+			o := openings[key]
+			if o == nil {
+				o = &entities.ProfileOpening{Map: pg.Map, X: c.X, Y: c.Y}
+				openings[key] = o
+				profile.Openings = append(profile.Openings, o)
+			}
+			o.Played++
+			if pg.Won {
+				o.Won++
+			}
+		}
+	}
+
+	if profile.Played > 0 {
+		profile.WinRate = float64(profile.Won) / float64(profile.Played)
+		profile.VictoryPoints = float64(totalVp) / float64(profile.Played)
+	}
+
+	// Records add up the points until here
+	for _, r := range append(profile.Modes, profile.Maps...) {
+		r.WinRate = float64(r.Won) / float64(r.Played)
+		r.VictoryPoints /= float64(r.Played)
+	}
+
+	sort.SliceStable(profile.Modes, func(i, j int) bool { return profile.Modes[i].Played > profile.Modes[j].Played })
+	sort.SliceStable(profile.Maps, func(i, j int) bool { return profile.Maps[i].Played > profile.Maps[j].Played })
+	sort.SliceStable(profile.Openings, func(i, j int) bool { return profile.Openings[i].Played > profile.Openings[j].Played })
+	if len(profile.Openings) > ProfileOpenings {
+		profile.Openings = profile.Openings[:ProfileOpenings]
+	}
+
+	return profile
+}
+
+func getProfileGame(state *StoreGameState, report *entities.GameReport, player *entities.PlayerState, ratings []*entities.Rating) *entities.ProfileGame {
+	pg := &entities.ProfileGame{
+		ID:            state.ID,
+		Mode:          state.Settings.Mode,
+		Map:           state.Settings.MapName,
+		Players:       len(state.PlayerStates),
+		Ranked:        state.Settings.Ranked,
+		Finished:      state.GameOver,
+		VictoryPoints: player.VictoryPoints,
+	}
+	if !pg.Mode.IsValid() {
+		pg.Mode = entities.Base
+	}
+	if !pg.Finished {
+		return pg
+	}
+
+	// The report has the points hidden during the game
+	vps := make([]int, len(state.PlayerStates))
+	for i, p := range state.PlayerStates {
+		vps[i] = p.VictoryPoints
+		if report != nil && int(p.Order) < len(report.Players) {
+			if s := report.Players[p.Order].VictoryPoints; len(s) > 0 {
+				vps[i] = s[len(s)-1]
+			}
+		}
+		if p == player {
+			pg.VictoryPoints = vps[i]
+		}
+	}
+
+	// The winner is ahead of everyone even with the same points
+	pg.Won = state.Winner == int(player.Order)
+	pg.Place = 1
+	for i, p := range state.PlayerStates {
+		if !pg.Won && p != player && (vps[i] > pg.VictoryPoints || state.Winner == int(p.Order)) {
+			pg.Place++
+		}
+	}
+
+	for _, r := range ratings {
+		for _, h := range r.History {
+			if h.GameID == state.ID {
+				change := h.Change
+				pg.RatingChange = &change
+			}
+		}
+	}
+
+	return pg
+}
+
+func addProfileRecord(r *entities.ProfileRecord, pg *entities.ProfileGame) {
+	r.Played++
+	r.VictoryPoints += float64(pg.VictoryPoints)
+	if pg.Won {
+		r.Won++
+	}
+}
@@ -0,0 +1,55 @@
+package game
+
+import (
+	"imperials/entities"
+)
+
+// Seed the game random number generator from the settings,
+// choosing a new seed if none was given
+func (g *Game) initRand() {
+	g.seedGiven = g.Settings.Seed != 0
+	if g.Settings.Seed == 0 {
+		g.Settings.Seed = entities.NewSeed()
+	}
+	g.Rand = entities.NewRand(g.Settings.Seed)
+}
+
+// Start a new sequence for each journal entry, written or played
+// A game played again from its journal then continues with the
+// same rolls and draws as the game that wrote it
+func (g *Game) reseed() {
+	g.j.reseeds++
+	g.Rand.Seed(g.Settings.Seed + int64(g.j.reseeds))
+}
+
+// Settings as the players see them, the seed would tell every
+// roll and draw so it is only shown when the game is over
+func (g *Game) GetPublicSettings() entities.GameSettings {
+	settings := g.Settings
+	if !g.GameOver {
+		settings.Seed = 0
+	}
+	return settings
+}
+
+// Pick a random vertex independent of the order of the slice
+func (g *Game) randomVertex(vertices []*entities.Vertex) *entities.Vertex {
+	sorted := make([]*entities.Vertex, len(vertices))
+	copy(sorted, vertices)
+	entities.SortVertices(sorted)
+	return sorted[g.Rand.Intn(len(sorted))]
+}
+
+func (g *Game) randomEdge(edges []*entities.Edge) *entities.Edge {
+	sorted := make([]*entities.Edge, len(edges))
+	copy(sorted, edges)
+	entities.SortEdges(sorted)
+	return sorted[g.Rand.Intn(len(sorted))]
+}
+
+func (g *Game) randomTile(tiles []*entities.Tile) *entities.Tile {
+	sorted := make([]*entities.Tile, len(tiles))
+	copy(sorted, tiles)
+	entities.SortTiles(sorted)
+	return sorted[g.Rand.Intn(len(sorted))]
+}
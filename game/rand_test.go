@@ -0,0 +1,78 @@
+package game
+
+import (
+	"imperials/entities"
+	"imperials/memstore"
+	"strconv"
+	"testing"
+)
+
+func TestReplayContinuesRandomSequence(t *testing.T) {
+	tests := []struct {
+		mode  entities.GameMode
+		turns int
+	}{
+		{entities.Base, 0},
+		{entities.Base, 1},
+		{entities.Base, 8},
+		{entities.CitiesAndKnights, 8},
+		{entities.Seafarers, 8},
+	}
+
+	for i, tt := range tests {
+		store := memstore.NewMemStore()
+		id := "rand-" + strconv.Itoa(i)
+		settings := testSettings(tt.mode, int64(100+i))
+
+		live := newTestGame(t, store, id, settings, nil, 4)
+		playTestTurns(t, live, tt.turns)
+		live.j.Flush()
+
+		replayed := replayTestGame(t, store, id, settings, 4)
+		if replayed.j.index != live.j.index {
+			t.Fatalf("mode %d: replayed %d entries, live game wrote %d", tt.mode, replayed.j.index, live.j.index)
+		}
+		if replayed.Settings.Seed != live.Settings.Seed {
+			t.Fatalf("mode %d: seed %d was not restored from the journal", tt.mode, live.Settings.Seed)
+		}
+
+		for n := 0; n < 5; n++ {
+			lr, lw := live.drawDice(false)
+			rr, rw := replayed.drawDice(false)
+			if lr != rr || lw != rw {
+				t.Fatalf("mode %d after %d turns: live rolled %d %d, replay rolled %d %d", tt.mode, tt.turns, lr, lw, rr, rw)
+			}
+		}
+	}
+}
+
+func TestSameSeedSameGame(t *testing.T) {
+	play := func(id string) (*Game, int) {
+		g := newTestGame(t, memstore.NewMemStore(), id, testSettings(entities.Base, 42), nil, 3)
+		playTestTurns(t, g, 12)
+		return g, g.j.index
+	}
+
+	a, an := play("same-a")
+	b, bn := play("same-b")
+	if an != bn {
+		t.Fatalf("games with the same seed wrote %d and %d entries", an, bn)
+	}
+	for i := range a.Players {
+		if a.GetVictoryPoints(a.Players[i], false) != b.GetVictoryPoints(b.Players[i], false) {
+			t.Fatalf("player %d has different points with the same seed", i)
+		}
+	}
+}
+
+func TestPublicSettingsHideSeed(t *testing.T) {
+	g := &Game{Settings: testSettings(entities.Base, 7)}
+	if s := g.GetPublicSettings(); s.Seed != 0 {
+		t.Fatalf("seed %d shown before the game is over", s.Seed)
+	}
+
+	g.GameOver = true
+	if s := g.GetPublicSettings(); s.Seed != 7 {
+		t.Fatalf("seed not shown after the game is over, got %d", s.Seed)
+	}
+}
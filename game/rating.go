@@ -0,0 +1,77 @@
+package game
+
+import (
+	"imperials/entities"
+	"log"
+	"sync"
+	"time"
+)
+
+// Ratings are read, updated and written back, one game at a time
+// so that games finishing together do not overwrite each other
+var ratingsMutex sync.Mutex
+
+// Only ranked games of the matchmaking queue that humans played
+// to the end with the standard rules are rated
+func (g *Game) isRated() bool {
+	if !g.Settings.Matched || !g.Settings.Ranked || g.ReadOnly || g.seedGiven {
+		return false
+	}
+	if !g.rules().IsDefault() {
+		return false
+	}
+
+	for _, p := range g.Players {
+		if p.Id == "" || p.GetIsBot() || p.Abandoned {
+			return false
+		}
+		if p.Username == "" || p.Username[len(p.Username)-1:] == "*" {
+			return false
+		}
+	}
+	return true
+}
+
+// Update the ratings of the players when the game is over
+// Mutex must be locked
+func (g *Game) updateRatings() {
+	if g.j.playing || !g.isRated() {
+		return
+	}
+
+	// The winner is ahead of everyone even with the same points
+	ids := make([]string, len(g.Players))
+	scores := make([]int, len(g.Players))
+	best := 0
+	for i, p := range g.Players {
+		ids[i] = p.Id
+		scores[i] = g.GetVictoryPoints(p, false)
+		if scores[i] > best {
+			best = scores[i]
+		}
+	}
+	scores[g.GetWinner()] = best + 1
+
+	go writeRatings(g.Store, g.ID, g.Mode, ids, scores)
+}
+
+func writeRatings(store Store, gameId string, mode entities.GameMode, ids []string, scores []int) {
+	ratingsMutex.Lock()
+	defer ratingsMutex.Unlock()
+
+	ratings := make([]*entities.Rating, len(ids))
+	for i, id := range ids {
+		saved, err := store.ReadRatings(id)
+		if err != nil {
+			log.Println("error reading ratings: ", err)
+			return
+		}
+		ratings[i] = entities.FindRating(saved, id, mode, len(ids))
+	}
+
+	entities.UpdateRatings(ratings, scores, gameId, time.Now())
+
+	if err := store.WriteRatings(ratings); err != nil {
+		log.Println("error writing ratings: ", err)
+	}
+}
@@ -0,0 +1,103 @@
+package game
+
+import (
+	"imperials/entities"
+	"imperials/memstore"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestIsRated(t *testing.T) {
+	newGame := func() *Game {
+		g := &Game{Settings: testSettings(entities.Base, 0)}
+		g.Settings.Matched = true
+		g.Settings.Ranked = true
+		g.Rand = entities.NewRand(1)
+		g.Players, _ = entities.GetNewPlayers(entities.Base, 3, g.Rand)
+		for i, p := range g.Players {
+			p.Id = "user" + strconv.Itoa(i)
+			p.Username = "User" + strconv.Itoa(i)
+		}
+		return g
+	}
+
+	tests := []struct {
+		name   string
+		change func(g *Game)
+		want   bool
+	}{
+		{"ranked match", func(g *Game) {}, true},
+		{"standard rules in advanced mode", func(g *Game) { g.Settings.Advanced = true }, true},
+		{"not ranked", func(g *Game) { g.Settings.Ranked = false }, false},
+		{"not from the queue", func(g *Game) { g.Settings.Matched = false }, false},
+		{"read only", func(g *Game) { g.ReadOnly = true }, false},
+		{"seed given", func(g *Game) { g.seedGiven = true }, false},
+		{"variant", func(g *Game) {
+			g.Settings.Advanced = true
+			g.AdvancedSettings.RerollOn7 = true
+		}, false},
+		{"variant that is not played", func(g *Game) { g.AdvancedSettings.RerollOn7 = true }, true},
+		{"custom costs", func(g *Game) {
+			g.Settings.Advanced = true
+			g.AdvancedSettings.Costs.Road = []int{1, 1, 0, 0, 0}
+		}, false},
+		{"bot", func(g *Game) { g.Players[1].SetIsBot(true) }, false},
+		{"bot name", func(g *Game) { g.Players[1].Username = "Bot*" }, false},
+		{"anonymous", func(g *Game) { g.Players[2].Id = "" }, false},
+		{"abandoned", func(g *Game) { g.Players[0].Abandoned = true }, false},
+	}
+
+	for _, tt := range tests {
+		g := newGame()
+		tt.change(g)
+		if got := g.isRated(); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// Store that is slow to read ratings, like a remote database
+type slowRatingsStore struct {
+	*memstore.MemStore
+}
+
+func (s slowRatingsStore) ReadRatings(userId string) ([]*entities.Rating, error) {
+	time.Sleep(time.Millisecond)
+	return s.MemStore.ReadRatings(userId)
+}
+
+func TestWriteRatingsTogether(t *testing.T) {
+	store := memstore.NewMemStore()
+	registry := &memstore.MemRegistry{Store: store}
+	ids := []string{"user0", "user1", "user2"}
+	for _, id := range ids {
+		if err := registry.CreateUser(id, "User"+id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Games finishing together with the same players
+	const games = 20
+	var wg sync.WaitGroup
+	for i := 0; i < games; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			writeRatings(slowRatingsStore{store}, "game"+strconv.Itoa(i), entities.Base, ids, []int{10, 8, 6})
+		}(i)
+	}
+	wg.Wait()
+
+	for _, id := range ids {
+		saved, err := store.ReadRatings(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := entities.FindRating(saved, id, entities.Base, len(ids))
+		if r.Games != games {
+			t.Fatalf("%s has %d rated games, want %d", id, r.Games, games)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package game
+
+import (
+	"errors"
+	"imperials/entities"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Version of the replay file format, bumped on incompatible changes
+const ReplayFormatVersion = 1
+
+type (
+	// A whole game in one file, it is played again from the journal
+	Replay struct {
+		Format     int       `msgpack:"fmt"`
+		ID         string    `msgpack:"id"`
+		ExportedAt time.Time `msgpack:"at"`
+		NumPlayers uint16    `msgpack:"n"`
+		Usernames  []string  `msgpack:"u"`
+		GameOver   bool      `msgpack:"g"`
+		Winner     int       `msgpack:"w"`
+
+		Settings         entities.GameSettings     `msgpack:"s"`
+		AdvancedSettings entities.AdvancedSettings `msgpack:"as"`
+
+		// Definition the game was set up with, if it is still known
+		// The journal alone is enough to rebuild the board
+		Map *entities.MapDefinition `msgpack:"m,omitempty"`
+
+		Entries []JournalEntry `msgpack:"e"`
+
+		// Ids of the players, to check who can export the game
+		// They are not written to the file
+		PlayerIDs []string `msgpack:"-"`
+	}
+)
+
+// Replay of the journal written so far
+// Mutex must be locked
+func (g *Game) ExportReplay() (*Replay, error) {
+	r, err := NewReplay(g.ID, g.NumPlayers, g.j.log)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Map = g.Settings.MapDefn
+	return r, nil
+}
+
+// Replay of a game from the entries of its journal
+// The journal is played to check it and fill in the header
+func NewReplay(id string, numPlayers uint16, log [][]byte) (*Replay, error) {
+	entries := make([]JournalEntry, len(log))
+	for i, b := range log {
+		if err := msgpack.Unmarshal(b, &entries[i]); err != nil {
+			return nil, errors.New("invalid journal entry")
+		}
+	}
+
+	g, err := playJournal(id, numPlayers, log)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Replay{
+		Format:           ReplayFormatVersion,
+		ID:               id,
+		ExportedAt:       time.Now(),
+		NumPlayers:       numPlayers,
+		Usernames:        make([]string, len(g.Players)),
+		PlayerIDs:        make([]string, len(g.Players)),
+		GameOver:         g.GameOver,
+		Winner:           g.GetWinner(),
+		Settings:         g.Settings,
+		AdvancedSettings: g.AdvancedSettings,
+		Entries:          entries,
+	}
+	for i, p := range g.Players {
+		r.Usernames[i] = p.Username
+		r.PlayerIDs[i] = p.Id
+	}
+
+	return r, nil
+}
+
+// Anyone can export a public game, private games can only be
+// exported by their players and their host
+func (r *Replay) CanExport(userId string, hostId string) bool {
+	if userId == "" {
+		return false
+	}
+	if !r.Settings.Private || userId == hostId {
+		return true
+	}
+	for _, id := range r.PlayerIDs {
+		if id == userId {
+			return true
+		}
+	}
+	return false
+}
+
+func DecodeReplay(data []byte) (*Replay, error) {
+	var r Replay
+	if err := msgpack.Unmarshal(data, &r); err != nil {
+		return nil, errors.New("not a replay file")
+	}
+
+	if r.Format != ReplayFormatVersion {
+		return nil, errors.New("unsupported replay format")
+	}
+	if r.NumPlayers < 2 || r.NumPlayers > 6 {
+		return nil, errors.New("invalid number of players")
+	}
+	if len(r.Entries) == 0 {
+		return nil, errors.New("replay has no journal")
+	}
+
+	return &r, nil
+}
+
+func (r *Replay) Encode() ([]byte, error) {
+	return msgpack.Marshal(r)
+}
+
+// Journal of the replay to store for a new game with the given id
+// The game is marked read-only so that it cannot be played on
+func (r *Replay) ImportJournal(id string) ([][]byte, error) {
+	log := make([][]byte, 0, len(r.Entries)+1)
+	for i, e := range r.Entries {
+		// The indexes decide the order of the entries when playing
+		e.Index = i + 1
+		b, err := msgpack.Marshal(e)
+		if err != nil {
+			return nil, err
+		}
+		log = append(log, b)
+	}
+
+	b, err := msgpack.Marshal(JournalEntry{Type: JSetReadOnly, Index: len(log) + 1})
+	if err != nil {
+		return nil, err
+	}
+	log = append(log, b)
+
+	// Make sure the journal plays before anyone joins the game
+	if _, err := playJournal(id, r.NumPlayers, log); err != nil {
+		return nil, err
+	}
+
+	return log, nil
+}
+
+// Headless copy of a game rebuilt from journal entries
+func playJournal(id string, numPlayers uint16, log [][]byte) (g *Game, err error) {
+	// Entries are decoded loosely, a corrupt file must not take the server down
+	defer func() {
+		if recover() != nil {
+			g, err = nil, errors.New("failed to replay journal")
+		}
+	}()
+
+	g = &Game{
+		Store:    &journalStore{entries: log},
+		Headless: true,
+	}
+
+	if _, err := g.Initialize(id, numPlayers); err != nil {
+		return nil, err
+	}
+
+	if g.j.index != len(log) {
+		return nil, errors.New("failed to replay journal")
+	}
+
+	return g, nil
+}
@@ -0,0 +1,134 @@
+package game
+
+import (
+	"imperials/entities"
+	"imperials/memstore"
+	"reflect"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func TestReplayRoundTrip(t *testing.T) {
+	tests := []struct {
+		mode  entities.GameMode
+		turns int
+	}{
+		{entities.Base, 0},
+		{entities.Base, 10},
+		{entities.CitiesAndKnights, 10},
+		{entities.Seafarers, 10},
+	}
+
+	for _, tt := range tests {
+		g := newTestGame(t, memstore.NewMemStore(), "replay-export", testSettings(tt.mode, 31), nil, 3)
+		playTestTurns(t, g, tt.turns)
+
+		exported, err := g.ExportReplay()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := exported.Encode()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		replay, err := DecodeReplay(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if replay.PlayerIDs != nil {
+			t.Errorf("mode %d: player ids are written to the file", tt.mode)
+		}
+		if !reflect.DeepEqual(replay.Usernames, exported.Usernames) || replay.GameOver != g.GameOver {
+			t.Errorf("mode %d: header changed in the file", tt.mode)
+		}
+
+		entries, err := replay.ImportJournal("replay-import")
+		if err != nil {
+			t.Fatal(err)
+		}
+		imported, err := playJournal("replay-import", replay.NumPlayers, entries)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !imported.ReadOnly {
+			t.Errorf("mode %d: imported game can be played on", tt.mode)
+		}
+		if imported.j.index != g.j.index+1 {
+			t.Errorf("mode %d: imported journal is at %d, want %d", tt.mode, imported.j.index, g.j.index+1)
+		}
+		for i, p := range imported.Players {
+			if imported.GetVictoryPoints(p, false) != g.GetVictoryPoints(g.Players[i], false) || p.CurrentHand.GetCardCount() != g.Players[i].CurrentHand.GetCardCount() {
+				t.Errorf("mode %d: player %d differs after import", tt.mode, i)
+			}
+		}
+	}
+}
+
+func TestDecodeReplayErrors(t *testing.T) {
+	encode := func(r Replay) []byte {
+		b, err := msgpack.Marshal(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	entries := []JournalEntry{{Type: JSetReadOnly, Index: 1}}
+
+	tests := []struct {
+		name string
+		data []byte
+		ok   bool
+	}{
+		{"not a replay", []byte("replay"), false},
+		{"other format", encode(Replay{Format: ReplayFormatVersion + 1, NumPlayers: 2, Entries: entries}), false},
+		{"one player", encode(Replay{Format: ReplayFormatVersion, NumPlayers: 1, Entries: entries}), false},
+		{"no journal", encode(Replay{Format: ReplayFormatVersion, NumPlayers: 2}), false},
+		{"valid", encode(Replay{Format: ReplayFormatVersion, NumPlayers: 2, Entries: entries}), true},
+	}
+
+	for _, tt := range tests {
+		if _, err := DecodeReplay(tt.data); (err == nil) != tt.ok {
+			t.Errorf("%s: got error %v", tt.name, err)
+		}
+	}
+}
+
+func TestImportCorruptJournal(t *testing.T) {
+	replay := &Replay{
+		Format:     ReplayFormatVersion,
+		NumPlayers: 3,
+		Entries:    []JournalEntry{{Type: JSetId, Fields: []interface{}{"x"}}},
+	}
+	if _, err := replay.ImportJournal("replay-corrupt"); err == nil {
+		t.Fatal("corrupt journal was imported")
+	}
+}
+
+func TestReplayCanExport(t *testing.T) {
+	public := &Replay{PlayerIDs: []string{"a", "b"}}
+	private := &Replay{PlayerIDs: []string{"a", "b"}}
+	private.Settings.Private = true
+
+	tests := []struct {
+		name   string
+		replay *Replay
+		user   string
+		host   string
+		want   bool
+	}{
+		{"anonymous", public, "", "", false},
+		{"public game", public, "c", "", true},
+		{"private game player", private, "b", "", true},
+		{"private game host", private, "h", "h", true},
+		{"private game other", private, "c", "h", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.replay.CanExport(tt.user, tt.host); got != tt.want {
+			t.Errorf("%s: can export is %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package game
+
+import (
+	"errors"
+)
+
+// Play a headless game to the end, ticking as fast as possible
+// All players must be bots. onTurn is called with the mutex locked
+// after every completed turn, including the winning one, and can
+// return false to stop early.
+// Returns an error if the game is not over after maxTicks ticks.
+func (g *Game) Simulate(maxTicks int, onTurn func(turn int) bool) error {
+	if !g.Headless {
+		return errors.New("only headless games can be simulated")
+	}
+
+	for _, p := range g.Players {
+		if !p.GetIsBot() {
+			return errors.New("all players must be bots")
+		}
+	}
+
+	if g.InitPhase {
+		g.startInitPhase()
+		g.async.Wait()
+	}
+
+	turn := 0
+	lastPlayer := g.CurrentPlayer
+	for i := 0; i < maxTicks; i++ {
+		g.Tick()
+		g.async.Wait()
+
+		// Same interval as the TickWatcher
+		if i%6 == 5 {
+			g.j.Flush()
+		}
+
+		if !g.Lock() {
+			g.Unlock()
+			return errors.New("game terminated")
+		}
+
+		if g.CurrentPlayer != lastPlayer && !g.SpecialBuildPhase {
+			turn++
+			lastPlayer = g.CurrentPlayer
+			if onTurn != nil && !onTurn(turn) {
+				g.Unlock()
+				g.j.Flush()
+				return nil
+			}
+		}
+
+		if g.GameOver {
+			if onTurn != nil {
+				onTurn(turn + 1)
+			}
+			g.Unlock()
+			g.j.Flush()
+			return nil
+		}
+
+		g.Unlock()
+	}
+
+	g.j.Flush()
+	return errors.New("game did not finish")
+}
+
+// Get the order of the winner, or -1 if the game is not over
+func (g *Game) GetWinner() int {
+	if !g.GameOver {
+		return -1
+	}
+	return int(g.CurrentPlayer.Order)
+}
@@ -16,7 +16,7 @@ func (g *Game) GetGameState() *entities.GameState {
 		playerStates[i] = g.GetPlayerState(p)
 	}
 
-	return &entities.GameState{
+	state := &entities.GameState{
 		CurrentPlayerOrder: g.CurrentPlayer.Order,
 		NeedDice:           !g.IsInitPhase() && g.DiceState == 0 && !g.HasPlayerPendingAction(),
 		Robber:             g.Robber,
@@ -27,11 +27,32 @@ func (g *Game) GetGameState() *entities.GameState {
 		BarbarianStrength: g.GetBarbarianStrength(),
 		BarbarianKnights:  g.GetBarbarianKnights(),
 	}
+
+	if g.Mode == entities.Seafarers {
+		state.Pirate = g.Pirate
+	}
+
+	if g.Mode.HasCaravans() {
+		state.Camels = make([]entities.EdgeCoordinate, len(g.Camels))
+		for i, e := range g.Camels {
+			state.Camels[i] = e.C
+		}
+	}
+
+	if g.Mode.HasBarbarianAttack() {
+		state.Barbarians = g.getTileBarbarians()
+	}
+
+	if g.isEventCards() {
+		state.Event = g.Event
+	}
+
+	return state
 }
 
 func (g *Game) GetPlayerState(p *entities.Player) *entities.PlayerState {
 	knights := int16(-1)
-	if g.Mode == entities.Base {
+	if g.Mode.UsesBaseCards() {
 		knights = p.CurrentHand.DevelopmentCardDeckMap[entities.DevelopmentCardKnight].NumUsed
 	} else if g.Mode == entities.CitiesAndKnights {
 		knights = int16(p.GetActivatedKnightStrength())
@@ -54,11 +75,31 @@ func (g *Game) GetPlayerState(p *entities.Player) *entities.PlayerState {
 		Improvements:        p.Improvements,
 		DiscardLimit:        g.GetDiscardLimit(p),
 		IsBot:               p.GetIsBot(),
+		BotDifficulty:       p.BotDifficulty,
 		HasLongestRoad:      g.ExtraVictoryPoints.LongestRoadHolder == p,
 		HasLargestArmy:      g.ExtraVictoryPoints.LargestArmyHolder == p,
+		Fish:                p.Fish,
+		Gold:                p.Gold,
+		CapturedBarbarians:  p.CapturedBarbarians,
+		Cards:               g.getOpenHand(p),
 	}
 }
 
+// Resource cards of the player if hands are open
+func (g *Game) getOpenHand(p *entities.Player) map[entities.CardType]int {
+	if rules := g.rules(); rules == nil || !rules.OpenHands {
+		return nil
+	}
+
+	cards := make(map[entities.CardType]int)
+	for t, deck := range p.CurrentHand.CardDeckMap {
+		if deck.Quantity > 0 {
+			cards[t] = int(deck.Quantity)
+		}
+	}
+	return cards
+}
+
 func (g *Game) GetPlayerSecretState(p *entities.Player) entities.PlayerSecretState {
 	cardData := make(map[entities.CardType]int)
 	for t, deck := range p.CurrentHand.CardDeckMap {
@@ -74,17 +115,23 @@ func (g *Game) GetPlayerSecretState(p *entities.Player) entities.PlayerSecretSta
 	actions := entities.AllowedActionsMap{
 		BuildSettlement:    !busy && p.CanBuild(entities.BTSettlement) == nil && len(p.GetBuildLocationsSettlement(g.Graph, false, false)) > 0,
 		BuildCity:          !busy && p.CanBuild(entities.BTCity) == nil && len(p.GetBuildLocationsCity(g.Graph)) > 0,
-		BuildRoad:          !busy && p.CanBuild(entities.BTRoad) == nil && len(p.GetBuildLocationsRoad(g.Graph, false)) > 0,
+		BuildRoad:          !busy && p.CanBuild(entities.BTRoad) == nil && len(g.GetRoadLocations(p)) > 0,
 		BuyDevelopmentCard: !busy && p.CanBuyDevelopmentCard(),
 		Trade:              !busy && !g.SpecialBuildPhase,
 		EndTurn:            !busy && g.CanEndTurn() == nil,
 
 		BuildKnight:    !busy && (p.CanBuild(entities.BTKnight1) == nil || p.CanBuild(entities.BTKnight2) == nil || p.CanBuild(entities.BTKnight3) == nil),
-		ActivateKnight: !busy && p.CurrentHand.HasResources(0, 0, 0, 1, 0) && len(p.GetActivateLocationsKnight(g.Graph)) > 0,
+		ActivateKnight: !busy && g.Mode == entities.CitiesAndKnights && p.CurrentHand.HasResources(0, 0, 0, 1, 0) && len(p.GetActivateLocationsKnight(g.Graph)) > 0,
 		RobberKnight:   !busy && !g.SpecialBuildPhase && g.KnightChaseRobber(p, true) == nil,
 		MoveKnight:     !busy && !g.SpecialBuildPhase && g.KnightMove(p, true) == nil,
 		BuildWall:      !busy && p.CanBuild(entities.BTWall) == nil && len(p.GetBuildLocationsWall(g.Graph)) > 0,
 
+		BuildShip: !busy && g.Mode == entities.Seafarers && p.CanBuild(entities.BTShip) == nil && len(p.GetBuildLocationsShip(g.Graph, g.Pirate.Tile)) > 0,
+		MoveShip:  !busy && g.CanMoveShip(p) == nil,
+
+		UseFish:   !busy && g.canUseAnyFish(p),
+		SpendGold: !busy && g.CanSpendGold(p) == nil,
+
 		ImprovePaper: p.ChoosingProgressCard || (!busy || p.UsingDevCard == entities.ProgressPaperCrane) && (g.CanBuildImprovement(p, entities.CardTypePaper) == nil),
 		ImproveCloth: p.ChoosingProgressCard || (!busy || p.UsingDevCard == entities.ProgressPaperCrane) && (g.CanBuildImprovement(p, entities.CardTypeCloth) == nil),
 		ImproveCoin:  p.ChoosingProgressCard || (!busy || p.UsingDevCard == entities.ProgressPaperCrane) && (g.CanBuildImprovement(p, entities.CardTypeCoin) == nil),
@@ -109,7 +156,7 @@ func (g *Game) GetPlayerSecretState(p *entities.Player) entities.PlayerSecretSta
 	}
 
 	vp := 0
-	if g.Mode == entities.Base {
+	if g.Mode.UsesBaseCards() {
 		vp = g.GetVictoryPoints(p, false)
 	}
 
@@ -126,11 +173,13 @@ func (g *Game) GetPlayerSecretState(p *entities.Player) entities.PlayerSecretSta
 }
 
 func (g *Game) SetExtraVictoryPoints() {
+	rules := g.rules()
+
 	// Longest Road
 	currentLongestRoad := 0
 	if g.ExtraVictoryPoints.LongestRoadHolder != nil {
 		currentLongestRoad = g.ExtraVictoryPoints.LongestRoadHolder.GetLongestRoad(g.Graph)
-		if currentLongestRoad < 5 {
+		if currentLongestRoad < rules.GetLongestRoadLength() {
 			g.ExtraVictoryPoints.LongestRoadHolder = nil
 		}
 	}
@@ -138,7 +187,7 @@ func (g *Game) SetExtraVictoryPoints() {
 	for _, p := range g.Players {
 		longestRoad := p.GetLongestRoad(g.Graph)
 		p.LongestRoad = longestRoad
-		if longestRoad >= 5 && longestRoad > currentLongestRoad {
+		if longestRoad >= rules.GetLongestRoadLength() && longestRoad > currentLongestRoad {
 			currentLongestRoad = longestRoad
 			g.ExtraVictoryPoints.LongestRoadHolder = p
 			g.BroadcastDevCardUse(entities.CardLongestRoad, DevCardShowTime, int(p.Order))
@@ -146,10 +195,10 @@ func (g *Game) SetExtraVictoryPoints() {
 	}
 
 	// Largest Army
-	if g.Mode == entities.Base {
+	if g.Mode.UsesBaseCards() {
 		for _, p := range g.Players {
 			deck := p.CurrentHand.DevelopmentCardDeckMap[entities.DevelopmentCardKnight]
-			if deck != nil && deck.NumUsed >= 3 && deck.NumUsed > g.ExtraVictoryPoints.LargestArmyCount {
+			if deck != nil && int(deck.NumUsed) >= rules.GetLargestArmySize() && deck.NumUsed > g.ExtraVictoryPoints.LargestArmyCount {
 				g.ExtraVictoryPoints.LargestArmyCount = deck.NumUsed
 				g.ExtraVictoryPoints.LargestArmyHolder = p
 				g.BroadcastDevCardUse(entities.CardLargestArmy, DevCardShowTime, int(p.Order))
@@ -163,12 +212,12 @@ func (g *Game) GetVictoryPoints(p *entities.Player, public bool) int {
 
 	// Longest Road
 	if g.ExtraVictoryPoints.LongestRoadHolder == p {
-		victoryPoints += 2
+		victoryPoints += g.rules().GetLongestRoadVP()
 	}
 
 	// Largest Army
 	if g.ExtraVictoryPoints.LargestArmyHolder == p {
-		victoryPoints += 2
+		victoryPoints += g.rules().GetLargestArmyVP()
 	}
 
 	// Buildings
@@ -180,6 +229,12 @@ func (g *Game) GetVictoryPoints(p *entities.Player, public bool) int {
 		}
 	}
 
+	// Islands
+	victoryPoints += g.GetIslandVictoryPoints(p)
+
+	// Traders & Barbarians
+	victoryPoints += g.GetTradersVictoryPoints(p)
+
 	if g.Mode == entities.CitiesAndKnights {
 		// Defender
 		for _, dp := range g.ExtraVictoryPoints.DefenderPoints {
@@ -240,6 +295,8 @@ func (g *Game) CheckForVictory() {
 		message := entities.GameOverMessage{
 			Players: make([]*entities.PlayerState, 0),
 			Winner:  g.CurrentPlayer.Order,
+			Report:  g.GetReport(),
+			Seed:    g.Settings.Seed,
 		}
 
 		for _, p := range g.Players {
@@ -276,6 +333,10 @@ func (g *Game) CheckForVictory() {
 		})
 		g.Store.WriteGameFinished(g.ID)
 
+		if firstCheck {
+			g.updateRatings()
+		}
+
 		gameState := g.GenerateStoreGameState()
 		if gameState != nil {
 			gameState.Winner = int(g.CurrentPlayer.Order)
@@ -286,5 +347,12 @@ func (g *Game) CheckForVictory() {
 			}
 			g.Store.WriteGameState(g.ID, serialized)
 		}
+
+		report, err := msgpack.Marshal(message.Report)
+		if err != nil {
+			log.Println("error serializing game report: ", err)
+			return
+		}
+		g.Store.WriteGameReport(g.ID, report)
 	}
 }
@@ -0,0 +1,99 @@
+package game
+
+import "imperials/entities"
+
+// Production and blocked production are counted by the dice rolls
+// played again from the journal, and openings by the pieces built
+// again. The other stats are journaled on their own
+
+func (g *Game) countSteal(victim *entities.Player, stealer *entities.Player) {
+	g.j.WCountSteal(victim, stealer)
+	g.Stats.AddSteal(victim.Order, stealer.Order)
+}
+
+func (g *Game) countDiscard(p *entities.Player, quantity int) {
+	g.j.WCountDiscard(p, quantity)
+	g.Stats.AddDiscard(p.Order, quantity)
+}
+
+func (g *Game) countTrade(p *entities.Player, partner int, given int, taken int) {
+	g.j.WCountTrade(p, partner, given, taken)
+	g.Stats.AddTrade(p.Order, partner, given, taken)
+}
+
+func (g *Game) countCardPlayed(p *entities.Player, card entities.DevelopmentCardType) {
+	g.j.WCountCard(p, card)
+	g.Stats.AddCardPlayed(p.Order, card)
+}
+
+func (g *Game) countOpening(p *entities.Player, vertex *entities.Vertex) {
+	g.Stats.AddOpening(p.Order, vertex.C)
+}
+
+// Cards each player can expect from a roll, before it is rolled
+func (g *Game) countExpectedProduction() {
+	for _, c := range sortedCoordinates(g.Tiles) {
+		tile := g.Tiles[c]
+		if tile.Number == 0 || g.Robber.Tile == tile || g.isConquered(tile) {
+			continue
+		}
+		if tile.Type != entities.TileTypeGold && (tile.Type < entities.TileTypeWood || tile.Type > entities.TileTypeOre) {
+			continue
+		}
+
+		// Ways to roll the number with two dice
+		ways := int(tile.Number) - 1
+		if tile.Number > 7 {
+			ways = 13 - int(tile.Number)
+		}
+		chance := float64(ways) / 36
+
+		for _, placement := range g.Graph.GetTilePlacements(tile) {
+			switch placement.GetType() {
+			case entities.BTSettlement:
+				g.Stats.AddExpected(placement.GetOwner().Order, chance)
+			case entities.BTCity:
+				g.Stats.AddExpected(placement.GetOwner().Order, 2*chance)
+			}
+		}
+	}
+}
+
+// Victory points of everyone at the end of a turn
+func (g *Game) countTurn() {
+	if g.j.playing {
+		return
+	}
+
+	vps := make([]int, len(g.Players))
+	for _, p := range g.Players {
+		vps[p.Order] = g.GetVictoryPoints(p, false)
+	}
+	g.j.WCountTurn(vps)
+	g.Stats.AddTurn(vps)
+}
+
+// Report of the game so far, with hidden victory points
+// Mutex must be locked
+func (g *Game) GetReport() *entities.GameReport {
+	report := &entities.GameReport{
+		ID:      g.ID,
+		Winner:  g.GetWinner(),
+		Turns:   g.Stats.Turns + 1,
+		Players: make([]*entities.PlayerStats, len(g.Stats.Players)),
+		Tiles:   g.Stats.GetTiles(),
+		Dice:    g.DiceStats,
+	}
+
+	for i, s := range g.Stats.Players {
+		ps := *s
+		p := g.Players[s.Order]
+		ps.Username = p.Username
+
+		// The last point is the end of the game
+		ps.VictoryPoints = append(append([]int{}, s.VictoryPoints...), g.GetVictoryPoints(p, false))
+		report.Players[i] = &ps
+	}
+
+	return report
+}
@@ -0,0 +1,290 @@
+package game
+
+import (
+	"bytes"
+	"errors"
+	"imperials/entities"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Run an action of the current player that can be taken back with Undo
+// Hidden information (dice, steals, cards) and the end of the turn are
+// written outside such actions, so undo never crosses them
+func (g *Game) Reversible(action func() error) error {
+	if g.j.reversible || g.j.playing {
+		return action()
+	}
+
+	start := g.j.index
+	g.j.reversible = true
+	g.j.revealed = false
+	err := action()
+	g.j.reversible = false
+
+	// Nothing was written, e.g. an offer to other players
+	if g.j.index > start && !g.j.revealed {
+		g.j.undo = append(g.j.undo, start)
+	}
+
+	return err
+}
+
+// Nothing before this point can be undone, the player has seen
+// something that would stay known after the undo
+func (g *Game) preventUndo() {
+	g.j.undo = nil
+	g.j.revealed = true
+}
+
+// Position of the journal, changes with every action
+func (g *Game) JournalIndex() int {
+	return g.j.index
+}
+
+// Check if the last action of the player can be undone
+func (g *Game) CanUndo(p *entities.Player) error {
+	if g.GameOver || g.InitPhase {
+		return errors.New("cannot undo now")
+	}
+
+	if p != g.CurrentPlayer {
+		return errors.New("only the current player can undo")
+	}
+
+	if len(g.j.undo) == 0 {
+		return errors.New("nothing to undo")
+	}
+
+	if g.HasPlayerPendingAction() {
+		return errors.New("wait for player to finish action")
+	}
+
+	return nil
+}
+
+// Roll back to the state before the last reversible action of the
+// current player by replaying the journal up to that point
+// Players keep their objects, only their state is rolled back
+// Mutex must be locked
+func (g *Game) Undo(p *entities.Player) error {
+	if err := g.CanUndo(p); err != nil {
+		return err
+	}
+
+	mark := g.j.undo[len(g.j.undo)-1]
+	c := &Game{
+		Store:            &journalStore{entries: g.j.log[:mark:mark]},
+		Headless:         true,
+		Settings:         g.Settings,
+		AdvancedSettings: g.AdvancedSettings,
+	}
+
+	if _, err := c.Initialize(g.ID, g.NumPlayers); err != nil {
+		return err
+	}
+
+	if c.j.index != mark {
+		return errors.New("failed to replay journal")
+	}
+
+	// The stored journal must match, or a restart would redo the action
+	g.j.Flush()
+	if err := g.Store.TruncateJournal(g.ID, mark); err != nil {
+		return err
+	}
+
+	oldVertices, oldEdges := g.getPlacements()
+	undo := g.j.undo[:len(g.j.undo)-1]
+	g.restore(c)
+	g.j.WUndo()
+	g.j.undo = undo
+
+	g.broadcastPlacementChanges(oldVertices, oldEdges)
+	g.BroadcastMessage(&entities.Message{Type: entities.MessageTypeTradeCloseOffers})
+	for _, player := range g.Players {
+		g.SendPlayerSecret(player)
+	}
+	g.BroadcastState()
+
+	return nil
+}
+
+// Take over the state of a game replayed from the journal
+func (g *Game) restore(c *Game) {
+	// Clients and bots hold the players, the state of the replayed
+	// players is moved to them instead of replacing them
+	player := func(p *entities.Player) *entities.Player {
+		if p == nil {
+			return nil
+		}
+		return g.Players[p.Order]
+	}
+
+	for i, np := range c.Players {
+		op := g.Players[i]
+		op.CurrentHand = np.CurrentHand
+		op.VertexPlacements = np.VertexPlacements
+		op.EdgePlacements = np.EdgePlacements
+		op.PendingAction = nil
+		op.BuildablesLeft = np.BuildablesLeft
+		op.Improvements = np.Improvements
+		op.UsingDevCard = np.UsingDevCard
+		op.ChoosingProgressCard = np.ChoosingProgressCard
+		op.LongestRoad = np.LongestRoad
+		op.DevelopmentCardsBought = np.DevelopmentCardsBought
+		op.Fish = np.Fish
+		op.Gold = np.Gold
+		op.GoldTrades = np.GoldTrades
+		op.CapturedBarbarians = np.CapturedBarbarians
+		op.SpecialBuild = np.SpecialBuild
+		op.Abandoned = op.Abandoned || np.Abandoned
+
+		for _, vp := range op.VertexPlacements {
+			vp.SetOwner(op)
+		}
+		for _, ep := range op.EdgePlacements {
+			ep.SetOwner(op)
+		}
+	}
+
+	evp := c.ExtraVictoryPoints
+	evp.LongestRoadHolder = player(evp.LongestRoadHolder)
+	evp.LargestArmyHolder = player(evp.LargestArmyHolder)
+	evp.PrinterHolder = player(evp.PrinterHolder)
+	evp.ConstitutionHolder = player(evp.ConstitutionHolder)
+	for i, p := range evp.DefenderPoints {
+		evp.DefenderPoints[i] = player(p)
+	}
+	for k, p := range evp.Metropolis {
+		evp.Metropolis[k] = player(p)
+	}
+	if c.Merchant != nil {
+		c.Merchant.Owner = player(c.Merchant.Owner)
+	}
+
+	g.DiceState = c.DiceState
+	g.LastRollWhite = c.LastRollWhite
+	g.LastRollRed = c.LastRollRed
+	g.LastRollEvent = c.LastRollEvent
+
+	g.Bank = c.Bank
+	g.DevelopmentCardOrder = c.DevelopmentCardOrder
+	g.CurrentPlayer = player(c.CurrentPlayer)
+	g.Robber = c.Robber
+	g.Pirate = c.Pirate
+	g.Merchant = c.Merchant
+	g.MerchantFleets = c.MerchantFleets
+	g.BarbarianPosition = c.BarbarianPosition
+	g.NumBarbarianAttacks = c.NumBarbarianAttacks
+
+	g.Vertices = c.Vertices
+	g.Edges = c.Edges
+	g.Tiles = c.Tiles
+	g.Graph = c.Graph
+	g.Ports = c.Ports
+	g.ExtraVictoryPoints = c.ExtraVictoryPoints
+	g.DispCoordMap = c.DispCoordMap
+
+	g.InitPhase = c.InitPhase
+	g.GameOver = c.GameOver
+	g.SpecialBuildPhase = c.SpecialBuildPhase
+	g.SpecialBuildStarter = player(c.SpecialBuildStarter)
+	g.ShipMoved = c.ShipMoved
+	g.islands = nil
+	g.Camels = c.Camels
+	g.Barbarians = c.Barbarians
+	g.EventCardOrder = c.EventCardOrder
+	g.EventCardCursor = c.EventCardCursor
+	g.Event = c.Event
+
+	g.CurrentOffers = make([]*entities.TradeOffer, 0)
+	g.DiceStats = c.DiceStats
+	g.Stats = c.Stats
+
+	// Sequences are still counted from before the undo, starting
+	// over from the index would repeat what followed the undone action
+	g.j.index = c.j.index
+	g.j.log = c.j.log
+
+	// Strategies may keep state about the undone action
+	g.bots = nil
+}
+
+func (g *Game) getPlacements() (map[entities.Coordinate]entities.VertexBuildable, map[entities.EdgeCoordinate]entities.EdgeBuildable) {
+	vertices := make(map[entities.Coordinate]entities.VertexBuildable)
+	edges := make(map[entities.EdgeCoordinate]entities.EdgeBuildable)
+
+	for _, p := range g.Players {
+		for _, vp := range p.VertexPlacements {
+			vertices[vp.GetLocation().C] = vp
+		}
+		for _, ep := range p.EdgePlacements {
+			edges[ep.GetLocation().C] = ep
+		}
+	}
+
+	return vertices, edges
+}
+
+// Send removed and changed placements after a rollback
+func (g *Game) broadcastPlacementChanges(
+	oldVertices map[entities.Coordinate]entities.VertexBuildable,
+	oldEdges map[entities.EdgeCoordinate]entities.EdgeBuildable,
+) {
+	vertices, edges := g.getPlacements()
+
+	for c, vp := range oldVertices {
+		if vertices[c] == nil {
+			g.BroadcastMessage(&entities.Message{
+				Type: entities.MessageTypeVertexPlacementRem,
+				Data: vp,
+			})
+		}
+	}
+
+	for c, vp := range vertices {
+		if !samePlacement(oldVertices[c], vp) {
+			g.BroadcastMessage(&entities.Message{
+				Type: entities.MessageTypeVertexPlacement,
+				Data: vp,
+			})
+		}
+	}
+
+	for c, ep := range oldEdges {
+		if edges[c] == nil {
+			g.BroadcastMessage(&entities.Message{
+				Type: entities.MessageTypeEdgePlacementRem,
+				Data: ep,
+			})
+		}
+	}
+
+	for c, ep := range edges {
+		if !samePlacement(oldEdges[c], ep) {
+			g.BroadcastMessage(&entities.Message{
+				Type: entities.MessageTypeEdgePlacement,
+				Data: ep,
+			})
+		}
+	}
+}
+
+// Compare placements by what the clients see
+func samePlacement(a interface{}, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	ab, err := msgpack.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bb, err := msgpack.Marshal(b)
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(ab, bb)
+}
@@ -0,0 +1,157 @@
+package game
+
+import (
+	"imperials/entities"
+	"imperials/memstore"
+	"reflect"
+	"testing"
+)
+
+// Game after the first turns with the current player able to build
+func newUndoTestGame(t *testing.T, id string, rules *entities.AdvancedSettings) (*Game, *entities.Player) {
+	t.Helper()
+
+	g := newTestGame(t, memstore.NewMemStore(), id, testSettings(entities.Base, 11), rules, 3)
+	playTestTurns(t, g, 4)
+	if g.GameOver || g.InitPhase {
+		t.Fatal("game is not in the middle of a turn")
+	}
+
+	// The player plays by hand from here
+	p := g.CurrentPlayer
+	for _, o := range g.Players {
+		o.SetIsBot(false)
+	}
+	g.DiceState = 1
+	for _, ct := range []entities.CardType{entities.CardTypeWood, entities.CardTypeBrick} {
+		p.CurrentHand.UpdateCards(ct, 4)
+	}
+	return g, p
+}
+
+func buildTestRoad(t *testing.T, g *Game, p *entities.Player, prepare func(e *entities.Edge)) {
+	t.Helper()
+
+	edges := p.GetBuildLocationsRoad(g.Graph, false)
+	if len(edges) == 0 {
+		t.Fatal("no place for a road")
+	}
+	entities.SortEdges(edges)
+	if prepare != nil {
+		prepare(edges[0])
+	}
+
+	if err := g.Reversible(func() error { return g.BuildRoad(p, edges[0].C) }); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUndoBuildRoad(t *testing.T) {
+	g, p := newUndoTestGame(t, "undo-road", nil)
+	roads := len(p.EdgePlacements)
+	cards := p.CurrentHand.GetCardCount()
+	index := g.JournalIndex()
+
+	buildTestRoad(t, g, p, nil)
+	if len(p.EdgePlacements) != roads+1 {
+		t.Fatal("road was not built")
+	}
+
+	if err := g.Undo(p); err != nil {
+		t.Fatal(err)
+	}
+
+	if g.Players[p.Order] != p {
+		t.Fatal("undo replaced the player")
+	}
+	if len(p.EdgePlacements) != roads {
+		t.Fatalf("player has %d roads after undo, had %d", len(p.EdgePlacements), roads)
+	}
+	for _, ep := range p.EdgePlacements {
+		if ep.GetOwner() != p || ep.GetLocation().Placement != ep {
+			t.Fatal("road is not on the board of the player")
+		}
+	}
+	// Cards given by hand in the test are not in the journal
+	if p.CurrentHand.GetCardCount() != cards-8 {
+		t.Fatalf("player has %d cards after undo", p.CurrentHand.GetCardCount())
+	}
+	// The undo itself is journaled
+	if g.JournalIndex() != index+1 {
+		t.Fatalf("journal is at %d after undo, was %d", g.JournalIndex(), index)
+	}
+	if err := g.CanUndo(p); err == nil {
+		t.Fatal("the same action can be undone twice")
+	}
+}
+
+func TestUndoDoesNotRepeatRolls(t *testing.T) {
+	g, p := newUndoTestGame(t, "undo-rand", nil)
+	other, op := newUndoTestGame(t, "undo-rand", nil)
+
+	// The same road with and without an undo before it
+	buildTestRoad(t, g, p, nil)
+	if err := g.Undo(p); err != nil {
+		t.Fatal(err)
+	}
+
+	// The dice and cards given by hand are not in the journal
+	g.DiceState = 1
+	p.CurrentHand.UpdateCards(entities.CardTypeWood, 1)
+	p.CurrentHand.UpdateCards(entities.CardTypeBrick, 1)
+	buildTestRoad(t, g, p, nil)
+	buildTestRoad(t, other, op, nil)
+
+	draws := func(g *Game) []int64 {
+		res := make([]int64, 8)
+		for i := range res {
+			res[i] = g.Rand.Int63()
+		}
+		return res
+	}
+
+	// Replayed before drawing from the game, a restart continues
+	// with the same rolls
+	g.j.Flush()
+	r := replayTestGame(t, g.Store.(*memstore.MemStore), "undo-rand", g.Settings, len(g.Players))
+	rolls := draws(g)
+	if !reflect.DeepEqual(draws(r), rolls) {
+		t.Fatal("replayed game has other rolls after the undo")
+	}
+
+	if reflect.DeepEqual(draws(other), rolls) {
+		t.Fatal("rolls after the undo are the ones the undone action was followed by")
+	}
+}
+
+func TestUndoAfterFogReveal(t *testing.T) {
+	tests := []struct {
+		name string
+		fog  bool
+		want bool
+	}{
+		{"road in the open", false, true},
+		{"road next to fog", true, false},
+	}
+
+	for _, tt := range tests {
+		g, p := newUndoTestGame(t, "undo-fog", nil)
+
+		// An earlier action cannot be undone past the reveal either
+		buildTestRoad(t, g, p, nil)
+		buildTestRoad(t, g, p, func(e *entities.Edge) {
+			if !tt.fog {
+				return
+			}
+			v, err := g.Graph.GetVertex(e.C.C2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			v.AdjacentTiles[0].Fog = true
+		})
+
+		if err := g.CanUndo(p); (err == nil) != tt.want {
+			t.Errorf("%s: can undo is %v, want %v", tt.name, err == nil, tt.want)
+		}
+	}
+}
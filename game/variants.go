@@ -0,0 +1,68 @@
+package game
+
+import (
+	"errors"
+	"imperials/entities"
+)
+
+// Variants chosen by the host, nil for the standard rules
+func (g *Game) rules() *entities.AdvancedSettings {
+	if !g.Settings.Advanced {
+		return nil
+	}
+	return &g.AdvancedSettings
+}
+
+// Pay a cost from rules to the bank
+func (g *Game) payCost(player *entities.Player, cost []int, journal bool) {
+	for i, q := range cost {
+		if q > 0 {
+			g.MoveCards(int(player.Order), -1, entities.CardType(i+1), q, journal, false)
+		}
+	}
+}
+
+// The friendly robber leaves players alone till they have enough points
+func (g *Game) isRobberProtected(p *entities.Player) bool {
+	rules := g.rules()
+	return rules != nil && rules.FriendlyRobber > 0 && g.GetVictoryPoints(p, true) < rules.FriendlyRobber
+}
+
+// The robber or pirate can go to a tile that only touches players it
+// may steal from, the player moving it does not protect the tile
+func (g *Game) isRobberTileAllowed(t *entities.Tile) bool {
+	var victims []*entities.Player
+	if t.IsLand() {
+		for _, vp := range g.Graph.GetTilePlacements(t) {
+			if vp.GetType() == entities.BTSettlement || vp.GetType() == entities.BTCity {
+				victims = append(victims, vp.GetOwner())
+			}
+		}
+	} else {
+		victims = g.getShipOwners(t)
+	}
+
+	for _, p := range victims {
+		if p != g.CurrentPlayer && g.isRobberProtected(p) {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *Game) ensureRobberCanMove() error {
+	if len(g.getRobberTiles()) == 0 {
+		return errors.New("the robber has nowhere to go")
+	}
+	return nil
+}
+
+// Every roll of the first round is made by a different player
+func (g *Game) isFirstRound() bool {
+	return g.numRolls() < len(g.Players)
+}
+
+func (g *Game) setDevelopmentCardsBought(player *entities.Player, count int) {
+	player.DevelopmentCardsBought = count
+	g.j.WSetDevelopmentCardsBought(player)
+}
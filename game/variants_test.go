@@ -0,0 +1,106 @@
+package game
+
+import (
+	"imperials/entities"
+	"testing"
+)
+
+// Players of the tile the robber could steal from, other than the current player
+func robberTileVictims(g *Game, t *entities.Tile) []*entities.Player {
+	victims := make([]*entities.Player, 0)
+	for _, vp := range g.Graph.GetTilePlacements(t) {
+		if vp.GetType() == entities.BTSettlement || vp.GetType() == entities.BTCity {
+			if vp.GetOwner() != g.CurrentPlayer {
+				victims = append(victims, vp.GetOwner())
+			}
+		}
+	}
+	return victims
+}
+
+func TestFriendlyRobberTiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		friendly int
+		blocked  bool
+	}{
+		{"standard rules", 0, false},
+		{"everyone has enough points", 2, false},
+		{"nobody has enough points", 3, true},
+	}
+
+	for _, tt := range tests {
+		g, _ := newUndoTestGame(t, "friendly-robber", nil)
+		g.Settings.Advanced = true
+		g.AdvancedSettings.FriendlyRobber = tt.friendly
+
+		allowed := g.getRobberTiles()
+		if len(allowed) == 0 {
+			t.Fatalf("%s: no tile for the robber", tt.name)
+		}
+
+		blocked := 0
+		for _, c := range sortedCoordinates(g.Tiles) {
+			tile := g.Tiles[c]
+			if tile.Fog || tile == g.Robber.Tile {
+				continue
+			}
+
+			victims := robberTileVictims(g, tile)
+			if containsTile(allowed, tile) == (tt.blocked && len(victims) > 0) {
+				t.Errorf("%s: tile %v with %d victims allowed is %v", tt.name, c, len(victims), containsTile(allowed, tile))
+			}
+			if !containsTile(allowed, tile) {
+				blocked++
+			}
+		}
+		if tt.blocked && blocked == 0 {
+			t.Errorf("%s: no tile is protected", tt.name)
+		}
+	}
+}
+
+func TestFriendlyRobberKeepsKnight(t *testing.T) {
+	tests := []struct {
+		name    string
+		hidden  bool
+		wantErr bool
+	}{
+		{"robber can move", false, false},
+		{"every tile is protected", true, true},
+	}
+
+	for _, tt := range tests {
+		g, p := newUndoTestGame(t, "friendly-knight", nil)
+		g.Settings.Advanced = true
+		g.AdvancedSettings.FriendlyRobber = 3
+
+		// Only tiles of protected players are left in sight
+		if tt.hidden {
+			for _, tile := range g.Tiles {
+				if len(robberTileVictims(g, tile)) == 0 {
+					tile.Fog = true
+				}
+			}
+		}
+
+		knight := p.CurrentHand.GetDevelopmentCardDeck(entities.DevelopmentCardKnight)
+		knight.Quantity = 1
+		knight.CanUse = true
+
+		// The bot places the robber
+		p.SetIsBot(true)
+		g.Lock()
+		err := g.UseDevelopmentCard(p, entities.DevelopmentCardKnight)
+		g.Unlock()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: got error %v", tt.name, err)
+		}
+		if tt.wantErr && (knight.Quantity != 1 || knight.NumUsed != 0) {
+			t.Errorf("%s: knight was used", tt.name)
+		}
+		if !tt.wantErr && knight.Quantity != 0 {
+			t.Errorf("%s: knight was not used", tt.name)
+		}
+	}
+}
@@ -15,6 +15,7 @@ const (
 	GameStatesTable = "game_states"
 	UsersTable      = "users"
 	MapsTable       = "maps"
+	RatingsTable    = "ratings"
 )
 
 const (
@@ -159,3 +160,32 @@ func CreateMapsTable() {
 	collection.Indexes().CreateOne(context.TODO(), official)
 	log.Println("Created table", MapsTable)
 }
+
+func CreateRatingsTable() {
+	db := GetDatabase()
+
+	collection := db.Collection(RatingsTable)
+
+	unique := true
+	user := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "user", Value: 1},
+			{Key: "mode", Value: 1},
+			{Key: "players", Value: 1},
+		},
+		Options: &options.IndexOptions{
+			Unique: &unique,
+		},
+	}
+	collection.Indexes().CreateOne(context.TODO(), user)
+
+	rating := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "mode", Value: 1},
+			{Key: "players", Value: 1},
+			{Key: "rating", Value: -1},
+		},
+	}
+	collection.Indexes().CreateOne(context.TODO(), rating)
+	log.Println("Created table", RatingsTable)
+}
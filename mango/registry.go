@@ -5,23 +5,29 @@ import (
 	"errors"
 	"time"
 
+	"github.com/mitchellh/mapstructure"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type (
-	MangoRegistry struct{}
+	MangoRegistry struct {
+		// Kept with every heartbeat, the server expires without one
+		region string
+	}
 )
 
 func (mr *MangoRegistry) Init() {}
 
 func (mr *MangoRegistry) Register(url, region string) error {
+	mr.region = region
 	CreateServersTable()
 	CreateUsersTable()
 	CreateGamesTable()
 	CreateGameStatesTable()
 	CreateMapsTable()
+	CreateRatingsTable()
 	mr.Heartbeat(url)
 	return nil
 }
@@ -37,6 +43,7 @@ func (mr *MangoRegistry) Heartbeat(url string) error {
 		bson.D{
 			primitive.E{Key: "$set", Value: bson.M{
 				"updatedAt": time.Now(),
+				"region":    mr.region,
 			}},
 			primitive.E{Key: "$setOnInsert", Value: bson.M{
 				"url":       url,
@@ -51,6 +58,35 @@ func (mr *MangoRegistry) Heartbeat(url string) error {
 	return err
 }
 
+// Region of each server by url, servers expire without a heartbeat
+func (mr *MangoRegistry) GetServerRegions() (map[string]string, error) {
+	db := GetDatabase()
+	collection := db.Collection(ServersTable)
+
+	res, err := collection.Find(
+		context.TODO(),
+		bson.D{},
+		&options.FindOptions{Projection: bson.M{"_id": 0, "url": 1, "region": 1}},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var m []map[string]interface{}
+	if err := res.All(context.TODO(), &m); err != nil {
+		return nil, err
+	}
+
+	regions := make(map[string]string)
+	for _, v := range m {
+		var url, region string
+		mapstructure.Decode(v["url"], &url)
+		mapstructure.Decode(v["region"], &region)
+		regions[url] = region
+	}
+	return regions, nil
+}
+
 func (mr *MangoRegistry) CreateUser(id, username string) error {
 	return mr.CreateUserWithEmail(id, username, username+"@imperials.app")
 }
@@ -5,12 +5,14 @@ import (
 	"encoding/json"
 	"errors"
 	"imperials/entities"
+	"imperials/maps"
 	"os"
 	"time"
 
 	"github.com/mitchellh/mapstructure"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
@@ -191,6 +193,31 @@ func (ds *MangoStore) WriteJournalEntries(id string, entries [][]byte) error {
 	return err
 }
 
+func (ds *MangoStore) TruncateJournal(id string, length int) error {
+	db := GetDatabase()
+	collection := db.Collection(GamesTable)
+	_, err := collection.UpdateOne(
+		context.TODO(),
+		bson.D{primitive.E{Key: "id", Value: id}},
+		bson.D{
+			primitive.E{Key: "$push",
+				Value: bson.M{
+					"journal": bson.M{
+						"$each":  bson.A{},
+						"$slice": length,
+					},
+				},
+			},
+			primitive.E{Key: "$set",
+				Value: bson.M{
+					"updatedAt": time.Now(),
+				},
+			},
+		},
+	)
+	return err
+}
+
 func (ds *MangoStore) ReadJournal(id string) ([][]byte, error) {
 	db := GetDatabase()
 	collection := db.Collection(GamesTable)
@@ -352,6 +379,53 @@ func (ds *MangoStore) ReadGameState(id string) ([]byte, error) {
 	return m["state"].([]byte), nil
 }
 
+func (ds *MangoStore) WriteGameReport(id string, report []byte) error {
+	db := GetDatabase()
+	collection := db.Collection(GameStatesTable)
+
+	sid, err := ds.GetGameStateIdFromGameId(id)
+	if err != nil {
+		return errors.New("game state not created")
+	}
+
+	_, err = collection.UpdateOne(
+		context.TODO(),
+		bson.D{primitive.E{Key: "_id", Value: sid}},
+		bson.D{primitive.E{Key: "$set", Value: bson.M{
+			"report": report,
+		}}},
+		&options.UpdateOptions{},
+	)
+	return err
+}
+
+func (ds *MangoStore) ReadGameReport(id string) ([]byte, error) {
+	db := GetDatabase()
+	collection := db.Collection(GameStatesTable)
+
+	sid, err := ds.GetGameStateIdFromGameId(id)
+	if err != nil {
+		return nil, errors.New("game state not created")
+	}
+
+	var m map[string]interface{}
+	res := collection.FindOne(
+		context.TODO(),
+		bson.D{primitive.E{Key: "_id", Value: sid}},
+		&options.FindOneOptions{},
+	)
+	if res == nil {
+		return nil, errors.New("database entry for state not found")
+	}
+	res.Decode(&m)
+
+	report, ok := m["report"].(primitive.Binary)
+	if !ok {
+		return nil, errors.New("game report not found")
+	}
+	return report.Data, nil
+}
+
 func (ds *MangoStore) WriteGameIdForUser(id string, userId string, settings *entities.GameSettings) error {
 	db := GetDatabase()
 	collection := db.Collection(UsersTable)
@@ -418,6 +492,170 @@ func (ds *MangoStore) WriteGameCompletedForUser(id string) error {
 	return err
 }
 
+func (ds *MangoStore) WriteRatings(ratings []*entities.Rating) error {
+	db := GetDatabase()
+	collection := db.Collection(RatingsTable)
+
+	upsert := true
+	for _, r := range ratings {
+		history := make(bson.A, len(r.History))
+		for i, h := range r.History {
+			history[i] = bson.M{
+				"game":   h.GameID,
+				"rating": h.Rating,
+				"change": h.Change,
+				"place":  h.Place,
+				"at":     h.At,
+			}
+		}
+
+		_, err := collection.ReplaceOne(
+			context.TODO(),
+			bson.D{
+				primitive.E{Key: "user", Value: r.UserID},
+				primitive.E{Key: "mode", Value: r.Mode},
+				primitive.E{Key: "players", Value: r.Players},
+			},
+			bson.M{
+				"user":      r.UserID,
+				"mode":      r.Mode,
+				"players":   r.Players,
+				"rating":    r.Rating,
+				"deviation": r.Deviation,
+				"games":     r.Games,
+				"wins":      r.Wins,
+				"updatedAt": r.UpdatedAt,
+				"history":   history,
+			},
+			&options.ReplaceOptions{Upsert: &upsert},
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ds *MangoStore) ReadRatings(userId string) ([]*entities.Rating, error) {
+	db := GetDatabase()
+	collection := db.Collection(RatingsTable)
+
+	res, err := collection.Find(
+		context.TODO(),
+		bson.D{primitive.E{Key: "user", Value: userId}},
+		&options.FindOptions{Projection: bson.M{"_id": 0}},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var m []map[string]interface{}
+	if err := res.All(context.TODO(), &m); err != nil {
+		return nil, err
+	}
+
+	ratings := make([]*entities.Rating, 0, len(m))
+	for _, v := range m {
+		ratings = append(ratings, toRating(v))
+	}
+	return ratings, nil
+}
+
+// Latest games of a user, newest first, all of them without a limit
+func (ds *MangoStore) ReadUserGames(userId string, limit int) ([]*entities.UserGame, error) {
+	db := GetDatabase()
+
+	projection := bson.M{"games": 1}
+	if limit > 0 {
+		projection = bson.M{"games": bson.M{"$slice": -limit}}
+	}
+
+	var u map[string]interface{}
+	res := db.Collection(UsersTable).FindOne(
+		context.TODO(),
+		bson.D{primitive.E{Key: "id", Value: userId}},
+		&options.FindOneOptions{Projection: projection},
+	)
+	if err := res.Decode(&u); err != nil {
+		return nil, errors.New("database entry for user not found")
+	}
+
+	ids, _ := u["games"].(primitive.A)
+	if len(ids) == 0 {
+		return make([]*entities.UserGame, 0), nil
+	}
+
+	cursor, err := db.Collection(GameStatesTable).Find(
+		context.TODO(),
+		bson.M{"_id": bson.M{"$in": ids}},
+		&options.FindOptions{
+			Sort: bson.D{primitive.E{Key: "createdAt", Value: -1}},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var m []map[string]interface{}
+	if err := cursor.All(context.TODO(), &m); err != nil {
+		return nil, err
+	}
+
+	games := make([]*entities.UserGame, 0, len(m))
+	for _, v := range m {
+		g := &entities.UserGame{CreatedAt: toTime(v["createdAt"])}
+		if state, ok := v["state"].(primitive.Binary); ok {
+			g.State = state.Data
+		}
+		if report, ok := v["report"].(primitive.Binary); ok {
+			g.Report = report.Data
+		}
+		games = append(games, g)
+	}
+	return games, nil
+}
+
+func (ds *MangoStore) ReadLeaderboard(mode entities.GameMode, players int, limit int) ([]*entities.Rating, error) {
+	db := GetDatabase()
+	collection := db.Collection(RatingsTable)
+
+	match := bson.M{}
+	if mode != 0 {
+		match["mode"] = mode
+	}
+	if players != 0 {
+		match["players"] = players
+	}
+
+	// Best rating of each user
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$sort": bson.D{{Key: "rating", Value: -1}}},
+		{"$group": bson.M{"_id": "$user", "best": bson.M{"$first": "$$ROOT"}}},
+		{"$replaceRoot": bson.M{"newRoot": "$best"}},
+		{"$sort": bson.D{{Key: "rating", Value: -1}, {Key: "user", Value: 1}}},
+	}
+	if limit > 0 {
+		pipeline = append(pipeline, bson.M{"$limit": limit})
+	}
+
+	cursor, err := collection.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var m []map[string]interface{}
+	if err := cursor.All(context.TODO(), &m); err != nil {
+		return nil, err
+	}
+
+	ratings := make([]*entities.Rating, 0, len(m))
+	for _, v := range m {
+		ratings = append(ratings, toRating(v))
+	}
+	return ratings, nil
+}
+
 func (ds *MangoStore) ReadUser(id string) (map[string]interface{}, error) {
 	db := GetDatabase()
 	collection := db.Collection(UsersTable)
@@ -479,6 +717,7 @@ func (ds *MangoStore) GetAllMapNamesForUser(userId string, exclude bool) ([]stri
 		filter = bson.D{
 			primitive.E{Key: "creator", Value: bson.M{"$ne": userId}},
 			primitive.E{Key: "official", Value: bson.M{"$ne": true}},
+			primitive.E{Key: "private", Value: bson.M{"$ne": true}},
 		}
 	}
 
@@ -529,3 +768,260 @@ func (ds *MangoStore) GetMap(name string) *entities.MapDefinition {
 	}
 	return ans
 }
+
+func (ds *MangoStore) GetMapInfo(name string) *entities.MapInfo {
+	m := ds.findMap(name, bson.M{"_id": 0, "versions": 0})
+	if m == nil {
+		return nil
+	}
+
+	info := toMapInfo(m)
+	info.Defn = toMapDefinition(m["map"])
+	return info
+}
+
+func (ds *MangoStore) GetMapVersion(name string, version int) *entities.MapInfo {
+	m := ds.findMap(name, bson.M{"_id": 0})
+	if m == nil {
+		return nil
+	}
+
+	var versions []map[string]interface{}
+	mapstructure.Decode(m["versions"], &versions)
+	for _, v := range versions {
+		var n int
+		mapstructure.Decode(v["version"], &n)
+		if n == version {
+			info := toMapInfo(m)
+			info.Version = n
+			info.UpdatedAt = toTime(v["createdAt"])
+			info.Defn = toMapDefinition(v["map"])
+			return info
+		}
+	}
+	return nil
+}
+
+// Versions of a map without their definitions, newest first
+func (ds *MangoStore) GetMapVersions(name string) ([]*entities.MapInfo, error) {
+	m := ds.findMap(name, bson.M{"_id": 0, "map": 0, "versions.map": 0})
+	if m == nil {
+		return nil, errors.New("map not found")
+	}
+
+	var versions []map[string]interface{}
+	mapstructure.Decode(m["versions"], &versions)
+
+	ans := make([]*entities.MapInfo, 0, len(versions))
+	for i := len(versions) - 1; i >= 0; i-- {
+		info := toMapInfo(m)
+		mapstructure.Decode(versions[i]["version"], &info.Version)
+		info.UpdatedAt = toTime(versions[i]["createdAt"])
+		ans = append(ans, info)
+	}
+	return ans, nil
+}
+
+// Save the first version of a map, the name must not be taken
+func (ds *MangoStore) CreateMapInfo(info *entities.MapInfo) (*entities.MapInfo, error) {
+	if err := validateMapInfo(info); err != nil {
+		return nil, err
+	}
+
+	db := GetDatabase()
+	collection := db.Collection(MapsTable)
+
+	now := time.Now()
+	defn := fromMapDefinition(info.Defn)
+	saved := &entities.MapInfo{
+		Name:      info.Name,
+		Creator:   info.Creator,
+		Official:  info.Official,
+		Private:   info.Private,
+		Version:   1,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Defn:      info.Defn,
+	}
+
+	// The unique index on the name stops two maps being created at once
+	_, err := collection.InsertOne(context.TODO(), bson.M{
+		"name":      saved.Name,
+		"creator":   saved.Creator,
+		"official":  saved.Official,
+		"private":   saved.Private,
+		"version":   saved.Version,
+		"map":       defn,
+		"createdAt": now,
+		"updatedAt": now,
+		"versions": bson.A{
+			bson.M{"version": saved.Version, "map": defn, "createdAt": now},
+		},
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, entities.ErrMapExists
+	} else if err != nil {
+		return nil, errors.New("could not create map")
+	}
+	return saved, nil
+}
+
+// Save a new version of a map of the creator in info
+// The official flag of the map is kept
+func (ds *MangoStore) WriteMapInfo(info *entities.MapInfo) (*entities.MapInfo, error) {
+	if err := validateMapInfo(info); err != nil {
+		return nil, err
+	}
+
+	db := GetDatabase()
+	collection := db.Collection(MapsTable)
+
+	now := time.Now()
+	defn := fromMapDefinition(info.Defn)
+
+	saved := ds.GetMapInfo(info.Name)
+	if saved == nil || saved.Creator != info.Creator {
+		return nil, entities.ErrMapNotFound
+	}
+
+	// Only update the version that was read, maps saved before versions have none
+	previous := saved.Version
+	saved.Private = info.Private
+	saved.Version++
+	saved.UpdatedAt = now
+	saved.Defn = info.Defn
+
+	res, err := collection.UpdateOne(
+		context.TODO(),
+		bson.D{
+			primitive.E{Key: "name", Value: info.Name},
+			primitive.E{Key: "creator", Value: info.Creator},
+			primitive.E{Key: "version", Value: bson.M{"$in": bson.A{previous, nil}}},
+		},
+		bson.D{
+			primitive.E{
+				Key: "$set",
+				Value: bson.M{
+					"private":   saved.Private,
+					"version":   saved.Version,
+					"map":       defn,
+					"updatedAt": now,
+				},
+			},
+			primitive.E{
+				Key: "$push",
+				Value: bson.M{
+					"versions": bson.M{"version": saved.Version, "map": defn, "createdAt": now},
+				},
+			},
+		},
+	)
+	if err != nil {
+		return nil, errors.New("could not update map")
+	}
+	if res.MatchedCount == 0 {
+		return nil, errors.New("map was changed by someone else")
+	}
+	return saved, nil
+}
+
+func validateMapInfo(info *entities.MapInfo) error {
+	if info.Defn == nil || info.Name == "" || info.Defn.Name != info.Name {
+		return errors.New("map must have a name")
+	}
+	return maps.ValidateMapDefinition(info.Defn)
+}
+
+// Remove a map with all of its versions
+func (ds *MangoStore) DeleteMap(name string) error {
+	db := GetDatabase()
+	collection := db.Collection(MapsTable)
+
+	res, err := collection.DeleteOne(context.TODO(), bson.D{primitive.E{Key: "name", Value: name}})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return errors.New("map not found")
+	}
+	return nil
+}
+
+func (ds *MangoStore) findMap(name string, projection bson.M) map[string]interface{} {
+	db := GetDatabase()
+	collection := db.Collection(MapsTable)
+
+	var m map[string]interface{}
+	err := collection.FindOne(
+		context.TODO(),
+		bson.D{primitive.E{Key: "name", Value: name}},
+		&options.FindOneOptions{Projection: projection},
+	).Decode(&m)
+	if err != nil {
+		return nil
+	}
+	return m
+}
+
+func toMapInfo(m map[string]interface{}) *entities.MapInfo {
+	info := &entities.MapInfo{
+		CreatedAt: toTime(m["createdAt"]),
+		UpdatedAt: toTime(m["updatedAt"]),
+	}
+	mapstructure.Decode(m["name"], &info.Name)
+	mapstructure.Decode(m["creator"], &info.Creator)
+	mapstructure.Decode(m["official"], &info.Official)
+	mapstructure.Decode(m["private"], &info.Private)
+	mapstructure.Decode(m["version"], &info.Version)
+	return info
+}
+
+func toRating(m map[string]interface{}) *entities.Rating {
+	r := &entities.Rating{UpdatedAt: toTime(m["updatedAt"])}
+	mapstructure.Decode(m["user"], &r.UserID)
+	mapstructure.Decode(m["mode"], &r.Mode)
+	mapstructure.Decode(m["players"], &r.Players)
+	mapstructure.Decode(m["rating"], &r.Rating)
+	mapstructure.Decode(m["deviation"], &r.Deviation)
+	mapstructure.Decode(m["games"], &r.Games)
+	mapstructure.Decode(m["wins"], &r.Wins)
+
+	var history []map[string]interface{}
+	mapstructure.Decode(m["history"], &history)
+	r.History = make([]*entities.RatingChange, len(history))
+	for i, h := range history {
+		c := &entities.RatingChange{At: toTime(h["at"])}
+		mapstructure.Decode(h["game"], &c.GameID)
+		mapstructure.Decode(h["rating"], &c.Rating)
+		mapstructure.Decode(h["change"], &c.Change)
+		mapstructure.Decode(h["place"], &c.Place)
+		r.History[i] = c
+	}
+	return r
+}
+
+func toTime(v interface{}) time.Time {
+	if t, ok := v.(primitive.DateTime); ok {
+		return t.Time()
+	}
+	return time.Time{}
+}
+
+// Definitions are kept with their json names, like the maps made by the editor
+func toMapDefinition(v interface{}) *entities.MapDefinition {
+	var ans *entities.MapDefinition
+	marshal, err := json.Marshal(v)
+	if err == nil {
+		json.Unmarshal(marshal, &ans)
+	}
+	return ans
+}
+
+func fromMapDefinition(defn *entities.MapDefinition) map[string]interface{} {
+	var ans map[string]interface{}
+	marshal, err := json.Marshal(defn)
+	if err == nil {
+		json.Unmarshal(marshal, &ans)
+	}
+	return ans
+}
@@ -0,0 +1,151 @@
+package maps
+
+import (
+	"imperials/entities"
+	"math"
+)
+
+// Land tile of a map definition with its position on the board
+type boardTile struct {
+	Row    int
+	Col    int
+	Center entities.Coordinate
+	Type   entities.TileType
+	Number int
+}
+
+// Land tiles of a definition with the numbers fixed by the map
+func getBoardTiles(defn *entities.MapDefinition) []*boardTile {
+	tiles := make([]*boardTile, 0)
+	forEachCell(defn, func(row, col int, center entities.Coordinate, t entities.TileType) {
+		if t == entities.TileTypeNone || t == entities.TileTypeSea {
+			return
+		}
+
+		tile := &boardTile{Row: row, Col: col, Center: center, Type: t}
+		if row < len(defn.TileNumbers) && col < len(defn.TileNumbers[row]) {
+			tile.Number = defn.TileNumbers[row][col]
+		}
+		tiles = append(tiles, tile)
+	})
+	return tiles
+}
+
+// Visit every cell of the map with its center laid out like the game does
+func forEachCell(defn *entities.MapDefinition, f func(row, col int, center entities.Coordinate, t entities.TileType)) {
+	startX, startY := 2, 3
+
+	for i, row := range defn.Map {
+		for j, t := range row {
+			f(i, j, entities.Coordinate{X: startX + 4*j, Y: startY}, entities.TileType(t))
+		}
+
+		if i < len(defn.Order) && defn.Order[i] {
+			startX -= 2
+		} else {
+			startX += 2
+		}
+		startY += 4
+	}
+}
+
+func setBoardTiles(defn *entities.MapDefinition, tiles []*boardTile) {
+	if len(defn.TileNumbers) != len(defn.Map) {
+		defn.TileNumbers = make([][]int, len(defn.Map))
+		for i, row := range defn.Map {
+			defn.TileNumbers[i] = make([]int, len(row))
+		}
+	}
+
+	for _, t := range tiles {
+		defn.Map[t.Row][t.Col] = int(t.Type)
+		defn.TileNumbers[t.Row][t.Col] = t.Number
+	}
+}
+
+// Count the pairs of touching tiles that match
+func countAdjacent(tiles []*boardTile, match func(a, b *boardTile) bool) int {
+	byCenter := make(map[entities.Coordinate]*boardTile)
+	for _, t := range tiles {
+		byCenter[t.Center] = t
+	}
+
+	count := 0
+	for _, t := range tiles {
+		// Only look forward so that each pair is counted once
+		for _, o := range neighbourOffsets[:3] {
+			n := byCenter[entities.Coordinate{X: t.Center.X + o.X, Y: t.Center.Y + o.Y}]
+			if n != nil && match(t, n) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// Dots under a number, the ways to roll it out of 36
+func getPips(n int) int {
+	if n < 2 || n > 12 || n == 7 {
+		return 0
+	}
+	return 6 - int(math.Abs(float64(7-n)))
+}
+
+// Score from 0 to 100 of how fair a board with fixed numbers is
+// Points are lost when the resources do not get their share of the pips,
+// when the best numbers meet at a corner and when tiles or numbers cluster
+func GetMapBalance(defn *entities.MapDefinition) int {
+	tiles := getBoardTiles(defn)
+	if len(tiles) == 0 || len(defn.TileNumbers) == 0 {
+		return 0
+	}
+
+	// Share of the pips of each resource against its share of the tiles
+	tileCount := make(map[entities.TileType]int)
+	pipCount := make(map[entities.TileType]int)
+	numTiles, numPips := 0, 0
+	for _, t := range tiles {
+		if t.Number == 0 {
+			continue
+		}
+		tileCount[t.Type]++
+		pipCount[t.Type] += getPips(t.Number)
+		numTiles++
+		numPips += getPips(t.Number)
+	}
+	if numPips == 0 {
+		return 0
+	}
+
+	deviation := 0.0
+	for t, count := range tileCount {
+		deviation += math.Abs(float64(pipCount[t])/float64(numPips) - float64(count)/float64(numTiles))
+	}
+	score := 100 - 150*deviation
+
+	// Corners where three rich tiles meet
+	corners := make(map[entities.Coordinate]int)
+	for _, t := range tiles {
+		tile := entities.Tile{Center: t.Center}
+		for _, c := range tile.GetVertexCoordinates() {
+			corners[c] += getPips(t.Number)
+		}
+	}
+	for _, pips := range corners {
+		if pips > 11 {
+			score -= 3 * float64(pips-11)
+		}
+	}
+
+	score -= 5 * float64(countAdjacent(tiles, func(a, b *boardTile) bool {
+		return a.Number != 0 && a.Number == b.Number
+	}))
+	score -= 10 * float64(countAdjacent(tiles, func(a, b *boardTile) bool {
+		return a.Type == b.Type && a.Type != entities.TileTypeDesert
+	}))
+	score -= 20 * float64(countAdjacent(tiles, func(a, b *boardTile) bool {
+		return isRedNumber(a.Number) && isRedNumber(b.Number)
+	}))
+
+	return int(math.Max(0, math.Min(100, math.Round(score))))
+}
@@ -0,0 +1,85 @@
+package maps
+
+import (
+	"imperials/entities"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestGetPips(t *testing.T) {
+	tests := []struct {
+		number int
+		pips   int
+	}{
+		{0, 0}, {2, 1}, {3, 2}, {6, 5}, {7, 0}, {8, 5}, {11, 2}, {12, 1}, {13, 0},
+	}
+
+	for _, tt := range tests {
+		if got := getPips(tt.number); got != tt.pips {
+			t.Errorf("%d has %d pips, want %d", tt.number, got, tt.pips)
+		}
+	}
+}
+
+func TestGenerateMap(t *testing.T) {
+	for _, players := range []int{3, 4, 5, 6} {
+		defn := GenerateMap(players, entities.NewRand(int64(players)))
+		if defn == nil {
+			t.Fatalf("%d players: no map", players)
+		}
+
+		if err := ValidateMapDefinition(defn); err != nil {
+			t.Errorf("%d players: %v", players, err)
+		}
+		if !FitsPlayers(defn, players) {
+			t.Errorf("%d players: map has %d tiles", players, len(getBoardTiles(defn)))
+		}
+		if score := GetMapBalance(defn); score < 0 || score > 100 {
+			t.Errorf("%d players: balance %d", players, score)
+		}
+
+		again := GenerateMap(players, entities.NewRand(int64(players)))
+		if !reflect.DeepEqual(defn, again) {
+			t.Errorf("%d players: same seed made another map", players)
+		}
+	}
+}
+
+func TestGetMapBalance(t *testing.T) {
+	fair := GenerateMap(4, entities.NewRand(1))
+
+	// The same tiles with the best numbers next to each other
+	clustered := GenerateMap(4, entities.NewRand(1))
+	tiles := getBoardTiles(clustered)
+	numbers := make([]int, 0, len(tiles))
+	for _, tile := range tiles {
+		if tile.Number != 0 {
+			numbers = append(numbers, tile.Number)
+		}
+	}
+	sort.Slice(numbers, func(i, j int) bool { return getPips(numbers[i]) > getPips(numbers[j]) })
+	for _, tile := range tiles {
+		if tile.Number != 0 {
+			tile.Number, numbers = numbers[0], numbers[1:]
+		}
+	}
+	setBoardTiles(clustered, tiles)
+
+	tests := []struct {
+		name string
+		defn *entities.MapDefinition
+		min  int
+		max  int
+	}{
+		{"random numbers", GetBaseMap(), 0, 0},
+		{"generated", fair, 1, 100},
+		{"clustered", clustered, 0, GetMapBalance(fair) - 1},
+	}
+
+	for _, tt := range tests {
+		if score := GetMapBalance(tt.defn); score < tt.min || score > tt.max {
+			t.Errorf("%s: balance %d, want %d to %d", tt.name, score, tt.min, tt.max)
+		}
+	}
+}
@@ -0,0 +1,347 @@
+package maps
+
+import (
+	"imperials/entities"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Name of the map that is generated for each game
+const GeneratedMapName = "Random Map"
+
+const (
+	// Boards generated before keeping the best one
+	generatorAttempts = 40
+
+	// Shuffles of the tiles and numbers for each shape
+	generatorShuffles = 200
+)
+
+var (
+	// Resources in the proportions of the base game, repeated for bigger boards
+	generatorResources = []entities.TileType{
+		entities.TileTypeWood, entities.TileTypeWool, entities.TileTypeWheat, entities.TileTypeBrick, entities.TileTypeOre,
+		entities.TileTypeWood, entities.TileTypeWool, entities.TileTypeWheat, entities.TileTypeBrick, entities.TileTypeOre,
+		entities.TileTypeWood, entities.TileTypeWool, entities.TileTypeWheat, entities.TileTypeBrick, entities.TileTypeOre,
+		entities.TileTypeWood, entities.TileTypeWool, entities.TileTypeWheat,
+	}
+
+	// Numbers in the proportions of the base game, the middle ones come first
+	generatorNumbers = []int{3, 4, 5, 6, 8, 9, 10, 11, 2, 12}
+
+	// Specific ports, the rest of the ports trade anything
+	generatorPorts = []entities.PortType{
+		entities.PortTypeWood, entities.PortTypeBrick, entities.PortTypeWool, entities.PortTypeWheat, entities.PortTypeOre,
+	}
+
+	// Centers of the neighbours across each edge of a tile
+	neighbourOffsets = []entities.Coordinate{
+		{X: 2, Y: -4}, {X: 4, Y: 0}, {X: 2, Y: 4}, {X: -2, Y: 4}, {X: -4, Y: 0}, {X: -2, Y: -4},
+	}
+)
+
+// Land tiles of a generated board
+func GetGeneratedMapSize(players int) int {
+	if players > 4 {
+		return 30
+	}
+	return 19
+}
+
+// Generate a new board for the number of players
+// The tiles, numbers and ports are fixed so that the board can be scored
+func GenerateMap(players int, r *rand.Rand) *entities.MapDefinition {
+	var best *entities.MapDefinition
+	bestScore := -1
+
+	for i := 0; i < generatorAttempts; i++ {
+		defn := generateShape(GetGeneratedMapSize(players), r)
+		if !assignGeneratedTiles(defn, r) || !assignGeneratedNumbers(defn, r) {
+			continue
+		}
+		assignGeneratedPorts(defn, r)
+
+		if score := GetMapBalance(defn); score > bestScore {
+			best, bestScore = defn, score
+		}
+	}
+
+	return best
+}
+
+// Grow a compact island from the middle of a square grid
+func generateShape(tiles int, r *rand.Rand) *entities.MapDefinition {
+	size := int(math.Sqrt(float64(tiles)))*2 + 1
+	land := make([][]bool, size)
+	for i := range land {
+		land[i] = make([]bool, size)
+	}
+	land[size/2][size/2] = true
+
+	for n := 1; n < tiles; n++ {
+		type cell struct{ row, col int }
+		frontier := make([]cell, 0)
+		weights := make([]float64, 0)
+		total := 0.0
+
+		for row := 0; row < size; row++ {
+			for col := 0; col < size; col++ {
+				if land[row][col] {
+					continue
+				}
+
+				count := 0
+				for _, o := range gridNeighbours(row, col) {
+					if o[0] >= 0 && o[0] < size && o[1] >= 0 && o[1] < size && land[o[0]][o[1]] {
+						count++
+					}
+				}
+				if count == 0 {
+					continue
+				}
+
+				// Cells surrounded by more land are much more likely
+				w := math.Pow(4, float64(count))
+				frontier = append(frontier, cell{row, col})
+				weights = append(weights, w)
+				total += w
+			}
+		}
+
+		x := r.Float64() * total
+		for i, c := range frontier {
+			x -= weights[i]
+			if x < 0 || i == len(frontier)-1 {
+				land[c.row][c.col] = true
+				break
+			}
+		}
+	}
+
+	// Crop to the rows and columns with land
+	minRow, maxRow, minCol, maxCol := size, -1, size, -1
+	for row := 0; row < size; row++ {
+		for col := 0; col < size; col++ {
+			if !land[row][col] {
+				continue
+			}
+			if row < minRow {
+				minRow = row
+			}
+			if row > maxRow {
+				maxRow = row
+			}
+			if col < minCol {
+				minCol = col
+			}
+			if col > maxCol {
+				maxCol = col
+			}
+		}
+	}
+
+	defn := &entities.MapDefinition{Name: GeneratedMapName}
+	for row := minRow; row <= maxRow; row++ {
+		line := make([]int, maxCol-minCol+1)
+		for col := range line {
+			line[col] = int(entities.TileTypeNone)
+			if land[row][minCol+col] {
+				line[col] = int(entities.TileTypeRandom)
+			}
+		}
+		defn.Map = append(defn.Map, line)
+
+		// Odd rows of the grid are shifted to the right
+		defn.Order = append(defn.Order, row%2 == 1)
+	}
+
+	return defn
+}
+
+// Neighbours of a cell of a grid where odd rows are shifted right
+func gridNeighbours(row, col int) [][2]int {
+	shift := row % 2
+	return [][2]int{
+		{row, col - 1}, {row, col + 1},
+		{row - 1, col - 1 + shift}, {row - 1, col + shift},
+		{row + 1, col - 1 + shift}, {row + 1, col + shift},
+	}
+}
+
+// Place the resources so that no two tiles of a resource touch
+// Tiles are filled in a random order with a resource none of their
+// neighbours has, starting again when there is none left
+func assignGeneratedTiles(defn *entities.MapDefinition, r *rand.Rand) bool {
+	tiles := getBoardTiles(defn)
+	deserts := int(math.Round(float64(len(tiles)) / 19))
+
+	for s := 0; s < generatorShuffles; s++ {
+		pool := make([]entities.TileType, 0, len(tiles))
+		for i := 0; len(pool) < len(tiles)-deserts; i++ {
+			pool = append(pool, generatorResources[i%len(generatorResources)])
+		}
+		for i := 0; i < deserts; i++ {
+			pool = append(pool, entities.TileTypeDesert)
+		}
+
+		if fillTiles(tiles, r, func(t *boardTile, neighbours []*boardTile) bool {
+			// Choose among the cards left that none of the neighbours has
+			allowed := make([]int, 0)
+			for i, p := range pool {
+				ok := true
+				for _, n := range neighbours {
+					if n.Type == p && p != entities.TileTypeDesert {
+						ok = false
+						break
+					}
+				}
+				if ok {
+					allowed = append(allowed, i)
+				}
+			}
+			if len(allowed) == 0 {
+				return false
+			}
+
+			i := allowed[r.Intn(len(allowed))]
+			t.Type = pool[i]
+			pool = append(pool[:i], pool[i+1:]...)
+			return true
+		}) {
+			setBoardTiles(defn, tiles)
+			return true
+		}
+	}
+	return false
+}
+
+// Place the numbers so that no 6 or 8 touch each other
+func assignGeneratedNumbers(defn *entities.MapDefinition, r *rand.Rand) bool {
+	tiles := make([]*boardTile, 0)
+	for _, t := range getBoardTiles(defn) {
+		if t.Type != entities.TileTypeDesert {
+			tiles = append(tiles, t)
+		}
+	}
+
+	for s := 0; s < generatorShuffles; s++ {
+		reds, others := make([]int, 0), make([]int, 0)
+		for i := range tiles {
+			if n := generatorNumbers[i%len(generatorNumbers)]; isRedNumber(n) {
+				reds = append(reds, n)
+			} else {
+				others = append(others, n)
+			}
+		}
+
+		if fillTiles(tiles, r, func(t *boardTile, neighbours []*boardTile) bool {
+			nextToRed := false
+			for _, n := range neighbours {
+				nextToRed = nextToRed || isRedNumber(n.Number)
+			}
+
+			// The red numbers go first wherever they fit
+			if len(reds) > 0 && !nextToRed && (len(others) == 0 || r.Intn(len(reds)+len(others)) < len(reds)) {
+				t.Number, reds = reds[0], reds[1:]
+				return true
+			}
+			if len(others) == 0 {
+				return false
+			}
+
+			i := r.Intn(len(others))
+			t.Number = others[i]
+			others = append(others[:i], others[i+1:]...)
+			return true
+		}) {
+			setBoardTiles(defn, tiles)
+			return true
+		}
+	}
+	return false
+}
+
+// Fill the tiles in a random order, the neighbours given are those already filled
+// Returns false if a tile could not be filled
+func fillTiles(tiles []*boardTile, r *rand.Rand, fill func(t *boardTile, neighbours []*boardTile) bool) bool {
+	byCenter := make(map[entities.Coordinate]*boardTile)
+	done := make(map[*boardTile]bool)
+	for _, t := range tiles {
+		byCenter[t.Center] = t
+	}
+
+	for _, i := range r.Perm(len(tiles)) {
+		t := tiles[i]
+		neighbours := make([]*boardTile, 0)
+		for _, o := range neighbourOffsets {
+			if n := byCenter[entities.Coordinate{X: t.Center.X + o.X, Y: t.Center.Y + o.Y}]; n != nil && done[n] {
+				neighbours = append(neighbours, n)
+			}
+		}
+
+		if !fill(t, neighbours) {
+			return false
+		}
+		done[t] = true
+	}
+	return true
+}
+
+// Spread the ports evenly around the coast
+func assignGeneratedPorts(defn *entities.MapDefinition, r *rand.Rand) {
+	tiles := getBoardTiles(defn)
+	centers := make(map[entities.Coordinate]bool)
+	var cx, cy float64
+	for _, t := range tiles {
+		centers[t.Center] = true
+		cx += float64(t.Center.X) / float64(len(tiles))
+		cy += float64(t.Center.Y) / float64(len(tiles))
+	}
+
+	coast := make([]entities.EdgeCoordinate, 0)
+	for _, t := range tiles {
+		tile := entities.Tile{Center: t.Center}
+		for i, ec := range tile.GetEdgeCoordinates() {
+			o := neighbourOffsets[i]
+			if !centers[entities.Coordinate{X: t.Center.X + o.X, Y: t.Center.Y + o.Y}] {
+				coast = append(coast, ec)
+			}
+		}
+	}
+
+	angle := func(ec entities.EdgeCoordinate) float64 {
+		return math.Atan2(float64(ec.C1.Y+ec.C2.Y)/2-cy, float64(ec.C1.X+ec.C2.X)/2-cx)
+	}
+	sort.Slice(coast, func(i, j int) bool { return angle(coast[i]) < angle(coast[j]) })
+
+	generic := int(math.Round(float64(len(tiles)) * 4 / 19))
+	ports := append([]entities.PortType{}, generatorPorts...)
+	for i := 0; i < generic; i++ {
+		ports = append(ports, entities.PortTypeAny)
+	}
+
+	used := make(map[entities.Coordinate]bool)
+	offset := r.Intn(len(coast))
+	for i := range ports {
+		// Take the next free edge if the ideal one touches a port
+		for j := 0; j < len(coast); j++ {
+			ec := coast[(offset+i*len(coast)/len(ports)+j)%len(coast)]
+			if !used[ec.C1] && !used[ec.C2] {
+				used[ec.C1], used[ec.C2] = true, true
+				defn.PortCoordinates = append(defn.PortCoordinates, ec)
+				defn.Ports = append(defn.Ports, ports[i])
+				break
+			}
+		}
+	}
+}
+
+func isRedNumber(n int) bool {
+	return n == 6 || n == 8
+}
+
+// Generated boards are made again when they do not fit the players any more
+func FitsPlayers(defn *entities.MapDefinition, players int) bool {
+	return len(getBoardTiles(defn)) == GetGeneratedMapSize(players)
+}
@@ -5,13 +5,24 @@ import (
 	"imperials/entities"
 )
 
+// The two rivers of the base map only flow in the rivers scenario
 func GetBaseMap() *entities.MapDefinition {
 	var defn entities.MapDefinition
 	json.Unmarshal([]byte(baseMap), &defn)
 	return &defn
 }
 
-const baseMap = `{"name":"Base","order":[false,true,false,true,false],"ports":[6,6,6,6,1,2,3,4,5],"port_coordinates":[{"C1":{"X":2,"Y":8},"C2":{"X":2,"Y":6}},{"C1":{"X":4,"Y":2},"C2":{"X":6,"Y":0}},{"C1":{"X":10,"Y":0},"C2":{"X":12,"Y":2}},{"C1":{"X":16,"Y":4},"C2":{"X":18,"Y":6}},{"C1":{"X":20,"Y":10},"C2":{"X":20,"Y":12}},{"C1":{"X":18,"Y":16},"C2":{"X":16,"Y":18}},{"C1":{"X":12,"Y":20},"C2":{"X":10,"Y":22}},{"C1":{"X":6,"Y":22},"C2":{"X":4,"Y":20}},{"C1":{"X":2,"Y":16},"C2":{"X":2,"Y":14}}],"numbers":[2,3,3,4,4,5,5,6,6,8,8,9,9,10,10,11,11,12],"tiles":[0,1,1,1,1,2,2,2,3,3,3,3,4,4,4,4,5,5,5],"map":[[8,9,9,9,8],[9,9,9,9,8],[9,9,9,9,9],[9,9,9,9,8],[8,9,9,9,8]]}`
+// Scenario for seafarers, a main island with two small islands
+// that can only be reached by ship
+func GetSeafarersMap() *entities.MapDefinition {
+	var defn entities.MapDefinition
+	json.Unmarshal([]byte(seafarersMap), &defn)
+	return &defn
+}
+
+const baseMap = `{"name":"Base","order":[false,true,false,true,false],"ports":[6,6,6,6,1,2,3,4,5],"port_coordinates":[{"C1":{"X":2,"Y":8},"C2":{"X":2,"Y":6}},{"C1":{"X":4,"Y":2},"C2":{"X":6,"Y":0}},{"C1":{"X":10,"Y":0},"C2":{"X":12,"Y":2}},{"C1":{"X":16,"Y":4},"C2":{"X":18,"Y":6}},{"C1":{"X":20,"Y":10},"C2":{"X":20,"Y":12}},{"C1":{"X":18,"Y":16},"C2":{"X":16,"Y":18}},{"C1":{"X":12,"Y":20},"C2":{"X":10,"Y":22}},{"C1":{"X":6,"Y":22},"C2":{"X":4,"Y":20}},{"C1":{"X":2,"Y":16},"C2":{"X":2,"Y":14}}],"numbers":[2,3,3,4,4,5,5,6,6,8,8,9,9,10,10,11,11,12],"tiles":[0,1,1,1,1,2,2,2,3,3,3,3,4,4,4,4,5,5,5],"map":[[8,9,9,9,8],[9,9,9,9,8],[9,9,9,9,9],[9,9,9,9,8],[8,9,9,9,8]],"river_coordinates":[{"C1":{"X":8,"Y":2},"C2":{"X":8,"Y":4}},{"C1":{"X":8,"Y":4},"C2":{"X":10,"Y":6}},{"C1":{"X":10,"Y":6},"C2":{"X":10,"Y":8}},{"C1":{"X":12,"Y":20},"C2":{"X":12,"Y":18}},{"C1":{"X":12,"Y":18},"C2":{"X":10,"Y":16}},{"C1":{"X":10,"Y":16},"C2":{"X":10,"Y":14}}]}`
+
+const seafarersMap = `{"name":"New Shores","island_bonus":2,"order":[false,true,false,true,false,true,false],"ports":[6,6,6,6,1,2,3,4,5],"numbers":[2,3,3,4,4,5,5,5,6,6,8,8,9,9,9,10,10,10,11,11,12],"tiles":[1,1,1,1,1,2,2,2,2,3,3,3,3,4,4,4,4,5,5,5,5],"map":[[7,7,7,7,7,7,7],[7,9,9,9,7,7,7],[7,9,9,9,9,7,9],[7,9,0,9,7,7,9],[7,9,9,9,9,7,7],[7,9,9,9,7,9,7],[7,7,7,7,7,9,9]]}`
 
 const baseBig = `{
 		"name": "Base Big",
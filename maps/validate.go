@@ -0,0 +1,253 @@
+package maps
+
+import (
+	"fmt"
+	"imperials/entities"
+	"strings"
+)
+
+type (
+	// Problem with one field of a map definition
+	MapError struct {
+		Field string `json:"field"`
+
+		// Row and column in the map, or the position in a list
+		Index []int `json:"index,omitempty"`
+
+		Message string `json:"message"`
+	}
+
+	// Every problem found in a map definition
+	MapErrors []*MapError
+)
+
+const minLandTiles = 5
+
+func (e *MapError) Error() string {
+	if len(e.Index) == 0 {
+		return e.Field + ": " + e.Message
+	}
+
+	index := make([]string, len(e.Index))
+	for i, v := range e.Index {
+		index[i] = fmt.Sprint(v)
+	}
+	return e.Field + "[" + strings.Join(index, "][") + "]: " + e.Message
+}
+
+func (e MapErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Check that a map definition makes a playable board
+// Returns nil or MapErrors with every problem that was found
+func ValidateMapDefinition(defn *entities.MapDefinition) error {
+	if defn == nil {
+		return MapErrors{{Field: "map", Message: "map is missing"}}
+	}
+
+	var errs MapErrors
+	add := func(field string, message string, index ...int) {
+		errs = append(errs, &MapError{Field: field, Index: index, Message: message})
+	}
+
+	if strings.TrimSpace(defn.Name) == "" {
+		add("name", "map must have a name")
+	}
+
+	if len(defn.Order) != len(defn.Map) {
+		add("order", fmt.Sprintf("has %d entries for %d rows", len(defn.Order), len(defn.Map)))
+	}
+
+	// Tiles of the map
+	land := make(map[entities.Coordinate]bool)
+	cells := make(map[entities.Coordinate]entities.TileType)
+	randomCells, numbered := 0, 0
+	forEachCell(defn, func(row, col int, center entities.Coordinate, t entities.TileType) {
+		switch {
+		case t == entities.TileTypeNone:
+			return
+		case t == entities.TileTypeRandom || t == entities.TileTypeFog:
+			randomCells++
+		case isProducingTile(t):
+			numbered++
+		case t != entities.TileTypeDesert && t != entities.TileTypeSea && t != entities.TileTypeLake:
+			add("map", fmt.Sprintf("invalid tile type %d", t), row, col)
+			return
+		}
+
+		cells[center] = t
+		if t != entities.TileTypeSea {
+			land[center] = true
+		}
+	})
+
+	if len(land) < minLandTiles {
+		add("map", fmt.Sprintf("needs at least %d land tiles", minLandTiles))
+	}
+
+	if islands := countRegions(cells); islands > 1 {
+		add("map", fmt.Sprintf("is split in %d parts that cannot reach each other", islands))
+	}
+
+	// Tiles that are shuffled onto the random tiles
+	pooled := 0
+	for i, t := range defn.RandomTiles {
+		if t != entities.TileTypeDesert && !isProducingTile(t) {
+			add("tiles", fmt.Sprintf("invalid tile type %d", t), i)
+		} else if t != entities.TileTypeDesert {
+			pooled++
+		}
+	}
+	if len(defn.RandomTiles) != randomCells {
+		add("tiles", fmt.Sprintf("has %d tiles for %d random tiles in the map", len(defn.RandomTiles), randomCells))
+	}
+
+	// Numbers fixed on the tiles
+	for i, row := range defn.TileNumbers {
+		for j, n := range row {
+			if n == 0 {
+				continue
+			}
+
+			if i >= len(defn.Map) || j >= len(defn.Map[i]) || !isProducingTile(entities.TileType(defn.Map[i][j])) {
+				add("tile_numbers", "numbers can only be fixed on resource or gold tiles", i, j)
+			} else if !isValidNumber(n) {
+				add("tile_numbers", fmt.Sprintf("invalid number %d", n), i, j)
+			} else {
+				numbered--
+			}
+		}
+	}
+
+	for i, n := range defn.Numbers {
+		if !isValidNumber(int(n)) {
+			add("numbers", fmt.Sprintf("invalid number %d", n), i)
+		}
+	}
+	if len(defn.Numbers) != numbered+pooled {
+		add("numbers", fmt.Sprintf("has %d numbers for %d tiles", len(defn.Numbers), numbered+pooled))
+	}
+
+	// Ports go on the coast
+	coast := getCoastalEdges(defn, land)
+	for i, t := range defn.Ports {
+		if t < entities.PortTypeWood || t > entities.PortTypeAny {
+			add("ports", fmt.Sprintf("invalid port type %d", t), i)
+		}
+	}
+
+	if len(defn.PortCoordinates) > 0 && len(defn.PortCoordinates) < len(defn.Ports) {
+		add("port_coordinates", fmt.Sprintf("has %d places for %d ports", len(defn.PortCoordinates), len(defn.Ports)))
+	}
+
+	seen := make(map[entities.EdgeCoordinate]bool)
+	for i, ec := range defn.PortCoordinates {
+		ec = normalizeEdge(ec)
+		if !coast[ec] {
+			add("port_coordinates", "not an edge on the coast", i)
+		} else if seen[ec] {
+			add("port_coordinates", "used twice", i)
+		}
+		seen[ec] = true
+	}
+
+	// Rivers flow between land tiles
+	edges := getLandEdges(land)
+	for i, ec := range defn.RiverCoordinates {
+		if !edges[normalizeEdge(ec)] {
+			add("river_coordinates", "not an edge of a land tile", i)
+		}
+	}
+
+	if defn.IslandBonus < 0 {
+		add("island_bonus", "cannot be negative")
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Tiles that produce and get a number
+func isProducingTile(t entities.TileType) bool {
+	return (t >= entities.TileTypeWood && t <= entities.TileTypeOre) || t == entities.TileTypeGold
+}
+
+func isValidNumber(n int) bool {
+	return n >= 2 && n <= 12 && n != 7
+}
+
+func normalizeEdge(ec entities.EdgeCoordinate) entities.EdgeCoordinate {
+	if ec.C2.Less(ec.C1) {
+		return entities.EdgeCoordinate{C1: ec.C2, C2: ec.C1}
+	}
+	return ec
+}
+
+// Edges of land tiles that do not have land on the other side
+func getCoastalEdges(defn *entities.MapDefinition, land map[entities.Coordinate]bool) map[entities.EdgeCoordinate]bool {
+	coast := make(map[entities.EdgeCoordinate]bool)
+	for c := range land {
+		tile := entities.Tile{Center: c}
+		for i, ec := range tile.GetEdgeCoordinates() {
+			o := neighbourOffsets[i]
+			if !land[entities.Coordinate{X: c.X + o.X, Y: c.Y + o.Y}] {
+				coast[normalizeEdge(ec)] = true
+			}
+		}
+	}
+	return coast
+}
+
+func getLandEdges(land map[entities.Coordinate]bool) map[entities.EdgeCoordinate]bool {
+	edges := make(map[entities.EdgeCoordinate]bool)
+	for c := range land {
+		tile := entities.Tile{Center: c}
+		for _, ec := range tile.GetEdgeCoordinates() {
+			edges[normalizeEdge(ec)] = true
+		}
+	}
+	return edges
+}
+
+// Number of groups of tiles that touch each other
+// Groups of deserts alone are only scenery and do not count
+func countRegions(tiles map[entities.Coordinate]entities.TileType) int {
+	visited := make(map[entities.Coordinate]bool)
+	regions := 0
+
+	for c := range tiles {
+		if visited[c] {
+			continue
+		}
+
+		desert := true
+		stack := []entities.Coordinate{c}
+		visited[c] = true
+		for len(stack) > 0 {
+			cur := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			desert = desert && tiles[cur] == entities.TileTypeDesert
+
+			for _, o := range neighbourOffsets {
+				n := entities.Coordinate{X: cur.X + o.X, Y: cur.Y + o.Y}
+				if _, ok := tiles[n]; ok && !visited[n] {
+					visited[n] = true
+					stack = append(stack, n)
+				}
+			}
+		}
+
+		if !desert {
+			regions++
+		}
+	}
+
+	return regions
+}
@@ -0,0 +1,157 @@
+package maps
+
+import (
+	"imperials/entities"
+	"strings"
+	"testing"
+)
+
+func TestValidateMapDefinition(t *testing.T) {
+	tests := []struct {
+		name   string
+		defn   func() *entities.MapDefinition
+		field  string
+		substr string
+	}{
+		{"base map", GetBaseMap, "", ""},
+		{"seafarers map", GetSeafarersMap, "", ""},
+		{"missing map", func() *entities.MapDefinition { return nil }, "map", "missing"},
+		{"no name", func() *entities.MapDefinition {
+			d := GetBaseMap()
+			d.Name = " "
+			return d
+		}, "name", ""},
+		{"order of other rows", func() *entities.MapDefinition {
+			d := GetBaseMap()
+			d.Order = d.Order[1:]
+			return d
+		}, "order", ""},
+		{"invalid tile", func() *entities.MapDefinition {
+			d := GetBaseMap()
+			d.Map[2][2] = 99
+			return d
+		}, "map", "invalid tile type"},
+		{"too small", func() *entities.MapDefinition {
+			d := GetBaseMap()
+			d.Map = [][]int{{9, 9, 9}}
+			d.Order = []bool{false}
+			d.RandomTiles = d.RandomTiles[:3]
+			return d
+		}, "map", "at least"},
+		{"split in two", func() *entities.MapDefinition {
+			d := GetBaseMap()
+			d.Map = [][]int{{9, 9, 9, 8, 9, 9, 9}}
+			d.Order = []bool{false}
+			return d
+		}, "map", "split"},
+		{"random tiles missing", func() *entities.MapDefinition {
+			d := GetBaseMap()
+			d.RandomTiles = d.RandomTiles[1:]
+			return d
+		}, "tiles", "random tiles"},
+		{"sea in the random tiles", func() *entities.MapDefinition {
+			d := GetBaseMap()
+			d.RandomTiles[0] = entities.TileTypeSea
+			return d
+		}, "tiles", "invalid tile type"},
+		{"seven as a number", func() *entities.MapDefinition {
+			d := GetBaseMap()
+			d.Numbers[0] = 7
+			return d
+		}, "numbers", "invalid number"},
+		{"numbers missing", func() *entities.MapDefinition {
+			d := GetBaseMap()
+			d.Numbers = d.Numbers[1:]
+			return d
+		}, "numbers", "for 18 tiles"},
+		{"fixed number", func() *entities.MapDefinition {
+			d := GetBaseMap()
+			d.Map[2][2] = int(entities.TileTypeWood)
+			d.RandomTiles = append(d.RandomTiles[:1], d.RandomTiles[2:]...)
+			d.Numbers = append(d.Numbers[:7], d.Numbers[8:]...)
+			d.TileNumbers = make([][]int, len(d.Map))
+			for i := range d.TileNumbers {
+				d.TileNumbers[i] = make([]int, len(d.Map[i]))
+			}
+			d.TileNumbers[2][2] = 6
+			return d
+		}, "", ""},
+		{"number fixed off the land", func() *entities.MapDefinition {
+			d := GetBaseMap()
+			d.TileNumbers = [][]int{{6}}
+			return d
+		}, "tile_numbers", "resource or gold"},
+		{"invalid port", func() *entities.MapDefinition {
+			d := GetBaseMap()
+			d.Ports[0] = entities.PortTypeAny + 1
+			return d
+		}, "ports", "invalid port type"},
+		{"too few port places", func() *entities.MapDefinition {
+			d := GetBaseMap()
+			d.PortCoordinates = d.PortCoordinates[1:]
+			return d
+		}, "port_coordinates", "places"},
+		{"port used twice", func() *entities.MapDefinition {
+			d := GetBaseMap()
+			d.PortCoordinates[1] = d.PortCoordinates[0]
+			return d
+		}, "port_coordinates", "twice"},
+		{"port inland", func() *entities.MapDefinition {
+			d := GetBaseMap()
+			d.PortCoordinates[0] = d.RiverCoordinates[0]
+			return d
+		}, "port_coordinates", "coast"},
+		{"river at sea", func() *entities.MapDefinition {
+			d := GetBaseMap()
+			d.RiverCoordinates[0] = entities.EdgeCoordinate{C1: entities.Coordinate{X: 100, Y: 100}, C2: entities.Coordinate{X: 102, Y: 102}}
+			return d
+		}, "river_coordinates", "land tile"},
+		{"negative island bonus", func() *entities.MapDefinition {
+			d := GetBaseMap()
+			d.IslandBonus = -1
+			return d
+		}, "island_bonus", ""},
+	}
+
+	for _, tt := range tests {
+		err := ValidateMapDefinition(tt.defn())
+		if tt.field == "" {
+			if err != nil {
+				t.Errorf("%s: got error %v", tt.name, err)
+			}
+			continue
+		}
+
+		errs, ok := err.(MapErrors)
+		if !ok {
+			t.Errorf("%s: got error %v, want map errors", tt.name, err)
+			continue
+		}
+
+		found := false
+		for _, e := range errs {
+			if e.Field == tt.field && strings.Contains(e.Message, tt.substr) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("%s: got error %v, want one for %s", tt.name, err, tt.field)
+		}
+	}
+}
+
+func TestMapErrorString(t *testing.T) {
+	tests := []struct {
+		err  *MapError
+		want string
+	}{
+		{&MapError{Field: "name", Message: "map must have a name"}, "name: map must have a name"},
+		{&MapError{Field: "map", Index: []int{2, 3}, Message: "invalid tile type 99"}, "map[2][3]: invalid tile type 99"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+	}
+}
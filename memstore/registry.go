@@ -0,0 +1,153 @@
+package memstore
+
+import (
+	"errors"
+	"time"
+)
+
+// Time without a heartbeat after which a server is gone
+const serverExpiry = time.Minute
+
+type (
+	// MemRegistry is the in-memory counterpart of mango.MangoRegistry.
+	// It shares its users with the MemStore so that ReadUser sees them.
+	MemRegistry struct {
+		Store *MemStore
+	}
+)
+
+func (mr *MemRegistry) Init() {
+	if mr.Store == nil {
+		mr.Store = NewMemStore()
+	}
+}
+
+func (mr *MemRegistry) Register(url, region string) error {
+	mr.Store.mutex.Lock()
+	if s, ok := mr.Store.servers[url]; ok {
+		s.Region = region
+	} else {
+		mr.Store.servers[url] = &memServer{
+			URL:       url,
+			Region:    region,
+			CreatedAt: time.Now(),
+		}
+	}
+	mr.Store.mutex.Unlock()
+
+	return mr.Heartbeat(url)
+}
+
+func (mr *MemRegistry) Heartbeat(url string) error {
+	mr.Store.mutex.Lock()
+	defer mr.Store.mutex.Unlock()
+
+	s, ok := mr.Store.servers[url]
+	if !ok {
+		s = &memServer{URL: url, CreatedAt: time.Now()}
+		mr.Store.servers[url] = s
+	}
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+// Region of each server by url, like the servers collection
+// servers expire without a heartbeat
+func (mr *MemRegistry) GetServerRegions() (map[string]string, error) {
+	mr.Store.mutex.RLock()
+	defer mr.Store.mutex.RUnlock()
+
+	regions := make(map[string]string)
+	for url, s := range mr.Store.servers {
+		if time.Since(s.UpdatedAt) < serverExpiry {
+			regions[url] = s.Region
+		}
+	}
+	return regions, nil
+}
+
+func (mr *MemRegistry) CreateUser(id, username string) error {
+	return mr.CreateUserWithEmail(id, username, username+"@imperials.app")
+}
+
+func (mr *MemRegistry) CreateUserWithEmail(id, username, email string) error {
+	mr.Store.mutex.Lock()
+	defer mr.Store.mutex.Unlock()
+
+	// Mirror the unique indexes of the users table
+	if _, ok := mr.Store.users[id]; ok {
+		return errors.New("duplicate user id")
+	}
+	for _, u := range mr.Store.users {
+		if u.Username == username {
+			return errors.New("duplicate username")
+		}
+		if u.Email == email {
+			return errors.New("duplicate email")
+		}
+	}
+
+	mr.Store.users[id] = &memUser{
+		ID:        id,
+		Username:  username,
+		Email:     email,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		Games:     make([]string, 0),
+	}
+	return nil
+}
+
+func (mr *MemRegistry) CheckIfUserExists(id string) (bool, error) {
+	mr.Store.mutex.RLock()
+	defer mr.Store.mutex.RUnlock()
+
+	if _, ok := mr.Store.users[id]; !ok {
+		return false, errors.New("user not found")
+	}
+	return true, nil
+}
+
+func (mr *MemRegistry) CheckIfUserEmailExists(email string) (map[string]interface{}, error) {
+	mr.Store.mutex.RLock()
+	defer mr.Store.mutex.RUnlock()
+
+	for _, u := range mr.Store.users {
+		if u.Email == email {
+			return u.toMap(), nil
+		}
+	}
+	return nil, errors.New("email not found")
+}
+
+func (mr *MemRegistry) UpdateUsername(id, username string) error {
+	mr.Store.mutex.Lock()
+	defer mr.Store.mutex.Unlock()
+
+	for _, u := range mr.Store.users {
+		if u.Username == username && u.ID != id {
+			return errors.New("duplicate username")
+		}
+	}
+
+	if u, ok := mr.Store.users[id]; ok {
+		u.Username = username
+	}
+	return nil
+}
+
+func (mr *MemRegistry) UpdateEmail(id, email string) error {
+	mr.Store.mutex.Lock()
+	defer mr.Store.mutex.Unlock()
+
+	for _, u := range mr.Store.users {
+		if u.Email == email && u.ID != id {
+			return errors.New("duplicate email")
+		}
+	}
+
+	if u, ok := mr.Store.users[id]; ok {
+		u.Email = email
+	}
+	return nil
+}
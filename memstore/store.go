@@ -0,0 +1,716 @@
+package memstore
+
+import (
+	"errors"
+	"imperials/entities"
+	"imperials/maps"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type (
+	// MemStore keeps everything in process memory. It implements game.Store
+	// with the same semantics as mango.MangoStore so that the server, bots
+	// and journal replay can run without a database.
+	MemStore struct {
+		mutex sync.RWMutex
+
+		games      map[string]*memGame
+		gameStates map[string]*memGameState
+		users      map[string]*memUser
+		maps       map[string]*memMap
+		servers    map[string]*memServer
+
+		stateCounter int
+	}
+
+	memGame struct {
+		ID            string
+		CreatedAt     time.Time
+		UpdatedAt     time.Time
+		Stage         int
+		Players       int
+		ActivePlayers int
+		Host          string
+		Server        string
+		Journal       [][]byte
+		Private       bool
+		Settings      []byte
+		StateID       string
+	}
+
+	memGameState struct {
+		CreatedAt time.Time
+		State     []byte
+		Report    []byte
+	}
+
+	memUser struct {
+		ID        string
+		Username  string
+		Email     string
+		CreatedAt time.Time
+		UpdatedAt time.Time
+		Games     []string
+		Started   int32
+		Finished  int32
+
+		// One for each mode and number of players
+		Ratings []*entities.Rating
+	}
+
+	memMap struct {
+		Name      string
+		Creator   string
+		Official  bool
+		Private   bool
+		Version   int
+		CreatedAt time.Time
+		UpdatedAt time.Time
+		Defn      *entities.MapDefinition
+
+		// Every saved version of the definition, oldest first
+		Versions []*memMapVersion
+	}
+
+	memMapVersion struct {
+		Version   int
+		CreatedAt time.Time
+		Defn      *entities.MapDefinition
+	}
+
+	memServer struct {
+		URL       string
+		Region    string
+		CreatedAt time.Time
+		UpdatedAt time.Time
+	}
+)
+
+func NewMemStore() *MemStore {
+	ds := &MemStore{
+		games:      make(map[string]*memGame),
+		gameStates: make(map[string]*memGameState),
+		users:      make(map[string]*memUser),
+		maps:       make(map[string]*memMap),
+		servers:    make(map[string]*memServer),
+	}
+
+	ds.WriteMap(maps.GetBaseMap(), "", true)
+	ds.WriteMap(maps.GetSeafarersMap(), "", true)
+	return ds
+}
+
+func (ds *MemStore) Init(id string) error {
+	return ds.CreateGameIfNotExists(id)
+}
+
+func (ds *MemStore) CreateGameIfNotExists(id string) error {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+
+	if _, ok := ds.games[id]; ok {
+		return nil
+	}
+
+	ds.games[id] = &memGame{
+		ID:        id,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		Server:    os.Getenv("SERVER_URL"),
+		Journal:   make([][]byte, 0),
+	}
+	return nil
+}
+
+func (ds *MemStore) TerminateGame(id string) error {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+
+	delete(ds.games, id)
+	return nil
+}
+
+// Run f on the game with the given id if it exists
+// Mutex must be locked
+func (ds *MemStore) updateGame(id string, f func(g *memGame)) {
+	if g, ok := ds.games[id]; ok {
+		f(g)
+	}
+}
+
+func (ds *MemStore) WriteGameServer(id string) error {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+
+	ds.updateGame(id, func(g *memGame) {
+		g.Server = os.Getenv("SERVER_URL")
+	})
+	return nil
+}
+
+func (ds *MemStore) WriteGameStarted(id string) error {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+
+	ds.updateGame(id, func(g *memGame) {
+		g.Stage = 1
+		g.UpdatedAt = time.Now()
+	})
+	return nil
+}
+
+func (ds *MemStore) WriteGameFinished(id string) error {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+
+	ds.updateGame(id, func(g *memGame) {
+		g.Stage = 2
+		g.UpdatedAt = time.Now()
+	})
+	return nil
+}
+
+func (ds *MemStore) WriteGameActivePlayers(id string, numPlayers int32, host string) error {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+
+	ds.updateGame(id, func(g *memGame) {
+		g.ActivePlayers = int(numPlayers)
+		g.UpdatedAt = time.Now()
+		if host != "" {
+			g.Host = host
+		}
+	})
+	return nil
+}
+
+func (ds *MemStore) WriteGamePlayers(id string, numPlayers int32) error {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+
+	ds.updateGame(id, func(g *memGame) {
+		if g.Stage == 0 {
+			g.Players = int(numPlayers)
+			g.UpdatedAt = time.Now()
+		}
+	})
+	return nil
+}
+
+func (ds *MemStore) WriteGamePrivacy(id string, private bool) error {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+
+	ds.updateGame(id, func(g *memGame) {
+		g.Private = private
+		g.UpdatedAt = time.Now()
+	})
+	return nil
+}
+
+func (ds *MemStore) WriteGameSettings(id string, settings []byte) error {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+
+	ds.updateGame(id, func(g *memGame) {
+		if g.Stage == 0 {
+			g.Settings = copyBytes(settings)
+			g.UpdatedAt = time.Now()
+		}
+	})
+	return nil
+}
+
+func (ds *MemStore) WriteJournalEntries(id string, entries [][]byte) error {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+
+	ds.updateGame(id, func(g *memGame) {
+		for _, e := range entries {
+			g.Journal = append(g.Journal, copyBytes(e))
+		}
+		g.UpdatedAt = time.Now()
+	})
+	return nil
+}
+
+func (ds *MemStore) TruncateJournal(id string, length int) error {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+
+	ds.updateGame(id, func(g *memGame) {
+		if length < len(g.Journal) {
+			g.Journal = g.Journal[:length]
+		}
+		g.UpdatedAt = time.Now()
+	})
+	return nil
+}
+
+func (ds *MemStore) ReadJournal(id string) ([][]byte, error) {
+	ds.mutex.RLock()
+	defer ds.mutex.RUnlock()
+
+	g, ok := ds.games[id]
+	if !ok {
+		return nil, errors.New("database entry for journal not found")
+	}
+
+	journalBytes := make([][]byte, len(g.Journal))
+	for i, e := range g.Journal {
+		journalBytes[i] = copyBytes(e)
+	}
+
+	return journalBytes, nil
+}
+
+func (ds *MemStore) ReadGamePlayers(id string) (int, error) {
+	ds.mutex.RLock()
+	defer ds.mutex.RUnlock()
+
+	g, ok := ds.games[id]
+	if !ok {
+		return 0, errors.New("database entry for game not found")
+	}
+
+	return g.Players, nil
+}
+
+func (ds *MemStore) CheckIfJournalExists(id string) (bool, error) {
+	j, err := ds.ReadJournal(id)
+	if err != nil {
+		return false, err
+	}
+	return len(j) > 0, nil
+}
+
+func (ds *MemStore) CreateGameStateIfNotExists(id string, state []byte) error {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+
+	g, ok := ds.games[id]
+	if ok && g.StateID != "" {
+		return errors.New("game state already created")
+	}
+
+	ds.stateCounter++
+	sid := id + "-" + strconv.Itoa(ds.stateCounter)
+	ds.gameStates[sid] = &memGameState{
+		CreatedAt: time.Now(),
+		State:     copyBytes(state),
+	}
+
+	if ok {
+		g.StateID = sid
+	}
+
+	return nil
+}
+
+func (ds *MemStore) WriteGameState(id string, state []byte) error {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+
+	g, ok := ds.games[id]
+	if !ok || g.StateID == "" {
+		return errors.New("game state not created")
+	}
+
+	ds.gameStates[g.StateID].State = copyBytes(state)
+	return nil
+}
+
+func (ds *MemStore) ReadGameState(id string) ([]byte, error) {
+	ds.mutex.RLock()
+	defer ds.mutex.RUnlock()
+
+	g, ok := ds.games[id]
+	if !ok || g.StateID == "" {
+		return nil, errors.New("game state not created")
+	}
+
+	return copyBytes(ds.gameStates[g.StateID].State), nil
+}
+
+func (ds *MemStore) WriteGameReport(id string, report []byte) error {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+
+	g, ok := ds.games[id]
+	if !ok || g.StateID == "" {
+		return errors.New("game state not created")
+	}
+
+	ds.gameStates[g.StateID].Report = copyBytes(report)
+	return nil
+}
+
+func (ds *MemStore) ReadGameReport(id string) ([]byte, error) {
+	ds.mutex.RLock()
+	defer ds.mutex.RUnlock()
+
+	g, ok := ds.games[id]
+	if !ok || g.StateID == "" {
+		return nil, errors.New("game state not created")
+	}
+
+	report := ds.gameStates[g.StateID].Report
+	if report == nil {
+		return nil, errors.New("game report not found")
+	}
+	return copyBytes(report), nil
+}
+
+func (ds *MemStore) WriteGameIdForUser(id string, userId string, settings *entities.GameSettings) error {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+
+	g, ok := ds.games[id]
+	if !ok || g.StateID == "" {
+		return errors.New("game state not created")
+	}
+
+	if u, ok := ds.users[userId]; ok {
+		u.Games = append(u.Games, g.StateID)
+		u.UpdatedAt = time.Now()
+		if settings.EnableKarma {
+			u.Started++
+		}
+	}
+
+	return nil
+}
+
+func (ds *MemStore) WriteGameCompletedForUser(id string) error {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+
+	if u, ok := ds.users[id]; ok {
+		u.Finished++
+		u.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+// Ratings of users that do not exist are ignored
+func (ds *MemStore) WriteRatings(ratings []*entities.Rating) error {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+
+	for _, r := range ratings {
+		u, ok := ds.users[r.UserID]
+		if !ok {
+			continue
+		}
+
+		found := false
+		for i, saved := range u.Ratings {
+			if saved.Mode == r.Mode && saved.Players == r.Players {
+				u.Ratings[i] = copyRating(r)
+				found = true
+			}
+		}
+		if !found {
+			u.Ratings = append(u.Ratings, copyRating(r))
+		}
+	}
+	return nil
+}
+
+func (ds *MemStore) ReadRatings(userId string) ([]*entities.Rating, error) {
+	ds.mutex.RLock()
+	defer ds.mutex.RUnlock()
+
+	u, ok := ds.users[userId]
+	if !ok {
+		return nil, errors.New("database entry for user not found")
+	}
+
+	ratings := make([]*entities.Rating, len(u.Ratings))
+	for i, r := range u.Ratings {
+		ratings[i] = copyRating(r)
+	}
+	return ratings, nil
+}
+
+// Latest games of a user, newest first, all of them without a limit
+func (ds *MemStore) ReadUserGames(userId string, limit int) ([]*entities.UserGame, error) {
+	ds.mutex.RLock()
+	defer ds.mutex.RUnlock()
+
+	u, ok := ds.users[userId]
+	if !ok {
+		return nil, errors.New("database entry for user not found")
+	}
+
+	games := make([]*entities.UserGame, 0)
+	for i := len(u.Games) - 1; i >= 0 && (limit <= 0 || len(games) < limit); i-- {
+		s, ok := ds.gameStates[u.Games[i]]
+		if !ok {
+			continue
+		}
+		games = append(games, &entities.UserGame{
+			State:     copyBytes(s.State),
+			Report:    copyBytes(s.Report),
+			CreatedAt: s.CreatedAt,
+		})
+	}
+	return games, nil
+}
+
+func (ds *MemStore) ReadLeaderboard(mode entities.GameMode, players int, limit int) ([]*entities.Rating, error) {
+	ds.mutex.RLock()
+	defer ds.mutex.RUnlock()
+
+	ratings := make([]*entities.Rating, 0)
+	for _, u := range ds.users {
+		ratings = append(ratings, u.Ratings...)
+	}
+
+	best := entities.BestRatings(ratings, mode, players, limit)
+	for i, r := range best {
+		best[i] = copyRating(r)
+	}
+	return best, nil
+}
+
+func (ds *MemStore) ReadUser(id string) (map[string]interface{}, error) {
+	ds.mutex.RLock()
+	defer ds.mutex.RUnlock()
+
+	u, ok := ds.users[id]
+	if !ok {
+		return nil, errors.New("database entry for user not found")
+	}
+
+	return u.toMap(), nil
+}
+
+func (ds *MemStore) GetOfficalMapNames() []string {
+	ds.mutex.RLock()
+	defer ds.mutex.RUnlock()
+
+	names := make([]string, 0)
+	for _, m := range ds.maps {
+		if m.Official {
+			names = append(names, m.Name)
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// Get all maps excluding user maps if exclude
+func (ds *MemStore) GetAllMapNamesForUser(userId string, exclude bool) ([]string, error) {
+	ds.mutex.RLock()
+	defer ds.mutex.RUnlock()
+
+	var names []string
+	for _, m := range ds.maps {
+		if !exclude && m.Creator == userId {
+			names = append(names, m.Name)
+		} else if exclude && m.Creator != userId && !m.Official && !m.Private {
+			names = append(names, m.Name)
+		}
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+func (ds *MemStore) GetMap(name string) *entities.MapDefinition {
+	ds.mutex.RLock()
+	defer ds.mutex.RUnlock()
+
+	m, ok := ds.maps[name]
+	if !ok {
+		return nil
+	}
+
+	return copyMapDefinition(m.Defn)
+}
+
+func (ds *MemStore) GetMapInfo(name string) *entities.MapInfo {
+	ds.mutex.RLock()
+	defer ds.mutex.RUnlock()
+
+	m, ok := ds.maps[name]
+	if !ok {
+		return nil
+	}
+
+	info := m.toInfo()
+	info.Defn = copyMapDefinition(m.Defn)
+	return info
+}
+
+func (ds *MemStore) GetMapVersion(name string, version int) *entities.MapInfo {
+	ds.mutex.RLock()
+	defer ds.mutex.RUnlock()
+
+	m, ok := ds.maps[name]
+	if !ok {
+		return nil
+	}
+
+	for _, v := range m.Versions {
+		if v.Version == version {
+			info := m.toInfo()
+			info.Version = v.Version
+			info.UpdatedAt = v.CreatedAt
+			info.Defn = copyMapDefinition(v.Defn)
+			return info
+		}
+	}
+	return nil
+}
+
+// Versions of a map without their definitions, newest first
+func (ds *MemStore) GetMapVersions(name string) ([]*entities.MapInfo, error) {
+	ds.mutex.RLock()
+	defer ds.mutex.RUnlock()
+
+	m, ok := ds.maps[name]
+	if !ok {
+		return nil, errors.New("map not found")
+	}
+
+	versions := make([]*entities.MapInfo, 0, len(m.Versions))
+	for i := len(m.Versions) - 1; i >= 0; i-- {
+		info := m.toInfo()
+		info.Version = m.Versions[i].Version
+		info.UpdatedAt = m.Versions[i].CreatedAt
+		versions = append(versions, info)
+	}
+	return versions, nil
+}
+
+// Add or replace a map definition
+func (ds *MemStore) WriteMap(defn *entities.MapDefinition, creator string, official bool) error {
+	if defn == nil {
+		return errors.New("map must have a name")
+	}
+
+	info := &entities.MapInfo{
+		Name:     defn.Name,
+		Creator:  creator,
+		Official: official,
+		Defn:     defn,
+	}
+	_, err := ds.CreateMapInfo(info)
+	if err == entities.ErrMapExists {
+		_, err = ds.WriteMapInfo(info)
+	}
+	return err
+}
+
+// Save the first version of a map, the name must not be taken
+func (ds *MemStore) CreateMapInfo(info *entities.MapInfo) (*entities.MapInfo, error) {
+	if err := validateMapInfo(info); err != nil {
+		return nil, err
+	}
+
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+
+	if _, ok := ds.maps[info.Name]; ok {
+		return nil, entities.ErrMapExists
+	}
+
+	now := time.Now()
+	m := &memMap{
+		Name:      info.Name,
+		Creator:   info.Creator,
+		Official:  info.Official,
+		CreatedAt: now,
+	}
+	ds.maps[info.Name] = m
+	return m.addVersion(info, now), nil
+}
+
+// Save a new version of a map of the creator in info
+// The official flag of the map is kept
+func (ds *MemStore) WriteMapInfo(info *entities.MapInfo) (*entities.MapInfo, error) {
+	if err := validateMapInfo(info); err != nil {
+		return nil, err
+	}
+
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+
+	m, ok := ds.maps[info.Name]
+	if !ok || m.Creator != info.Creator {
+		return nil, entities.ErrMapNotFound
+	}
+	return m.addVersion(info, time.Now()), nil
+}
+
+func validateMapInfo(info *entities.MapInfo) error {
+	if info.Defn == nil || info.Name == "" || info.Defn.Name != info.Name {
+		return errors.New("map must have a name")
+	}
+	return maps.ValidateMapDefinition(info.Defn)
+}
+
+func (m *memMap) addVersion(info *entities.MapInfo, now time.Time) *entities.MapInfo {
+	m.Private = info.Private
+	m.Version++
+	m.UpdatedAt = now
+	m.Defn = copyMapDefinition(info.Defn)
+	m.Versions = append(m.Versions, &memMapVersion{
+		Version:   m.Version,
+		CreatedAt: now,
+		Defn:      m.Defn,
+	})
+
+	saved := m.toInfo()
+	saved.Defn = copyMapDefinition(m.Defn)
+	return saved
+}
+
+// Remove a map with all of its versions
+func (ds *MemStore) DeleteMap(name string) error {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+
+	if _, ok := ds.maps[name]; !ok {
+		return errors.New("map not found")
+	}
+	delete(ds.maps, name)
+	return nil
+}
+
+func (m *memMap) toInfo() *entities.MapInfo {
+	return &entities.MapInfo{
+		Name:      m.Name,
+		Creator:   m.Creator,
+		Official:  m.Official,
+		Private:   m.Private,
+		Version:   m.Version,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+func (u *memUser) toMap() map[string]interface{} {
+	games := make([]interface{}, len(u.Games))
+	for i, g := range u.Games {
+		games[i] = g
+	}
+
+	return map[string]interface{}{
+		"id":        u.ID,
+		"username":  u.Username,
+		"email":     u.Email,
+		"createdAt": u.CreatedAt,
+		"updatedAt": u.UpdatedAt,
+		"games":     games,
+		"started":   u.Started,
+		"finished":  u.Finished,
+	}
+}
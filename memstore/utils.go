@@ -0,0 +1,41 @@
+package memstore
+
+import (
+	"encoding/json"
+	"imperials/entities"
+)
+
+func copyBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+
+	c := make([]byte, len(b))
+	copy(c, b)
+	return c
+}
+
+// Deep copy a map definition so that callers cannot mutate stored maps.
+// The game shuffles RandomTiles in place when generating a board.
+func copyMapDefinition(defn *entities.MapDefinition) *entities.MapDefinition {
+	if defn == nil {
+		return nil
+	}
+
+	var ans *entities.MapDefinition
+	marshal, err := json.Marshal(defn)
+	if err == nil {
+		json.Unmarshal(marshal, &ans)
+	}
+	return ans
+}
+
+func copyRating(r *entities.Rating) *entities.Rating {
+	c := *r
+	c.History = make([]*entities.RatingChange, len(r.History))
+	for i, h := range r.History {
+		change := *h
+		c.History[i] = &change
+	}
+	return &c
+}
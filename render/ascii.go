@@ -0,0 +1,127 @@
+package render
+
+import (
+	"fmt"
+	"imperials/entities"
+	"imperials/game"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Characters for each half tile step across a row
+const asciiStep = 3
+
+// Draw the board of a game as text, one line for each row of tiles,
+// followed by the pieces of each player and the ports
+//
+// Each tile is its type, its number and a marker for the robber (R),
+// pirate (P) or merchant (M), like "Wh 8R". Hidden tiles are "??".
+func ASCII(g *game.Game) string {
+	tiles := sortedTiles(g)
+
+	// Tiles next to each other in a row are two steps apart
+	unit := 2 * game.DispXFactor
+	minX, minY := math.Inf(1), math.MaxInt32
+	for _, t := range tiles {
+		minX = math.Min(minX, g.DispCoordMap[t.Center].X)
+		if t.Center.Y < minY {
+			minY = t.Center.Y
+		}
+	}
+
+	lines := make([][]byte, 0)
+	for _, t := range tiles {
+		row := (t.Center.Y - minY) / 4
+		col := int(math.Round((g.DispCoordMap[t.Center].X-minX)/unit)) * asciiStep
+
+		for len(lines) <= row {
+			lines = append(lines, []byte{})
+		}
+		label := getTileLabel(g, t)
+		for len(lines[row]) < col+len(label) {
+			lines[row] = append(lines[row], ' ')
+		}
+		copy(lines[row][col:], label)
+	}
+
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(strings.TrimRight(string(line), " "))
+		b.WriteString("\n")
+	}
+
+	// Pieces of each player
+	for _, p := range g.Players {
+		pieces := make(map[entities.BuildableType][]string)
+		for _, v := range sortedVertices(g) {
+			if v.Placement != nil && v.Placement.GetOwner() == p {
+				t := v.Placement.GetType()
+				pieces[t] = append(pieces[t], fmt.Sprintf("%d,%d", v.C.X, v.C.Y))
+			}
+		}
+		for _, e := range sortedEdges(g) {
+			if e.Placement != nil && e.Placement.GetOwner() == p {
+				t := e.Placement.GetType()
+				pieces[t] = append(pieces[t], fmt.Sprintf("%d,%d-%d,%d", e.C.C1.X, e.C.C1.Y, e.C.C2.X, e.C.C2.Y))
+			}
+		}
+
+		fmt.Fprintf(&b, "%d %s:", p.Order, p.Username)
+		for _, piece := range []struct {
+			Type entities.BuildableType
+			Name string
+		}{
+			{entities.BTSettlement, "settlements"},
+			{entities.BTCity, "cities"},
+			{entities.BTKnight1, "knights1"},
+			{entities.BTKnight2, "knights2"},
+			{entities.BTKnight3, "knights3"},
+			{entities.BTRoad, "roads"},
+			{entities.BTShip, "ships"},
+		} {
+			if len(pieces[piece.Type]) > 0 {
+				fmt.Fprintf(&b, " %s %s;", piece.Name, strings.Join(pieces[piece.Type], " "))
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	// Ports by type
+	ports := make([]string, 0, len(g.Ports))
+	for _, p := range g.Ports {
+		if p.Edge != nil {
+			ports = append(ports, fmt.Sprintf("%s@%d,%d-%d,%d", portNames[p.Type], p.Edge.C.C1.X, p.Edge.C.C1.Y, p.Edge.C.C2.X, p.Edge.C.C2.Y))
+		}
+	}
+	sort.Strings(ports)
+	if len(ports) > 0 {
+		fmt.Fprintf(&b, "ports: %s\n", strings.Join(ports, " "))
+	}
+
+	return b.String()
+}
+
+func getTileLabel(g *game.Game, t *entities.Tile) string {
+	code := getTileStyle(t.Type).Code
+	if t.Fog {
+		code = getTileStyle(entities.TileTypeFog).Code
+	}
+
+	number := "  "
+	if t.Number != 0 {
+		number = fmt.Sprintf("%2d", t.Number)
+	}
+
+	marker := " "
+	switch {
+	case g.Robber != nil && g.Robber.Tile == t:
+		marker = "R"
+	case g.Pirate != nil && g.Pirate.Tile == t:
+		marker = "P"
+	case g.Merchant != nil && g.Merchant.Owner != nil && g.Merchant.Tile == t:
+		marker = "M"
+	}
+
+	return code + number + marker
+}
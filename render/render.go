@@ -0,0 +1,83 @@
+// Package render draws the board of a game as SVG or as compact text,
+// to look at board states without the web client.
+// The caller must hold the lock of the game while rendering.
+package render
+
+import (
+	"imperials/entities"
+	"imperials/game"
+	"sort"
+)
+
+// Tile type, fill colour and short code
+type tileStyle struct {
+	Name  string
+	Color string
+	Code  string
+}
+
+var tileStyles = map[entities.TileType]tileStyle{
+	entities.TileTypeDesert: {"Desert", "#e8d8a0", "De"},
+	entities.TileTypeWood:   {"Wood", "#2e7d32", "Wd"},
+	entities.TileTypeBrick:  {"Brick", "#c0582b", "Br"},
+	entities.TileTypeWool:   {"Wool", "#9ccc65", "Wl"},
+	entities.TileTypeWheat:  {"Wheat", "#f9c74f", "Wh"},
+	entities.TileTypeOre:    {"Ore", "#8d8d8d", "Or"},
+	entities.TileTypeFog:    {"Fog", "#cfd8dc", "??"},
+	entities.TileTypeSea:    {"Sea", "#4f8fd6", "~~"},
+	entities.TileTypeGold:   {"Gold", "#ffd700", "Au"},
+	entities.TileTypeLake:   {"Lake", "#81d4fa", "Lk"},
+}
+
+var portNames = map[entities.PortType]string{
+	entities.PortTypeAny:   "3:1",
+	entities.PortTypeWood:  "Wd",
+	entities.PortTypeBrick: "Br",
+	entities.PortTypeWool:  "Wl",
+	entities.PortTypeWheat: "Wh",
+	entities.PortTypeOre:   "Or",
+}
+
+func getTileStyle(t entities.TileType) tileStyle {
+	if s, ok := tileStyles[t]; ok {
+		return s
+	}
+	return tileStyle{"Unknown", "#ffffff", "  "}
+}
+
+// Tiles in row then column order so the output is the same every time
+func sortedTiles(g *game.Game) []*entities.Tile {
+	tiles := make([]*entities.Tile, 0, len(g.Tiles))
+	for _, t := range g.Tiles {
+		tiles = append(tiles, t)
+	}
+	sort.Slice(tiles, func(i, j int) bool { return tiles[i].Center.Less(tiles[j].Center) })
+	return tiles
+}
+
+func sortedVertices(g *game.Game) []*entities.Vertex {
+	vertices := make([]*entities.Vertex, 0, len(g.Vertices))
+	for _, v := range g.Vertices {
+		vertices = append(vertices, v)
+	}
+	sort.Slice(vertices, func(i, j int) bool { return vertices[i].C.Less(vertices[j].C) })
+	return vertices
+}
+
+func sortedEdges(g *game.Game) []*entities.Edge {
+	edges := make([]*entities.Edge, 0, len(g.Edges))
+	for _, e := range g.Edges {
+		edges = append(edges, e)
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].C.C1 != edges[j].C.C1 {
+			return edges[i].C.C1.Less(edges[j].C.C1)
+		}
+		return edges[i].C.C2.Less(edges[j].C.C2)
+	})
+	return edges
+}
+
+func isRedNumber(n uint16) bool {
+	return n == 6 || n == 8
+}
@@ -0,0 +1,173 @@
+package render
+
+import (
+	"fmt"
+	"imperials/entities"
+	"imperials/game"
+	"math"
+	"strings"
+)
+
+const (
+	// Pixels for one unit of the display coordinates
+	svgScale = 24.0
+
+	svgPadding = 2.0
+)
+
+// Draw the board of a game as an SVG document
+// Hidden tiles are drawn with their real type under a fog pattern
+func SVG(g *game.Game) []byte {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, fc := range g.DispCoordMap {
+		minX, maxX = math.Min(minX, fc.X), math.Max(maxX, fc.X)
+		minY, maxY = math.Min(minY, fc.Y), math.Max(maxY, fc.Y)
+	}
+	if len(g.DispCoordMap) == 0 {
+		minX, minY, maxX, maxY = 0, 0, 0, 0
+	}
+
+	// Room for the ports outside of the board
+	minX, minY = minX-svgPadding, minY-svgPadding
+	maxX, maxY = maxX+svgPadding, maxY+svgPadding
+
+	pos := func(c entities.Coordinate) (float64, float64) {
+		fc := g.DispCoordMap[c]
+		return (fc.X - minX) * svgScale, (fc.Y - minY) * svgScale
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" font-family="sans-serif" text-anchor="middle" dominant-baseline="central">`+"\n",
+		(maxX-minX)*svgScale, (maxY-minY)*svgScale)
+	b.WriteString(`<defs><pattern id="fog" width="8" height="8" patternUnits="userSpaceOnUse">` +
+		`<path d="M0,8 L8,0" stroke="#ffffff" stroke-width="2" opacity="0.6"/></pattern></defs>` + "\n")
+
+	// Tiles
+	for _, t := range sortedTiles(g) {
+		points := make([]string, 0, 6)
+		for _, c := range t.GetVertexCoordinates() {
+			x, y := pos(c)
+			points = append(points, fmt.Sprintf("%.1f,%.1f", x, y))
+		}
+		style := getTileStyle(t.Type)
+		fmt.Fprintf(&b, `<polygon points="%s" fill="%s" stroke="#5d4037" stroke-width="1"><title>%s %d,%d</title></polygon>`+"\n",
+			strings.Join(points, " "), style.Color, style.Name, t.Center.X, t.Center.Y)
+		if t.Fog {
+			fmt.Fprintf(&b, `<polygon points="%s" fill="url(#fog)"/>`+"\n", strings.Join(points, " "))
+		}
+
+		if t.Number != 0 {
+			x, y := pos(t.Center)
+			color := "#000000"
+			if isRedNumber(t.Number) {
+				color = "#d32f2f"
+			}
+			fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="#fff8e1"/>`+"\n", x, y, 0.7*svgScale)
+			fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" font-size="%.0f" fill="%s">%d</text>`+"\n", x, y, 0.8*svgScale, color, t.Number)
+		}
+	}
+
+	// Rivers, roads and ships
+	for _, e := range sortedEdges(g) {
+		x1, y1 := pos(e.C.C1)
+		x2, y2 := pos(e.C.C2)
+
+		if e.IsRiver {
+			fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#1e88e5" stroke-width="%.1f"/>`+"\n",
+				x1, y1, x2, y2, 0.2*svgScale)
+		}
+
+		if e.Placement == nil || e.Placement.GetOwner() == nil {
+			continue
+		}
+
+		dash := ""
+		if e.Placement.GetType() == entities.BTShip {
+			dash = ` stroke-dasharray="6,3"`
+		}
+		fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="%.1f" stroke-linecap="round"%s/>`+"\n",
+			x1, y1, x2, y2, e.Placement.GetOwner().Color, 0.25*svgScale, dash)
+	}
+
+	// Ports, outside of the edge they are on
+	for _, p := range g.Ports {
+		if p.Edge == nil || len(p.Edge.AdjacentTiles) == 0 {
+			continue
+		}
+		x1, y1 := pos(p.Edge.C.C1)
+		x2, y2 := pos(p.Edge.C.C2)
+		mx, my := (x1+x2)/2, (y1+y2)/2
+
+		// Away from the land tile of the edge
+		tile := p.Edge.AdjacentTiles[0]
+		for _, t := range p.Edge.AdjacentTiles {
+			if t.Type != entities.TileTypeSea {
+				tile = t
+			}
+		}
+		tx, ty := pos(tile.Center)
+		dx, dy := mx-tx, my-ty
+		if d := math.Hypot(dx, dy); d > 0 {
+			dx, dy = dx/d, dy/d
+		}
+		px, py := mx+dx*0.9*svgScale, my+dy*0.9*svgScale
+
+		fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#6d4c41" stroke-width="2"/>`+"\n", mx, my, px, py)
+		fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="#ffffff" stroke="#6d4c41"/>`+"\n", px, py, 0.6*svgScale)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" font-size="%.0f">%s</text>`+"\n", px, py, 0.45*svgScale, portNames[p.Type])
+	}
+
+	// Settlements, cities and knights
+	for _, v := range sortedVertices(g) {
+		if v.Placement == nil || v.Placement.GetOwner() == nil {
+			continue
+		}
+		x, y := pos(v.C)
+		color := v.Placement.GetOwner().Color
+
+		switch v.Placement.GetType() {
+		case entities.BTSettlement:
+			s := 0.3 * svgScale
+			fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s" stroke="#000000"/>`+"\n", x-s, y-s, 2*s, 2*s, color)
+		case entities.BTCity:
+			s := 0.45 * svgScale
+			width := 1
+			if c, ok := v.Placement.(*entities.City); ok && c.Wall {
+				width = 3
+			}
+			fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s" stroke="#000000" stroke-width="%d"/>`+"\n", x-s, y-s, 2*s, 2*s, color, width)
+		case entities.BTKnight1, entities.BTKnight2, entities.BTKnight3:
+			fill := "#ffffff"
+			if k, ok := v.Placement.(*entities.Knight); ok && k.Activated {
+				fill = color
+			}
+			level := int(v.Placement.GetType()-entities.BTKnight1) + 1
+			fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="%s" stroke="%s" stroke-width="3"/>`+"\n", x, y, 0.4*svgScale, fill, color)
+			fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" font-size="%.0f">%d</text>`+"\n", x, y, 0.45*svgScale, level)
+		}
+	}
+
+	// Robber, pirate and merchant
+	drawFigure := func(t *entities.Tile, label string, fill string, offset float64) {
+		if t == nil {
+			return
+		}
+		x, y := pos(t.Center)
+		x += offset * svgScale
+		fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="%.1f" fill="%s" stroke="#ffffff"/>`+"\n", x, y+svgScale, 0.45*svgScale, fill)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" font-size="%.0f" fill="#ffffff">%s</text>`+"\n", x, y+svgScale, 0.45*svgScale, label)
+	}
+	if g.Robber != nil {
+		drawFigure(g.Robber.Tile, "R", "#212121", -0.6)
+	}
+	if g.Pirate != nil {
+		drawFigure(g.Pirate.Tile, "P", "#000000", 0)
+	}
+	if g.Merchant != nil && g.Merchant.Owner != nil {
+		drawFigure(g.Merchant.Tile, "M", g.Merchant.Owner.Color, 0.6)
+	}
+
+	b.WriteString("</svg>\n")
+	return []byte(b.String())
+}
@@ -1,21 +1,41 @@
 package server
 
 import (
+	"encoding/json"
 	"errors"
 	"imperials/entities"
+	"imperials/game"
+	"net/http"
+	"strings"
 	"unicode"
 
 	"github.com/Pallinder/go-randomdata"
+	jwt "github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/mitchellh/mapstructure"
 )
 
-func (hub *WsHub) StartBot() error {
+func (hub *WsHub) StartBot(strategy string, difficulty string) error {
 	botname := randomdata.SillyName() + "*"
 
 	if hub.terminating {
 		return errors.New("hub is terminating")
 	}
 
+	if strategy == "" {
+		strategy = game.BotStrategyDefault
+	}
+	if !game.IsValidBotStrategy(strategy) {
+		return errors.New("unknown bot strategy")
+	}
+
+	if difficulty == "" {
+		difficulty = entities.BotDifficultyMedium
+	}
+	if !entities.IsValidBotDifficulty(difficulty) {
+		return errors.New("unknown bot difficulty")
+	}
+
 	playerNumber := hub.DisconnectOtherClients(botname, "Remove duplicate bot.")
 	if playerNumber < 0 || playerNumber >= 6 || playerNumber >= hub.Game.Settings.MaxPlayers {
 		return errors.New("too many players to add bot")
@@ -31,6 +51,8 @@ func (hub *WsHub) StartBot() error {
 		return err
 	}
 	client.Player = player
+	player.BotStrategy = strategy
+	player.BotDifficulty = difficulty
 
 	if hub.Game.Initialized {
 		gamePlayer, err := hub.Game.ReplacePlayer(player)
@@ -63,6 +85,88 @@ func (hub *WsHub) StartBot() error {
 	return nil
 }
 
+// Create a token for an external bot owned by the user
+// The same owner and name always give the same bot id
+func (s *Server) createBotToken(w http.ResponseWriter, r *http.Request) {
+	var owner string
+	var isBot bool
+	mapstructure.Decode(r.Context().Value(ContextKey("id")), &owner)
+	mapstructure.Decode(r.Context().Value(ContextKey("bot")), &isBot)
+
+	if owner == "" || isBot {
+		WriteJson(w, http.StatusUnauthorized, map[string]string{"error": "Bots cannot create bots"})
+		return
+	}
+
+	var data map[string]interface{}
+	var name string
+	if err := json.NewDecoder(r.Body).Decode(&data); err == nil {
+		mapstructure.Decode(data["name"], &name)
+	}
+
+	if !IsValidUsername(name) {
+		WriteJson(w, http.StatusBadRequest, map[string]string{"error": "Invalid bot name"})
+		return
+	}
+
+	id := getBotID(owner, name)
+	username := name + "*"
+
+	if exists, _ := s.registry.CheckIfUserExists(id); !exists {
+		if err := s.registry.CreateUser(id, username); err != nil {
+			WriteJson(w, http.StatusConflict, map[string]string{"error": "Bot name is taken"})
+			return
+		}
+	}
+
+	token, err := GenerateBotJWT(id, username, owner)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	WriteJson(w, http.StatusOK, map[string]string{"id": id, "username": username, "token": token})
+}
+
+func getBotID(owner, name string) string {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte("bot:"+owner+"/"+name)).String()
+}
+
+// The owner of a bot token must still be a user, and the token
+// must be the one made for the bot of that owner
+func (s *Server) checkBotOwner(r *http.Request) bool {
+	var claims jwt.MapClaims
+	mapstructure.Decode(r.Context().Value(ContextKey("claims")), &claims)
+
+	var id, username, owner string
+	mapstructure.Decode(claims["id"], &id)
+	mapstructure.Decode(claims["username"], &username)
+	mapstructure.Decode(claims["owner"], &owner)
+	if owner == "" || !strings.HasSuffix(username, "*") || id != getBotID(owner, strings.TrimSuffix(username, "*")) {
+		return false
+	}
+
+	exists, err := s.registry.CheckIfUserExists(owner)
+	return err == nil && exists
+}
+
+// Websocket for bots running as separate processes
+// Speaks the same protocol as /socket but needs a bot token
+func (s *Server) botSocketHandler(w http.ResponseWriter, r *http.Request) {
+	gameId := r.URL.Query().Get("id")
+
+	if !s.checkBotOwner(r) {
+		RejectWs(w, r, http.StatusForbidden, "E749: The owner of this bot is not a user")
+		return
+	}
+
+	if hub, ok := s.hubs.Load(gameId); ok {
+		StartWs(hub.(*WsHub), w, r, true)
+	} else {
+		RejectWs(w, r, http.StatusNotFound, "E738: Game not found. Try refresing this page.")
+	}
+}
+
 func IsValidUsername(u string) bool {
 	if len(u) < 3 || len(u) >= 20 {
 		return false
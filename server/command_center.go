@@ -3,14 +3,17 @@ package server
 import (
 	"errors"
 	"fmt"
+	"imperials/entities"
+	"imperials/game"
 	"log"
+	"sort"
 	"strings"
 )
 
 const (
 	HelpMsg = `
 
-Commands: !help, !embargo, !toggle, !stats
+Commands: !help, !embargo, !toggle, !stats, !undo
 `
 
 	EmbargoMsg = `
@@ -41,10 +44,19 @@ Commands: !help, !embargo, !toggle, !stats
 
 	Stats allows you to view different stats pertaining to the current game.
 
-	Available stats are: dice
+	Available stats are: dice, resources, robber, trades, events, luck, cards
 
 	Type "!stats [stat]" to view the stat.
 `
+
+	UndoMsg = `
+
+	Undo takes back your last build or trade with the bank during your turn.
+
+	Type "!undo" to ask the host, who answers with "!undo yes" or "!undo no".
+
+	Actions cannot be undone after dice rolls, steals or drawing cards.
+`
 )
 
 func processCommand(command string, ws *WsClient) (string, error) {
@@ -95,6 +107,19 @@ func processCommand(command string, ws *WsClient) (string, error) {
 
 			return output, nil
 		}
+	} else if strings.HasPrefix(command, "!undo") {
+		cmd := strings.Split(command, " ")
+		if len(cmd) > 2 || (len(cmd) == 2 && cmd[1] == "help") {
+			return UndoMsg, nil
+		}
+
+		// Process undo
+		output, err := processUndo(cmd, ws)
+		if err != nil {
+			return "\n\nCannot undo: " + err.Error() + "\n", nil
+		}
+
+		return output, nil
 	} else {
 		return "", errors.New("unknown command")
 	}
@@ -166,26 +191,265 @@ func processStats(cmd []string, ws *WsClient) (string, error) {
 		return "", errors.New("game not initialized")
 	}
 
-	stat := cmd[1]
-	if stat == "dice" {
-		output := "\n\n"
+	switch cmd[1] {
+	case "dice":
+		return getDiceStats(g), nil
+	case "resources":
+		return getResourceStats(g), nil
+	case "robber":
+		return getRobberStats(g), nil
+	case "trades":
+		return getTradeStats(g), nil
+	case "events":
+		return getEventStats(g), nil
+	case "luck":
+		return getLuckStats(g), nil
+	case "cards":
+		return getCardStats(g), nil
+	}
 
-		sum := 0
-		for i := 0; i < len(g.DiceStats.Rolls); i++ {
-			sum += g.DiceStats.Rolls[i]
+	return "", errors.New("invalid stat")
+}
+
+// Names of card types in stats, by card type
+var statsCardNames = [9]string{"", "wood", "brick", "wool", "wheat", "ore", "paper", "cloth", "coin"}
+
+func getStatsTileName(t entities.TileType) string {
+	if t >= entities.TileTypeWood && t <= entities.TileTypeOre {
+		return statsCardNames[t]
+	}
+	if t == entities.TileTypeGold {
+		return "gold"
+	}
+	return "tile"
+}
+
+func getDiceStats(g *game.Game) string {
+	output := "\n\n"
+
+	sum := 0
+	for i := 0; i < len(g.DiceStats.Rolls); i++ {
+		sum += g.DiceStats.Rolls[i]
+	}
+
+	if sum == 0 {
+		output += "No dice have been rolled yet\n"
+		return output
+	}
+
+	for i := 1; i < len(g.DiceStats.Rolls); i++ {
+		output += fmt.Sprintf("%5v: %s\n", i+1, strings.Repeat("■", int(float64(g.DiceStats.Rolls[i])/float64(sum)*40)))
+	}
+
+	if deck := g.DiceStats.Deck; deck != nil {
+		output += fmt.Sprintf("\nBalanced dice: %d cards left, shuffled %d times\n", len(deck.Cards), deck.Reshuffles)
+	}
+
+	return output
+}
+
+// Cards produced by the dice for each player
+func getResourceStats(g *game.Game) string {
+	output := "\n\n"
+
+	for _, s := range g.Stats.Players {
+		total := s.Gold
+		cards := make([]string, 0)
+		for t, q := range s.Produced {
+			if q > 0 {
+				total += q
+				cards = append(cards, fmt.Sprintf("%s %d", statsCardNames[t], q))
+			}
+		}
+		if s.Gold > 0 {
+			cards = append(cards, fmt.Sprintf("gold %d", s.Gold))
 		}
 
-		if sum == 0 {
-			output += "No dice have been rolled yet\n"
-			return output, nil
+		output += fmt.Sprintf("%s: %d", g.Players[s.Order].Username, total)
+		if len(cards) > 0 {
+			output += " (" + strings.Join(cards, ", ") + ")"
+		}
+		output += "\n"
+	}
+
+	return output
+}
+
+// Production lost to the robber and cards taken by it
+func getRobberStats(g *game.Game) string {
+	output := "\n\n"
+
+	total := 0
+	for _, s := range g.Stats.Players {
+		total += s.Blocked
+		output += fmt.Sprintf("%s: %d blocked, %d stolen, %d robbed, %d discarded\n",
+			g.Players[s.Order].Username, s.Blocked, s.Stolen, s.Robbed, s.Discarded)
+	}
+	output += fmt.Sprintf("\nTotal blocked: %d\n", total)
+
+	tiles := make([]*entities.TileStats, 0)
+	for _, t := range g.Stats.GetTiles() {
+		if t.Blocked > 0 {
+			tiles = append(tiles, t)
 		}
+	}
+	sort.SliceStable(tiles, func(i, j int) bool { return tiles[i].Blocked > tiles[j].Blocked })
+	for _, t := range tiles {
+		output += fmt.Sprintf("%5s %2d: %d\n", getStatsTileName(t.Type), t.Number, t.Blocked)
+	}
+
+	return output
+}
 
-		for i := 1; i < len(g.DiceStats.Rolls); i++ {
-			output += fmt.Sprintf("%5v: %s\n", i+1, strings.Repeat("■", int(float64(g.DiceStats.Rolls[i])/float64(sum)*40)))
+// Trade partners and number of cards exchanged
+func getTradeStats(g *game.Game) string {
+	output := "\n\n"
+
+	for _, s := range g.Stats.Players {
+		trades := make([]string, 0)
+		for order, n := range s.Trades {
+			if n > 0 {
+				trades = append(trades, fmt.Sprintf("%d with %s (%d cards)", n, g.Players[order].Username, s.TradeVolume[order]))
+			}
+		}
+		if s.BankTrades > 0 {
+			trades = append(trades, fmt.Sprintf("%d with the bank (%d cards for %d)", s.BankTrades, s.BankGiven, s.BankTaken))
 		}
 
-		return output, nil
+		if len(trades) == 0 {
+			trades = append(trades, "no trades")
+		}
+		output += fmt.Sprintf("%s: %s\n", g.Players[s.Order].Username, strings.Join(trades, ", "))
 	}
 
-	return "", errors.New("invalid stat")
+	return output
+}
+
+// Faces of the event die in Cities and Knights
+func getEventStats(g *game.Game) string {
+	output := "\n\n"
+
+	if g.Mode != entities.CitiesAndKnights {
+		output += "The event die is only rolled in Cities and Knights\n"
+		return output
+	}
+
+	sum := 0
+	for _, n := range g.DiceStats.EventRolls {
+		sum += n
+	}
+
+	if sum == 0 {
+		output += "The event die has not been rolled yet\n"
+		return output
+	}
+
+	faces := []struct {
+		Name  string
+		Count int
+	}{
+		{"paper", g.DiceStats.EventRolls[0]},
+		{"cloth", g.DiceStats.EventRolls[1]},
+		{"coin", g.DiceStats.EventRolls[2]},
+		{"ship", g.DiceStats.EventRolls[3] + g.DiceStats.EventRolls[4] + g.DiceStats.EventRolls[5]},
+	}
+	for _, f := range faces {
+		output += fmt.Sprintf("%5v: %s %d\n", f.Name, strings.Repeat("■", int(float64(f.Count)/float64(sum)*40)), f.Count)
+	}
+
+	return output
+}
+
+// Production compared to the odds of the dice with the board at each roll
+func getLuckStats(g *game.Game) string {
+	output := "\n\n"
+
+	for _, s := range g.Stats.Players {
+		actual := s.Gold
+		for _, q := range s.Produced {
+			actual += q
+		}
+
+		output += fmt.Sprintf("%s: %d produced, %.1f expected (%+.1f)\n",
+			g.Players[s.Order].Username, actual, s.Expected, float64(actual)-s.Expected)
+	}
+
+	return output
+}
+
+// Cards in hand and cards played, as seen by everyone
+func getCardStats(g *game.Game) string {
+	output := "\n\n"
+
+	devName := "development"
+	if g.Mode == entities.CitiesAndKnights {
+		devName = "progress"
+	}
+
+	for _, p := range g.Players {
+		s := g.Stats.Players[p.Order]
+		output += fmt.Sprintf("%s: hand %d, %s %d",
+			p.Username, p.CurrentHand.GetCardCount(), devName, p.CurrentHand.GetDevelopmentCardCount())
+
+		played := 0
+		for _, n := range s.CardsPlayed {
+			played += n
+		}
+		output += fmt.Sprintf(", played %d", played)
+		if knights := s.CardsPlayed[entities.DevelopmentCardKnight]; knights > 0 {
+			output += fmt.Sprintf(" (knights %d)", knights)
+		}
+		output += "\n"
+	}
+
+	return output
+}
+
+func processUndo(cmd []string, ws *WsClient) (string, error) {
+	g := &ws.Hub.Game
+	defer g.Unlock()
+	if !g.Lock() {
+		return "", errors.New("game not initialized")
+	}
+
+	if len(cmd) == 1 {
+		if err := g.CanUndo(ws.Player); err != nil {
+			return "", err
+		}
+
+		if ws.Hub.isHost(ws.Player) {
+			return "", ws.Hub.undo(ws.Player)
+		}
+
+		ws.Hub.undoRequest = &UndoRequest{
+			Order:        ws.Player.Order,
+			JournalIndex: g.JournalIndex(),
+		}
+		ws.Hub.BroadcastChat(ws.Player, ws.Player.Username+" asks to undo their last action, the host can answer with !undo yes or !undo no")
+		return "\n\nAsked the host to undo your last action\n", nil
+	}
+
+	if !ws.Hub.isHost(ws.Player) {
+		return "", errors.New("only the host can answer")
+	}
+
+	if cmd[1] != "yes" && cmd[1] != "no" {
+		return "", errors.New("answer with yes or no")
+	}
+
+	req := ws.Hub.undoRequest
+	ws.Hub.undoRequest = nil
+
+	// Anything written to the journal since makes the request stale
+	if req == nil || req.JournalIndex != g.JournalIndex() {
+		return "", errors.New("no pending request")
+	}
+
+	p := g.Players[req.Order]
+	if cmd[1] == "no" {
+		ws.Hub.BroadcastChat(p, "The host declined to undo the last action of "+p.Username)
+		return "", nil
+	}
+
+	return "", ws.Hub.undo(p)
 }
@@ -50,7 +50,9 @@ func (ws *WsClient) handleGame(msg map[string]interface{}) {
 				return
 			}
 
-			err = ws.Hub.Game.BuildSettlement(ws.Player, loc)
+			err = ws.Hub.Game.Reversible(func() error {
+				return ws.Hub.Game.BuildSettlement(ws.Player, loc)
+			})
 			if err != nil {
 				ws.Hub.Game.SendError(err, ws.Player)
 				return
@@ -81,14 +83,16 @@ func (ws *WsClient) handleGame(msg map[string]interface{}) {
 				ws.Hub.Game.SendError(err, ws.Player)
 				return
 			}
-			err = ws.Hub.Game.BuildCity(ws.Player, loc)
+			err = ws.Hub.Game.Reversible(func() error {
+				return ws.Hub.Game.BuildCity(ws.Player, loc)
+			})
 			if err != nil {
 				ws.Hub.Game.SendError(err, ws.Player)
 				return
 			}
 
 		case "r": // Road
-			edges := ws.Player.GetBuildLocationsRoad(ws.Hub.Game.Graph, false)
+			edges := ws.Hub.Game.GetRoadLocations(ws.Player)
 			if len(edges) == 0 || ws.Player.CanBuild(entities.BTRoad) != nil {
 				ws.Hub.Game.SendError(errors.New("nowhere to build or cannot build"), ws.Player)
 				return
@@ -113,14 +117,119 @@ func (ws *WsClient) handleGame(msg map[string]interface{}) {
 				return
 			}
 
-			err = ws.Hub.Game.BuildRoad(ws.Player, loc)
+			err = ws.Hub.Game.Reversible(func() error {
+				return ws.Hub.Game.BuildRoad(ws.Player, loc)
+			})
+			if err != nil {
+				ws.Hub.Game.SendError(err, ws.Player)
+				return
+			}
+
+		case "sh": // Ship
+			if ws.Hub.Game.Mode != entities.Seafarers {
+				return
+			}
+
+			edges := ws.Player.GetBuildLocationsShip(ws.Hub.Game.Graph, ws.Hub.Game.Pirate.Tile)
+			if len(edges) == 0 || ws.Player.CanBuild(entities.BTShip) != nil {
+				ws.Hub.Game.SendError(errors.New("nowhere to build or cannot build"), ws.Player)
+				return
+			}
+
+			defer ws.Hub.Game.BroadcastState()
+			res, err := ws.Hub.Game.BlockForAction(ws.Player, 0, &entities.PlayerAction{
+				Type:      entities.PlayerActionTypeChooseEdge,
+				Message:   "Choose location for ship",
+				CanCancel: true,
+				Data:      entities.PlayerActionChooseEdge{Allowed: edges},
+			})
+
+			if res == nil || err != nil { // Cancelled
+				return
+			}
+
+			var loc entities.EdgeCoordinate
+			err = mapstructure.Decode(res, &loc)
+			if err != nil {
+				ws.Hub.Game.SendError(err, ws.Player)
+				return
+			}
+
+			err = ws.Hub.Game.Reversible(func() error {
+				return ws.Hub.Game.BuildShip(ws.Player, loc)
+			})
+			if err != nil {
+				ws.Hub.Game.SendError(err, ws.Player)
+				return
+			}
+
+		case "ms": // Move Ship
+			if err := ws.Hub.Game.CanMoveShip(ws.Player); err != nil {
+				ws.Hub.Game.SendError(err, ws.Player)
+				return
+			}
+
+			defer ws.Hub.Game.BroadcastState()
+			res, err := ws.Hub.Game.BlockForAction(ws.Player, 0, &entities.PlayerAction{
+				Type:      entities.PlayerActionTypeChooseEdge,
+				Message:   "Choose ship to move",
+				CanCancel: true,
+				Data: entities.PlayerActionChooseEdge{
+					Allowed: ws.Player.GetMovableShips(ws.Hub.Game.Graph, ws.Hub.Game.Pirate.Tile),
+				},
+			})
+
+			if res == nil || err != nil { // Cancelled
+				return
+			}
+
+			var from entities.EdgeCoordinate
+			err = mapstructure.Decode(res, &from)
+			if err != nil {
+				ws.Hub.Game.SendError(err, ws.Player)
+				return
+			}
+
+			e, err := ws.Hub.Game.Graph.GetEdge(from)
+			if err != nil {
+				ws.Hub.Game.SendError(err, ws.Player)
+				return
+			}
+
+			edges := ws.Hub.Game.GetShipMoveLocations(ws.Player, e)
+			if len(edges) == 0 {
+				ws.Hub.Game.SendError(errors.New("nowhere to move this ship"), ws.Player)
+				return
+			}
+
+			res, err = ws.Hub.Game.BlockForAction(ws.Player, 0, &entities.PlayerAction{
+				Type:      entities.PlayerActionTypeChooseEdge,
+				Message:   "Choose location for ship",
+				CanCancel: true,
+				Data:      entities.PlayerActionChooseEdge{Allowed: edges},
+			})
+
+			if res == nil || err != nil { // Cancelled
+				return
+			}
+
+			var to entities.EdgeCoordinate
+			err = mapstructure.Decode(res, &to)
+			if err != nil {
+				ws.Hub.Game.SendError(err, ws.Player)
+				return
+			}
+
+			err = ws.Hub.Game.Reversible(func() error {
+				return ws.Hub.Game.MoveShip(ws.Player, from, to)
+			})
 			if err != nil {
 				ws.Hub.Game.SendError(err, ws.Player)
 				return
 			}
 
 		case "dc": // Development card
-			if ws.Hub.Game.Mode != entities.Base {
+			if !ws.Hub.Game.Mode.UsesBaseCards() {
 				return
 			}
 
@@ -131,7 +240,7 @@ func (ws *WsClient) handleGame(msg map[string]interface{}) {
 			}
 
 		case "k": // Knight
-			if ws.Hub.Game.Mode != entities.CitiesAndKnights {
+			if ws.Hub.Game.Mode != entities.CitiesAndKnights && !ws.Hub.Game.Mode.HasBarbarianAttack() {
 				return
 			}
 
@@ -164,7 +273,67 @@ func (ws *WsClient) handleGame(msg map[string]interface{}) {
 				return
 			}
 
-			err = ws.Hub.Game.BuildKnight(ws.Player, loc)
+			err = ws.Hub.Game.Reversible(func() error {
+				return ws.Hub.Game.BuildKnight(ws.Player, loc)
+			})
+			if err != nil {
+				ws.Hub.Game.SendError(err, ws.Player)
+				return
+			}
+
+		case "fi": // Fish
+			var amount int
+			err := mapstructure.Decode(msg["fa"], &amount)
+			if err != nil {
+				ws.Hub.Game.SendError(err, ws.Player)
+				return
+			}
+
+			err = ws.Hub.Game.UseFish(ws.Player, amount)
+			if err != nil {
+				ws.Hub.Game.SendError(err, ws.Player)
+				return
+			}
+
+		case "go": // Gold
+			if err := ws.Hub.Game.CanSpendGold(ws.Player); err != nil {
+				ws.Hub.Game.SendError(err, ws.Player)
+				return
+			}
+
+			defer ws.Hub.Game.BroadcastState()
+			res, err := ws.Hub.Game.BlockForAction(ws.Player, 0, &entities.PlayerAction{
+				Type:      entities.PlayerActionTypeSelectCards,
+				Message:   "Choose a card to buy with gold",
+				CanCancel: true,
+				Data: &entities.PlayerActionSelectCards{
+					AllowedTypes: []int{1, 2, 3, 4, 5},
+					Quantity:     1,
+					NotSelfHand:  true,
+				},
+			})
+
+			if res == nil || err != nil { // Cancelled
+				return
+			}
+
+			var resp []float64
+			err = mapstructure.Decode(res, &resp)
+			if err != nil || len(resp) != 9 {
+				ws.Hub.Game.SendError(errors.New("invalid selection"), ws.Player)
+				return
+			}
+			ws.Player.SendAction(&entities.PlayerAction{Type: entities.PlayerActionTypeSelectCardsDone})
+
+			ct := entities.CardType(0)
+			for t := entities.CardTypeWood; t <= entities.CardTypeOre; t++ {
+				if resp[t] > 0 {
+					ct = t
+					break
+				}
+			}
+
+			err = ws.Hub.Game.SpendGold(ws.Player, ct)
 			if err != nil {
 				ws.Hub.Game.SendError(err, ws.Player)
 				return
@@ -200,7 +369,9 @@ func (ws *WsClient) handleGame(msg map[string]interface{}) {
 				return
 			}
 
-			err = ws.Hub.Game.ActivateKnight(ws.Player, loc)
+			err = ws.Hub.Game.Reversible(func() error {
+				return ws.Hub.Game.ActivateKnight(ws.Player, loc)
+			})
 			if err != nil {
 				ws.Hub.Game.SendError(err, ws.Player)
 				return
@@ -240,7 +411,9 @@ func (ws *WsClient) handleGame(msg map[string]interface{}) {
 				return
 			}
 
-			err = ws.Hub.Game.BuildCityImprovement(ws.Player, ct)
+			err = ws.Hub.Game.Reversible(func() error {
+				return ws.Hub.Game.BuildCityImprovement(ws.Player, ct)
+			})
 			if err != nil {
 				ws.Hub.Game.SendError(err, ws.Player)
 				return
@@ -275,7 +448,9 @@ func (ws *WsClient) handleGame(msg map[string]interface{}) {
 				ws.Hub.Game.SendError(err, ws.Player)
 				return
 			}
-			err = ws.Hub.Game.BuildWall(ws.Player, loc)
+			err = ws.Hub.Game.Reversible(func() error {
+				return ws.Hub.Game.BuildWall(ws.Player, loc)
+			})
 			if err != nil {
 				ws.Hub.Game.SendError(err, ws.Player)
 				return
@@ -324,7 +499,11 @@ func (ws *WsClient) handleGame(msg map[string]interface{}) {
 				return
 			}
 
-			_, err = ws.Hub.Game.CreateOffer(ws.Player, &offerDetails)
+			// Trades with the bank can be undone
+			err = ws.Hub.Game.Reversible(func() error {
+				_, err := ws.Hub.Game.CreateOffer(ws.Player, &offerDetails)
+				return err
+			})
 			if err != nil {
 				ws.Hub.Game.SendError(err, ws.Player)
 				return
@@ -400,7 +579,7 @@ func (ws *WsClient) sendInitMessage() {
 	// This MUST be the first message sent to the client
 	ws.Player.SendMessage(&entities.Message{
 		Type: "i-st",
-		Data: ws.Hub.Game.Settings,
+		Data: ws.Hub.Game.GetPublicSettings(),
 	})
 
 	// Mapping
@@ -15,6 +15,9 @@ import (
 	_ "github.com/joho/godotenv/autoload"
 )
 
+// Bot tokens stop working after this, the owner creates a new one
+const BotTokenLifetime = 30 * 24 * time.Hour
+
 type (
 	ContextKey    string
 	JWTMiddleware struct {
@@ -42,6 +45,7 @@ func (m *JWTMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request, next h
 	if _, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
 		ctx := context.WithValue(r.Context(), ContextKey("username"), token.Claims.(jwt.MapClaims)["username"])
 		ctx = context.WithValue(ctx, ContextKey("id"), token.Claims.(jwt.MapClaims)["id"])
+		ctx = context.WithValue(ctx, ContextKey("bot"), token.Claims.(jwt.MapClaims)["bot"])
 		ctx = context.WithValue(ctx, ContextKey("claims"), token.Claims.(jwt.MapClaims))
 		next(w, r.WithContext(ctx))
 		return
@@ -83,6 +87,24 @@ func GenerateJWT(id, username string) (string, error) {
 	return token.SignedString(hmacSecret)
 }
 
+// Token for an external bot, only accepted by the bot endpoint
+// owner is the id of the user that created the bot
+func GenerateBotJWT(id, username, owner string) (string, error) {
+	hmacSecret := []byte(os.Getenv("HMAC_SECRET"))
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"version":  2,
+		"id":       id,
+		"username": username,
+		"owner":    owner,
+		"bot":      true,
+		"nbf":      json.Number(strconv.FormatInt(time.Date(2015, 10, 10, 12, 0, 0, 0, time.UTC).Unix(), 10)),
+		"iat":      json.Number(strconv.FormatInt(time.Now().Unix(), 10)),
+		"exp":      json.Number(strconv.FormatInt(time.Now().Add(BotTokenLifetime).Unix(), 10)),
+	})
+
+	return token.SignedString(hmacSecret)
+}
+
 func VerifyJWT(token string) (*jwt.Token, error) {
 	// Parse takes the token string and a function for looking up the key. The latter is especially
 	// useful if you use multiple keys for your application.  The standard is to use 'kid' in the
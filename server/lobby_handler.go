@@ -2,11 +2,12 @@ package server
 
 import (
 	"imperials/entities"
+	"imperials/game"
+	"imperials/maps"
 	"log"
-	"math/rand"
+	"math"
 	"sort"
 	"sync/atomic"
-	"time"
 
 	"github.com/mitchellh/mapstructure"
 	"github.com/vmihailenco/msgpack/v5"
@@ -20,9 +21,11 @@ const (
 	WsLobbyRequestTypeSetSettings         string = "ss"
 	WsLobbyRequestTypeSetAdvancedSettings string = "sas"
 	WsLobbyRequestTypeBotAdd              string = "bot_a"
+	WsLobbyRequestTypeBotStrategy         string = "bot_s"
 	WsLobbyRequestTypeKick                string = "k"
 	WsLobbyRequestTypeReady               string = "r"
 	WsLobbyRequestTypeStartGame           string = "sg"
+	WsLobbyRequestTypeRerollMap           string = "rm"
 
 	// Response Types
 	WsLobbyResponseTypePlayers          string = "rr-lp"
@@ -41,6 +44,19 @@ func (ws *WsClient) handleLobby(msg map[string]interface{}) {
 		return
 	}
 
+	// Matches cannot be changed and start on their own
+	if ws.Hub.Match != nil {
+		switch msg["t"] {
+		case WsLobbyRequestTypeInit, WsLobbyRequestTypeUpdateUsername, WsLobbyRequestTypeReady:
+		default:
+			ws.sendLobbyMessage(&entities.Message{
+				Type: entities.MessageTypeError,
+				Data: "the settings of a match are locked",
+			})
+			return
+		}
+	}
+
 	switch msg["t"] {
 	case WsLobbyRequestTypeInit:
 		ws.Hub.BroadcastLobbyMessage(ws.Hub.GetLobbyPlayersMessage())
@@ -52,7 +68,7 @@ func (ws *WsClient) handleLobby(msg map[string]interface{}) {
 			return
 		}
 		for i := 0; i < 3; i++ {
-			ws.Hub.StartBot()
+			ws.Hub.StartBot(game.BotStrategyDefault, entities.BotDifficultyMedium)
 		}
 		ws.Hub.Game.Settings.Private = false
 		ws.Hub.Game.Settings.EnableKarma = false
@@ -65,7 +81,7 @@ func (ws *WsClient) handleLobby(msg map[string]interface{}) {
 	case WsLobbyRequestTypeUpdateUsername:
 		var username string
 		mapstructure.Decode(msg["username"], &username)
-		if !IsValidUsername(username) {
+		if ws.External || !IsValidUsername(username) {
 			ws.sendLobbyMessage(&entities.Message{
 				Type: entities.MessageTypeError,
 				Data: "invalid username",
@@ -83,10 +99,47 @@ func (ws *WsClient) handleLobby(msg map[string]interface{}) {
 			})
 			return
 		}
+		mapName := ws.Hub.Game.Settings.MapName
+		seed := ws.Hub.Game.Settings.Seed
+		matched := ws.Hub.Game.Settings.Matched
+		ranked := ws.Hub.Game.Settings.Ranked
 		mapstructure.Decode(msg["settings"], &ws.Hub.Game.Settings)
+
+		// The seed is chosen by the server when the game starts,
+		// and only the matchmaking queue makes ranked games
+		ws.Hub.Game.Settings.Seed = seed
+		ws.Hub.Game.Settings.Matched = matched
+		ws.Hub.Game.Settings.Ranked = ranked
+
+		// Maps that cannot make a board are refused when they are picked
+		if name := ws.Hub.Game.Settings.MapName; name != mapName && name != maps.GeneratedMapName {
+			if info := ws.Hub.Game.Store.GetMapInfo(name); info != nil {
+				problem := ""
+				if !info.CanView(ws.Player.Id) {
+					problem = "map not found"
+				} else if err := maps.ValidateMapDefinition(info.Defn); err != nil {
+					problem = "invalid map: " + err.Error()
+				}
+
+				if problem != "" {
+					ws.Hub.Game.Settings.MapName = mapName
+					ws.sendLobbyMessage(&entities.Message{
+						Type: entities.MessageTypeError,
+						Data: problem,
+					})
+				} else {
+					ws.Hub.Game.Settings.MapDefn = info.Defn
+					ws.Hub.Game.Settings.MapVersion = info.Version
+				}
+			}
+		}
+
 		go ws.Hub.StoreSettings()
 		ws.Hub.BroadcastLobbyMessage(ws.Hub.GetLobbySettingsMessage())
 
+		// Ratings depend on the mode and number of players
+		ws.Hub.BroadcastLobbyMessage(ws.Hub.GetLobbyPlayersMessage())
+
 	case WsLobbyRequestTypeSetAdvancedSettings:
 		if !ws.Hub.Game.Settings.Advanced {
 			ws.sendLobbyMessage(&entities.Message{
@@ -103,9 +156,40 @@ func (ws *WsClient) handleLobby(msg map[string]interface{}) {
 			})
 			return
 		}
-		mapstructure.Decode(msg["advanced"], &ws.Hub.Game.AdvancedSettings)
+
+		var advanced entities.AdvancedSettings
+		mapstructure.Decode(msg["advanced"], &advanced)
+		if err := advanced.Validate(); err != nil {
+			ws.sendLobbyMessage(&entities.Message{
+				Type: entities.MessageTypeError,
+				Data: err.Error(),
+			})
+			return
+		}
+		ws.Hub.Game.AdvancedSettings = advanced
 		ws.Hub.BroadcastLobbyMessage(ws.Hub.GetLobbyAdvancedSettingsMessage())
 
+	case WsLobbyRequestTypeRerollMap:
+		if ws.Player.Order != 0 {
+			ws.sendLobbyMessage(&entities.Message{
+				Type: entities.MessageTypeError,
+				Data: "only host can change settings",
+			})
+			return
+		}
+
+		if ws.Hub.Game.Settings.MapName != maps.GeneratedMapName {
+			ws.sendLobbyMessage(&entities.Message{
+				Type: entities.MessageTypeError,
+				Data: "only a random map can be generated again",
+			})
+			return
+		}
+
+		// A new board is generated when the settings are stored
+		ws.Hub.Game.Settings.MapDefn = nil
+		go ws.Hub.StoreSettings()
+
 	case WsLobbyRequestTypeBotAdd:
 		if ws.Hub.Game.Initialized {
 			return
@@ -119,7 +203,11 @@ func (ws *WsClient) handleLobby(msg map[string]interface{}) {
 			return
 		}
 
-		err := ws.Hub.StartBot()
+		var strategy, difficulty string
+		mapstructure.Decode(msg["strategy"], &strategy)
+		mapstructure.Decode(msg["difficulty"], &difficulty)
+
+		err := ws.Hub.StartBot(strategy, difficulty)
 		if err != nil {
 			ws.sendLobbyMessage(&entities.Message{
 				Type: entities.MessageTypeError,
@@ -129,6 +217,40 @@ func (ws *WsClient) handleLobby(msg map[string]interface{}) {
 		}
 		ws.Hub.BroadcastLobbyMessage(ws.Hub.GetLobbyPlayersMessage())
 
+	case WsLobbyRequestTypeBotStrategy:
+		if ws.Hub.Game.Initialized {
+			return
+		}
+
+		if ws.Player.Order != 0 {
+			ws.sendLobbyMessage(&entities.Message{
+				Type: entities.MessageTypeError,
+				Data: "only host can change bots",
+			})
+			return
+		}
+
+		var u, strategy string
+		mapstructure.Decode(msg["username"], &u)
+		mapstructure.Decode(msg["strategy"], &strategy)
+		if !game.IsValidBotStrategy(strategy) {
+			ws.sendLobbyMessage(&entities.Message{
+				Type: entities.MessageTypeError,
+				Data: "unknown bot strategy",
+			})
+			return
+		}
+
+		ws.Hub.Clients.Range(func(key, value interface{}) bool {
+			c := key.(*WsClient)
+			if c.Player.Username == u && c.Player.GetIsBot() {
+				c.Player.BotStrategy = strategy
+				return false
+			}
+			return true
+		})
+		ws.Hub.BroadcastLobbyMessage(ws.Hub.GetLobbyPlayersMessage())
+
 	case WsLobbyRequestTypeKick:
 		if ws.Hub.Game.Initialized {
 			return
@@ -219,7 +341,7 @@ func startGame(gameId string, numPlayers int32, hub *WsHub) {
 		hub.Game.Store.CreateGameStateIfNotExists(gameId, serialized)
 	}
 
-	if g.InitPhase {
+	if g.InitPhase && !g.ReadOnly {
 		clientPlayers := make([]*entities.Player, 0)
 
 		hub.Clients.Range(func(key, value interface{}) bool {
@@ -228,19 +350,31 @@ func startGame(gameId string, numPlayers int32, hub *WsHub) {
 			return true
 		})
 
+		// Seat players in lobby order so the seed decides the shuffle
+		sort.Slice(clientPlayers, func(i, j int) bool {
+			return clientPlayers[i].Order < clientPlayers[j].Order
+		})
+
 		playerOrder := make([]int, len(hub.Game.Players))
 		for i := range hub.Game.Players {
 			playerOrder[i] = i
 		}
 
-		rand.Seed(time.Now().UnixNano())
-		rand.Shuffle(len(playerOrder), func(i, j int) {
+		if len(clientPlayers) > 0 {
+			hub.HostId = clientPlayers[0].Id
+		}
+
+		hub.Game.Rand.Shuffle(len(playerOrder), func(i, j int) {
 			playerOrder[i], playerOrder[j] = playerOrder[j], playerOrder[i]
 		})
 
 		for i, cp := range clientPlayers {
 			hub.Game.SetUsername(hub.Game.Players[playerOrder[i]], cp.Username)
 			hub.Game.SetId(hub.Game.Players[playerOrder[i]], cp.Id)
+			if cp.GetIsBot() {
+				hub.Game.SetBotStrategy(hub.Game.Players[playerOrder[i]], cp.BotStrategy)
+				hub.Game.SetBotDifficulty(hub.Game.Players[playerOrder[i]], cp.BotDifficulty)
+			}
 			cp.Order = uint16(playerOrder[i])
 			hub.Game.Store.WriteGameIdForUser(gameId, cp.Id, &hub.Game.Settings)
 		}
@@ -260,7 +394,7 @@ func startGame(gameId string, numPlayers int32, hub *WsHub) {
 		hub.Game.Store.WriteGameState(gameId, serialized)
 	}
 
-	if g.InitPhase {
+	if g.InitPhase && !g.ReadOnly {
 		g.RunInitPhase()
 	}
 }
@@ -280,6 +414,17 @@ func (ws *WsClient) SendGameStartedMessage() {
 	})
 }
 
+// Rating for the mode and number of players of the lobby, zero for bots
+func (c *WsClient) getRating() int {
+	if c.Ratings == nil {
+		return 0
+	}
+
+	settings := c.Hub.Game.Settings
+	r := entities.FindRating(c.Ratings, c.Player.Id, settings.Mode, settings.MaxPlayers)
+	return int(math.Round(r.Rating))
+}
+
 func (h *WsHub) GetLobbyPlayersMessage() *entities.Message {
 	players := make([]*entities.LobbyPlayerState, 0)
 	h.Clients.Range(func(key, value interface{}) bool {
@@ -292,6 +437,10 @@ func (h *WsHub) GetLobbyPlayersMessage() *entities.Message {
 			Ready:         c.Ready,
 			GamesStarted:  c.GamesStarted,
 			GamesFinished: c.GamesFinished,
+			BotStrategy:   p.BotStrategy,
+			BotDifficulty: p.BotDifficulty,
+			External:      c.External,
+			Rating:        c.getRating(),
 		})
 		return true
 	})
@@ -308,7 +457,7 @@ func (h *WsHub) GetLobbyPlayersMessage() *entities.Message {
 }
 
 func (ws *WsClient) GetLobbySettingsOptionsMessage() *entities.Message {
-	mapNames := ws.Hub.Game.Store.GetOfficalMapNames()
+	mapNames := append(ws.Hub.Game.Store.GetOfficalMapNames(), maps.GeneratedMapName)
 
 	myMaps, err := ws.Hub.Game.Store.GetAllMapNamesForUser(ws.Player.Id, false)
 	if err == nil && len(myMaps) > 0 {
@@ -328,7 +477,9 @@ func (ws *WsClient) GetLobbySettingsOptionsMessage() *entities.Message {
 		Location: entities.WsMsgLocationLobby,
 		Type:     WsLobbyResponseTypeSettingsOptions,
 		Data: map[string]interface{}{
-			"MapName": mapNames,
+			"MapName":       mapNames,
+			"BotStrategy":   game.GetBotStrategyNames(),
+			"BotDifficulty": entities.BotDifficulties,
 		},
 	}
 }
@@ -337,7 +488,7 @@ func (h *WsHub) GetLobbySettingsMessage() *entities.Message {
 	return &entities.Message{
 		Location: entities.WsMsgLocationLobby,
 		Type:     WsLobbyResponseTypeSettings,
-		Data:     h.Game.Settings,
+		Data:     h.Game.GetPublicSettings(),
 	}
 }
 
@@ -0,0 +1,237 @@
+package server
+
+import (
+	"encoding/json"
+	"imperials/entities"
+	"imperials/maps"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/mitchellh/mapstructure"
+)
+
+// Body of a request to create or update a map
+type MapRequest struct {
+	Defn    *entities.MapDefinition `json:"map"`
+	Private bool                    `json:"private"`
+
+	// Version the change was made to, the update is refused if the map
+	// was saved again since. Zero to skip the check.
+	Version int `json:"version"`
+}
+
+const maxMapNameLength = 40
+
+// Id of the user making the request, empty for bots
+func getMapUser(r *http.Request) string {
+	var id string
+	var isBot bool
+	mapstructure.Decode(r.Context().Value(ContextKey("id")), &id)
+	mapstructure.Decode(r.Context().Value(ContextKey("bot")), &isBot)
+
+	if isBot {
+		return ""
+	}
+	return id
+}
+
+func IsValidMapName(name string) bool {
+	if strings.TrimSpace(name) != name || name == "" || len(name) > maxMapNameLength {
+		return false
+	}
+
+	// The lobby uses dashes for its separators
+	return name != maps.GeneratedMapName && !strings.ContainsAny(name, "/\\") && !strings.HasPrefix(name, "--")
+}
+
+// Names of the maps the user can play
+func (s *Server) listMaps(w http.ResponseWriter, r *http.Request) {
+	user := getMapUser(r)
+	if user == "" {
+		WriteJson(w, http.StatusUnauthorized, map[string]string{"error": "Only users can list maps"})
+		return
+	}
+
+	mine, err := s.store.GetAllMapNamesForUser(user, false)
+	if err != nil {
+		WriteJson(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	community, err := s.store.GetAllMapNamesForUser(user, true)
+	if err != nil {
+		WriteJson(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	// Empty lists instead of null
+	WriteJson(w, http.StatusOK, map[string][]string{
+		"official":  append([]string{}, s.store.GetOfficalMapNames()...),
+		"mine":      append([]string{}, mine...),
+		"community": append([]string{}, community...),
+	})
+}
+
+func (s *Server) getMap(w http.ResponseWriter, r *http.Request) {
+	user := getMapUser(r)
+	name := mux.Vars(r)["name"]
+
+	info := s.store.GetMapInfo(name)
+	if info == nil || !info.CanView(user) {
+		WriteJson(w, http.StatusNotFound, map[string]string{"error": "Map not found"})
+		return
+	}
+
+	if v := r.URL.Query().Get("version"); v != "" {
+		version, err := strconv.Atoi(v)
+		if err != nil {
+			WriteJson(w, http.StatusBadRequest, map[string]string{"error": "Invalid version"})
+			return
+		}
+
+		info = s.store.GetMapVersion(name, version)
+		if info == nil {
+			WriteJson(w, http.StatusNotFound, map[string]string{"error": "Version not found"})
+			return
+		}
+	}
+
+	WriteJson(w, http.StatusOK, info)
+}
+
+func (s *Server) getMapVersions(w http.ResponseWriter, r *http.Request) {
+	user := getMapUser(r)
+	name := mux.Vars(r)["name"]
+
+	info := s.store.GetMapInfo(name)
+	if info == nil || !info.CanView(user) {
+		WriteJson(w, http.StatusNotFound, map[string]string{"error": "Map not found"})
+		return
+	}
+
+	versions, err := s.store.GetMapVersions(name)
+	if err != nil {
+		WriteJson(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	WriteJson(w, http.StatusOK, versions)
+}
+
+func (s *Server) createMap(w http.ResponseWriter, r *http.Request) {
+	user := getMapUser(r)
+	if user == "" {
+		WriteJson(w, http.StatusUnauthorized, map[string]string{"error": "Only users can create maps"})
+		return
+	}
+
+	req, ok := decodeMapRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if !IsValidMapName(req.Defn.Name) {
+		WriteJson(w, http.StatusBadRequest, map[string]string{"error": "Invalid map name"})
+		return
+	}
+
+	// The store refuses a name that is taken, even by a map created just now
+	s.writeMap(w, s.store.CreateMapInfo, &entities.MapInfo{
+		Name:    req.Defn.Name,
+		Creator: user,
+		Private: req.Private,
+		Defn:    req.Defn,
+	})
+}
+
+// Save a new version of a map of the user
+func (s *Server) updateMap(w http.ResponseWriter, r *http.Request) {
+	user := getMapUser(r)
+	name := mux.Vars(r)["name"]
+
+	info := s.store.GetMapInfo(name)
+	if info == nil || !info.CanView(user) {
+		WriteJson(w, http.StatusNotFound, map[string]string{"error": "Map not found"})
+		return
+	}
+	if !info.CanEdit(user) {
+		WriteJson(w, http.StatusForbidden, map[string]string{"error": "Only the creator can change a map"})
+		return
+	}
+
+	req, ok := decodeMapRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if req.Version != 0 && req.Version != info.Version {
+		WriteJson(w, http.StatusConflict, map[string]string{"error": "Map was changed since version " + strconv.Itoa(req.Version)})
+		return
+	}
+
+	// Maps cannot be renamed, the name is how games refer to them
+	req.Defn.Name = name
+	s.writeMap(w, s.store.WriteMapInfo, &entities.MapInfo{
+		Name:    name,
+		Creator: user,
+		Private: req.Private,
+		Defn:    req.Defn,
+	})
+}
+
+func (s *Server) deleteMap(w http.ResponseWriter, r *http.Request) {
+	user := getMapUser(r)
+	name := mux.Vars(r)["name"]
+
+	info := s.store.GetMapInfo(name)
+	if info == nil || !info.CanView(user) {
+		WriteJson(w, http.StatusNotFound, map[string]string{"error": "Map not found"})
+		return
+	}
+	if !info.CanEdit(user) {
+		WriteJson(w, http.StatusForbidden, map[string]string{"error": "Only the creator can delete a map"})
+		return
+	}
+
+	if err := s.store.DeleteMap(name); err != nil {
+		WriteJson(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func decodeMapRequest(w http.ResponseWriter, r *http.Request) (*MapRequest, bool) {
+	var req MapRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Defn == nil {
+		WriteJson(w, http.StatusBadRequest, map[string]string{"error": "Invalid map"})
+		return nil, false
+	}
+	return &req, true
+}
+
+// Validate and store a map, the errors of the definition are sent back
+func (s *Server) writeMap(w http.ResponseWriter, save func(*entities.MapInfo) (*entities.MapInfo, error), info *entities.MapInfo) {
+	if err := maps.ValidateMapDefinition(info.Defn); err != nil {
+		WriteJson(w, http.StatusBadRequest, map[string]interface{}{"error": "Invalid map", "errors": err})
+		return
+	}
+
+	saved, err := save(info)
+	if err == entities.ErrMapExists {
+		WriteJson(w, http.StatusConflict, map[string]string{"error": "Map already exists"})
+		return
+	}
+	if err == entities.ErrMapNotFound {
+		WriteJson(w, http.StatusNotFound, map[string]string{"error": "Map not found"})
+		return
+	}
+	if err != nil {
+		WriteJson(w, http.StatusConflict, map[string]string{"error": err.Error()})
+		return
+	}
+
+	WriteJson(w, http.StatusOK, saved)
+}
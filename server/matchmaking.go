@@ -0,0 +1,356 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"imperials/entities"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+const (
+	// Wait in the queue before a player gets a game with bots
+	MATCHMAKING_TIMEOUT_SEC = 90
+
+	// Wait for the matched players to join before bots take their seats
+	MATCHMAKING_JOIN_SEC = 60
+
+	// Difference of ratings allowed in a game, it grows every second
+	// the oldest player of the game has waited
+	matchRatingWindow    = 100.0
+	matchRatingWindowSec = 10.0
+)
+
+type (
+	// Players waiting for a game, in the order they joined the queue
+	Matchmaker struct {
+		mutex   sync.Mutex
+		tickets []*matchTicket
+	}
+
+	matchTicket struct {
+		UserID   string
+		Mode     entities.GameMode
+		Players  int
+		Region   string
+		Rating   float64
+		QueuedAt time.Time
+
+		// Receives the id of the game, empty if it could not be made
+		game chan string
+	}
+
+	matchGroup struct {
+		Tickets []*matchTicket
+		Ranked  bool
+	}
+
+	// Game made by the matchmaking queue
+	// The settings are locked and only the matched users can join the lobby
+	Match struct {
+		Users []string
+	}
+)
+
+func (m *Matchmaker) Add(t *matchTicket) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, o := range m.tickets {
+		if o.UserID == t.UserID {
+			return errors.New("already in the queue")
+		}
+	}
+	m.tickets = append(m.tickets, t)
+	return nil
+}
+
+func (m *Matchmaker) Remove(t *matchTicket) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for i, o := range m.tickets {
+		if o == t {
+			m.tickets = append(m.tickets[:i], m.tickets[i+1:]...)
+			return
+		}
+	}
+}
+
+// Take the players that can play together out of the queue
+// Players are grouped with the closest ratings in their window,
+// or with anyone in the same queue once they have waited too long
+func (m *Matchmaker) takeMatches(now time.Time) []*matchGroup {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	taken := make(map[*matchTicket]bool)
+	groups := make([]*matchGroup, 0)
+	for _, first := range m.tickets {
+		if taken[first] {
+			continue
+		}
+
+		waited := now.Sub(first.QueuedAt).Seconds()
+		timedOut := waited >= MATCHMAKING_TIMEOUT_SEC
+		window := matchRatingWindow + matchRatingWindowSec*waited
+
+		others := make([]*matchTicket, 0)
+		for _, t := range m.tickets {
+			if t == first || taken[t] || t.Mode != first.Mode || t.Players != first.Players || t.Region != first.Region {
+				continue
+			}
+			if timedOut || math.Abs(t.Rating-first.Rating) <= window {
+				others = append(others, t)
+			}
+		}
+		sort.SliceStable(others, func(i, j int) bool {
+			return math.Abs(others[i].Rating-first.Rating) < math.Abs(others[j].Rating-first.Rating)
+		})
+
+		group := &matchGroup{Ranked: true}
+		if len(others) >= first.Players-1 {
+			others = others[:first.Players-1]
+		} else if timedOut {
+			// Bots take the empty seats, which leaves the game unrated
+			group.Ranked = false
+		} else {
+			continue
+		}
+
+		group.Tickets = append([]*matchTicket{first}, others...)
+		for _, t := range group.Tickets {
+			taken[t] = true
+		}
+		groups = append(groups, group)
+	}
+
+	left := make([]*matchTicket, 0, len(m.tickets))
+	for _, t := range m.tickets {
+		if !taken[t] {
+			left = append(left, t)
+		}
+	}
+	m.tickets = left
+
+	return groups
+}
+
+// Queue for a ranked game with a mode and number of players
+// The request waits until there is a game and gets its id
+func (s *Server) joinMatchmaking(w http.ResponseWriter, r *http.Request) {
+	var user string
+	var isBot bool
+	mapstructure.Decode(r.Context().Value(ContextKey("id")), &user)
+	mapstructure.Decode(r.Context().Value(ContextKey("bot")), &isBot)
+	if user == "" || isBot {
+		WriteJson(w, http.StatusUnauthorized, map[string]string{"error": "Only users can play ranked games"})
+		return
+	}
+
+	var data map[string]interface{}
+	var mode entities.GameMode
+	var players int
+	var region string
+	if err := json.NewDecoder(r.Body).Decode(&data); err == nil {
+		mapstructure.Decode(data["mode"], &mode)
+		mapstructure.Decode(data["players"], &players)
+		mapstructure.Decode(data["region"], &region)
+	}
+	if !mode.IsValid() || players < 2 || players > 6 {
+		WriteJson(w, http.StatusBadRequest, map[string]string{"error": "Invalid mode or number of players"})
+		return
+	}
+
+	region, server := s.getMatchmakingServer(region)
+	if server != "" {
+		WriteJson(w, http.StatusMisdirectedRequest, map[string]string{
+			"error":  "Queue on the server of the region",
+			"server": server,
+		})
+		return
+	}
+
+	ratings, err := s.store.ReadRatings(user)
+	if err != nil {
+		WriteJson(w, http.StatusNotFound, map[string]string{"error": "User not found"})
+		return
+	}
+
+	t := &matchTicket{
+		UserID:   user,
+		Mode:     mode,
+		Players:  players,
+		Region:   region,
+		Rating:   entities.FindRating(ratings, user, mode, players).Rating,
+		QueuedAt: time.Now(),
+		game:     make(chan string, 1),
+	}
+	if err := s.matchmaker.Add(t); err != nil {
+		WriteJson(w, http.StatusConflict, map[string]string{"error": "Already in the queue"})
+		return
+	}
+	go s.matchPlayers()
+
+	select {
+	case id := <-t.game:
+		if id == "" {
+			WriteJson(w, http.StatusInternalServerError, map[string]string{"error": "Could not create game"})
+			return
+		}
+		WriteJson(w, http.StatusOK, map[string]string{"id": id})
+	case <-r.Context().Done():
+		s.matchmaker.Remove(t)
+	}
+}
+
+// Region to queue in and the server that keeps its queue, empty for
+// this server. The queue of a region is on the first of its servers
+// by url. Regions without servers queue in the region of this server.
+func (s *Server) getMatchmakingServer(region string) (string, string) {
+	own := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = own
+	}
+
+	regions, err := s.registry.GetServerRegions()
+	if err != nil {
+		log.Println("error reading servers: ", err)
+		return region, ""
+	}
+
+	servers := getServersInRegion(regions, region)
+	if len(servers) == 0 {
+		region = own
+		servers = getServersInRegion(regions, region)
+	}
+
+	if len(servers) == 0 || servers[0] == os.Getenv("SERVER_URL") {
+		return region, ""
+	}
+	return region, servers[0]
+}
+
+func getServersInRegion(regions map[string]string, region string) []string {
+	servers := make([]string, 0)
+	for url, r := range regions {
+		if r == region {
+			servers = append(servers, url)
+		}
+	}
+	sort.Strings(servers)
+	return servers
+}
+
+func (s *Server) runMatchmaker() {
+	ticker := time.NewTicker(time.Second)
+	for range ticker.C {
+		s.matchPlayers()
+	}
+}
+
+func (s *Server) matchPlayers() {
+	for _, group := range s.matchmaker.takeMatches(time.Now()) {
+		id, err := s.createMatch(group)
+		if err != nil {
+			log.Println("error creating match: ", err)
+		}
+		for _, t := range group.Tickets {
+			t.game <- id
+		}
+	}
+}
+
+// Create the lobby of a match with its settings locked
+func (s *Server) createMatch(group *matchGroup) (string, error) {
+	id, err := s.newGameID()
+	if err != nil {
+		return "", err
+	}
+
+	hub := s.NewWsHub(id)
+	if hub == nil {
+		return "", errors.New("could not create hub")
+	}
+
+	first := group.Tickets[0]
+	users := make([]string, len(group.Tickets))
+	for i, t := range group.Tickets {
+		users[i] = t.UserID
+	}
+
+	hub.Mutex.Lock()
+	hub.Match = &Match{Users: users}
+	hub.Game.Settings.Mode = first.Mode
+	hub.Game.Settings.MaxPlayers = first.Players
+	hub.Game.Settings.SpecialBuild = first.Players > 4
+	hub.Game.Settings.Private = true
+	hub.Game.Settings.EnableKarma = true
+	hub.Game.Settings.Matched = true
+	hub.Game.Settings.Ranked = group.Ranked
+	if first.Mode == entities.CitiesAndKnights {
+		hub.Game.Settings.VictoryPoints = 13
+	}
+	hub.Mutex.Unlock()
+
+	hub.StoreSettings()
+	time.AfterFunc(MATCHMAKING_JOIN_SEC*time.Second, hub.startMatch)
+	return id, nil
+}
+
+func (m *Match) HasUser(id string) bool {
+	for _, u := range m.Users {
+		if u == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Whether every matched user is in the lobby
+func (h *WsHub) isMatchComplete() bool {
+	for _, id := range h.Match.Users {
+		found := false
+		h.Clients.Range(func(key, value interface{}) bool {
+			if key.(*WsClient).Player.Id == id {
+				found = true
+				return false
+			}
+			return true
+		})
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Start the game of a match, bots take the seats of the players
+// that did not join. Nothing starts if nobody joined.
+func (h *WsHub) startMatch() {
+	h.Mutex.Lock()
+	defer h.Mutex.Unlock()
+
+	if h.terminating || h.Game.Initialized || atomic.LoadInt32(&h.NumClients) == 0 {
+		return
+	}
+
+	for n := atomic.LoadInt32(&h.NumClients); n < int32(h.Game.Settings.MaxPlayers); n++ {
+		if err := h.StartBot("", ""); err != nil {
+			log.Println(h.Game.ID, err)
+			break
+		}
+	}
+
+	numPlayers := atomic.LoadInt32(&h.NumClients)
+	h.Game.Store.WriteGamePlayers(h.Game.ID, numPlayers)
+	startGame(h.Game.ID, numPlayers, h)
+}
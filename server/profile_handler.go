@@ -0,0 +1,109 @@
+package server
+
+import (
+	"imperials/entities"
+	"imperials/game"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/mitchellh/mapstructure"
+)
+
+const (
+	// Latest games of a user counted in the profile
+	profileGamesLimit = 500
+
+	leaderboardLimit    = 50
+	leaderboardMaxLimit = 100
+)
+
+// Games played and won, records by mode and map, openings,
+// ratings and recent games of a user
+func (s *Server) getProfile(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	user, err := s.store.ReadUser(id)
+	if err != nil || user == nil {
+		WriteJson(w, http.StatusNotFound, map[string]string{"error": "User not found"})
+		return
+	}
+
+	var username string
+	mapstructure.Decode(user["username"], &username)
+
+	games, err := s.store.ReadUserGames(id, profileGamesLimit)
+	if err != nil {
+		WriteJson(w, http.StatusNotFound, map[string]string{"error": "User not found"})
+		return
+	}
+
+	ratings, err := s.store.ReadRatings(id)
+	if err != nil {
+		WriteJson(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	WriteJson(w, http.StatusOK, game.BuildProfile(id, username, games, ratings))
+}
+
+// Best rated users, of every mode or of one mode and number of players
+func (s *Server) getLeaderboard(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var mode entities.GameMode
+	if v := query.Get("mode"); v != "" {
+		m, err := strconv.Atoi(v)
+		if err != nil || !entities.GameMode(m).IsValid() {
+			WriteJson(w, http.StatusBadRequest, map[string]string{"error": "Invalid mode"})
+			return
+		}
+		mode = entities.GameMode(m)
+	}
+
+	players := 0
+	if v := query.Get("players"); v != "" {
+		p, err := strconv.Atoi(v)
+		if err != nil || p < 2 || p > 6 {
+			WriteJson(w, http.StatusBadRequest, map[string]string{"error": "Invalid number of players"})
+			return
+		}
+		players = p
+	}
+
+	limit := leaderboardLimit
+	if v := query.Get("limit"); v != "" {
+		l, err := strconv.Atoi(v)
+		if err != nil || l < 1 || l > leaderboardMaxLimit {
+			WriteJson(w, http.StatusBadRequest, map[string]string{"error": "Invalid limit"})
+			return
+		}
+		limit = l
+	}
+
+	ratings, err := s.store.ReadLeaderboard(mode, players, limit)
+	if err != nil {
+		WriteJson(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	entries := make([]*entities.LeaderboardEntry, len(ratings))
+	for i, rating := range ratings {
+		entries[i] = &entities.LeaderboardEntry{
+			Rank:      i + 1,
+			UserID:    rating.UserID,
+			Mode:      rating.Mode,
+			Players:   rating.Players,
+			Rating:    int(math.Round(rating.Rating)),
+			Deviation: int(math.Round(rating.Deviation)),
+			Games:     rating.Games,
+			Wins:      rating.Wins,
+		}
+		if user, err := s.store.ReadUser(rating.UserID); err == nil && user != nil {
+			mapstructure.Decode(user["username"], &entries[i].Username)
+		}
+	}
+
+	WriteJson(w, http.StatusOK, entries)
+}
@@ -0,0 +1,187 @@
+package server
+
+import (
+	"errors"
+	"imperials/game"
+	"io"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/gorilla/mux"
+	"github.com/mitchellh/mapstructure"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Largest replay file that can be imported
+const maxReplaySize = 16 << 20
+
+// Download a game as a replay file
+// Games in progress can only be exported with DEBUG_REPLAY=1,
+// the journal shows the hands and the order of the decks
+func (s *Server) exportReplay(w http.ResponseWriter, r *http.Request) {
+	user := getReplayUser(r)
+	if user == "" {
+		WriteJson(w, http.StatusUnauthorized, map[string]string{"error": "Only users can export replays"})
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+
+	var replay *game.Replay
+	var host string
+	var err error
+	if hub, ok := s.hubs.Load(id); ok {
+		host = hub.(*WsHub).HostId
+		replay, err = exportRunningReplay(&hub.(*WsHub).Game)
+	} else {
+		replay, err = s.exportStoredReplay(id)
+	}
+	if err != nil {
+		WriteJson(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		return
+	}
+
+	// Private games are not found by others
+	if !replay.CanExport(user, host) {
+		WriteJson(w, http.StatusNotFound, map[string]string{"error": "Game not found"})
+		return
+	}
+
+	if !replay.GameOver && os.Getenv("DEBUG_REPLAY") != "1" {
+		WriteJson(w, http.StatusForbidden, map[string]string{"error": "Game is not over yet"})
+		return
+	}
+
+	// Older games do not have their map definition
+	if replay.Map == nil && replay.Settings.MapVersion > 0 {
+		if info := s.store.GetMapVersion(replay.Settings.MapName, replay.Settings.MapVersion); info != nil {
+			replay.Map = info.Defn
+		}
+	}
+
+	data, err := replay.Encode()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", `attachment; filename="imperials-`+id+`.replay"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func exportRunningReplay(g *game.Game) (*game.Replay, error) {
+	defer g.Unlock()
+	if !g.Lock() {
+		return nil, errors.New("Game has not started")
+	}
+	return g.ExportReplay()
+}
+
+// Replay of a game that is not loaded on this server
+func (s *Server) exportStoredReplay(id string) (*game.Replay, error) {
+	if exists, err := s.store.CheckIfJournalExists(id); err != nil || !exists {
+		return nil, errors.New("Game not found")
+	}
+
+	numPlayers, err := s.store.ReadGamePlayers(id)
+	if err != nil {
+		return nil, errors.New("Game not found")
+	}
+
+	entries, err := s.store.ReadJournal(id)
+	if err != nil {
+		return nil, errors.New("Game not found")
+	}
+
+	return game.NewReplay(id, uint16(numPlayers), entries)
+}
+
+// Id of the user making the request, empty for bots
+func getReplayUser(r *http.Request) string {
+	var user string
+	var isBot bool
+	mapstructure.Decode(r.Context().Value(ContextKey("id")), &user)
+	mapstructure.Decode(r.Context().Value(ContextKey("bot")), &isBot)
+
+	if isBot {
+		return ""
+	}
+	return user
+}
+
+// Create a new read-only game from a replay file
+func (s *Server) importReplay(w http.ResponseWriter, r *http.Request) {
+	user := getReplayUser(r)
+	if user == "" {
+		WriteJson(w, http.StatusUnauthorized, map[string]string{"error": "Only users can import replays"})
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxReplaySize))
+	if err != nil {
+		WriteJson(w, http.StatusRequestEntityTooLarge, map[string]string{"error": "Replay is too large"})
+		return
+	}
+
+	replay, err := game.DecodeReplay(data)
+	if err != nil {
+		WriteJson(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	gameID, err := s.newGameID()
+	if err != nil {
+		WriteJson(w, http.StatusInternalServerError, map[string]string{"error": "Could not generate game ID"})
+		return
+	}
+
+	entries, err := replay.ImportJournal(gameID)
+	if err != nil {
+		WriteJson(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	if err := s.storeReplay(gameID, replay, entries); err != nil {
+		log.Println(gameID, err)
+		WriteJson(w, http.StatusInternalServerError, map[string]string{"error": "Could not import replay"})
+		return
+	}
+
+	// The hub plays the journal because the game has players
+	if s.NewWsHub(gameID) == nil {
+		WriteJson(w, http.StatusInternalServerError, map[string]string{"error": "Could not import replay"})
+		return
+	}
+
+	WriteJson(w, http.StatusOK, map[string]string{"id": gameID})
+}
+
+func (s *Server) storeReplay(id string, replay *game.Replay, entries [][]byte) error {
+	if err := s.store.CreateGameIfNotExists(id); err != nil {
+		return err
+	}
+	if err := s.store.WriteGameServer(id); err != nil {
+		return err
+	}
+
+	// Imported games are not listed in the lobby
+	if err := s.store.WriteGamePrivacy(id, true); err != nil {
+		return err
+	}
+
+	settings, err := msgpack.Marshal(replay.Settings)
+	if err != nil {
+		return err
+	}
+	if err := s.store.WriteGameSettings(id, settings); err != nil {
+		return err
+	}
+
+	if err := s.store.WriteJournalEntries(id, entries); err != nil {
+		return err
+	}
+	return s.store.WriteGamePlayers(id, int32(replay.NumPlayers))
+}
@@ -2,8 +2,13 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"imperials/boltstore"
+	"imperials/game"
 	"imperials/mango"
+	"imperials/memstore"
+	"imperials/render"
 	"log"
 	"net/http"
 	"os"
@@ -30,10 +35,13 @@ type (
 		CheckIfUserEmailExists(string) (map[string]interface{}, error)
 		UpdateUsername(string, string) error
 		UpdateEmail(string, string) error
+		GetServerRegions() (map[string]string, error)
 	}
 	Server struct {
-		hubs     sync.Map
-		registry Registry
+		hubs       sync.Map
+		registry   Registry
+		store      game.Store
+		matchmaker Matchmaker
 	}
 
 	GameResponse struct {
@@ -42,9 +50,38 @@ type (
 	}
 )
 
+const (
+	StoreMongo  = "mongo"
+	StoreMemory = "memory"
+	StoreBolt   = "bolt"
+)
+
 func NewServer() *Server {
 	server := &Server{}
-	server.registry = &mango.MangoRegistry{}
+
+	// Select the persistence backend
+	switch os.Getenv("STORE") {
+	case StoreMemory:
+		log.Println("Using in-memory store, nothing will be persisted")
+		store := memstore.NewMemStore()
+		server.store = store
+		server.registry = &memstore.MemRegistry{Store: store}
+	case StoreBolt:
+		path := os.Getenv("BOLT_PATH")
+		if path == "" {
+			path = "imperials.db"
+		}
+		store, err := boltstore.Open(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		server.store = store
+		server.registry = &boltstore.BoltRegistry{Store: store}
+	default:
+		server.store = &mango.MangoStore{}
+		server.registry = &mango.MangoRegistry{}
+	}
+
 	server.registry.Init()
 	return server
 }
@@ -54,10 +91,24 @@ func (s *Server) Run() {
 
 	r.HandleFunc("/heartbeat", s.handleHeartbeat).Methods("GET")
 	r.HandleFunc("/socket", s.socketHandler)
+	r.HandleFunc("/bot", s.botSocketHandler)
+	r.HandleFunc("/bots", s.createBotToken).Methods("POST")
 	r.HandleFunc("/games", s.handleGame).Methods("GET", "POST")
+	r.HandleFunc("/games/{id}/board", s.getBoard).Methods("GET")
+	r.HandleFunc("/games/{id}/replay", s.exportReplay).Methods("GET")
+	r.HandleFunc("/replays", s.importReplay).Methods("POST")
+	r.HandleFunc("/matchmaking", s.joinMatchmaking).Methods("POST")
+	r.HandleFunc("/leaderboards", s.getLeaderboard).Methods("GET")
+	r.HandleFunc("/users/{id}/profile", s.getProfile).Methods("GET")
 	r.HandleFunc("/anon", s.getAnonymousJWT).Methods("GET", "POST")
 	r.HandleFunc("/verify", s.verifyUser).Methods("GET")
 	r.HandleFunc("/register", s.registerUser).Methods("POST")
+	r.HandleFunc("/maps", s.listMaps).Methods("GET")
+	r.HandleFunc("/maps", s.createMap).Methods("POST")
+	r.HandleFunc("/maps/{name}", s.getMap).Methods("GET")
+	r.HandleFunc("/maps/{name}", s.updateMap).Methods("PUT")
+	r.HandleFunc("/maps/{name}", s.deleteMap).Methods("DELETE")
+	r.HandleFunc("/maps/{name}/versions", s.getMapVersions).Methods("GET")
 
 	http.Handle("/", r)
 
@@ -76,6 +127,8 @@ func (s *Server) Run() {
 
 	n.UseHandler(r)
 
+	go s.runMatchmaker()
+
 	address := fmt.Sprintf("%s:%s", os.Getenv("HOST"), os.Getenv("PORT"))
 	log.Println("Starting the Imperial backend on", address)
 	http.ListenAndServe(address, n)
@@ -120,6 +173,60 @@ func (s *Server) createGame(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Random id of a game that is neither loaded nor stored
+func (s *Server) newGameID() (string, error) {
+	for i := 0; i < 10; i++ {
+		id, err := GenerateRandomString(4)
+		if err != nil {
+			return "", err
+		}
+
+		if _, ok := s.hubs.Load(id); ok {
+			continue
+		}
+		// Stores fail to look up games that do not exist
+		if exists, _ := s.store.CheckIfJournalExists(id); !exists {
+			return id, nil
+		}
+	}
+	return "", errors.New("no free game id")
+}
+
+// Drawing of the board of a running game for debugging
+// Use ?format=text for the text form, the default is SVG
+func (s *Server) getBoard(w http.ResponseWriter, r *http.Request) {
+	// The drawing shows the tiles under the fog,
+	// only servers started with DEBUG_BOARD=1 draw it
+	if os.Getenv("DEBUG_BOARD") != "1" {
+		WriteJson(w, http.StatusNotFound, map[string]string{"error": "Not found"})
+		return
+	}
+
+	hub, ok := s.hubs.Load(mux.Vars(r)["id"])
+	if !ok {
+		WriteJson(w, http.StatusNotFound, map[string]string{"error": "Game not found"})
+		return
+	}
+
+	g := &hub.(*WsHub).Game
+	defer g.Unlock()
+	if !g.Lock() {
+		WriteJson(w, http.StatusConflict, map[string]string{"error": "Game has not started"})
+		return
+	}
+
+	if r.URL.Query().Get("format") == "text" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(render.ASCII(g)))
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.WriteHeader(http.StatusOK)
+	w.Write(render.SVG(g))
+}
+
 func (s *Server) registerUser(w http.ResponseWriter, r *http.Request) {
 	var claims jwt.MapClaims
 	mapstructure.Decode(r.Context().Value(ContextKey("claims")), &claims)
@@ -250,7 +357,7 @@ func (s *Server) socketHandler(w http.ResponseWriter, r *http.Request) {
 	gameId := queryParams.Get("id")
 
 	if hub, ok := s.hubs.Load(gameId); ok {
-		StartWs(hub.(*WsHub), w, r)
+		StartWs(hub.(*WsHub), w, r, false)
 	} else {
 		RejectWs(w, r, http.StatusNotFound, "E738: Game not found. Try refresing this page.")
 	}
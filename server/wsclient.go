@@ -60,8 +60,14 @@ type WsClient struct {
 	GamesStarted  int32
 	GamesFinished int32
 
+	// Ratings of the user in every mode, nil for bots
+	Ratings []*entities.Rating
+
 	// Chat Toggle
 	ChatEnabled bool
+
+	// Bot connected through the bot endpoint
+	External bool
 }
 
 // ReadPump pumps messages from the websocket connection to the hub.
@@ -168,7 +174,9 @@ func RejectWs(w http.ResponseWriter, r *http.Request, status int, data string) {
 	conn.Close()
 }
 
-func StartWs(hub *WsHub, w http.ResponseWriter, r *http.Request) {
+// external is true for connections to the bot endpoint,
+// which only accepts bot tokens while /socket rejects them
+func StartWs(hub *WsHub, w http.ResponseWriter, r *http.Request, external bool) {
 	hub.Mutex.Lock()
 	defer hub.Mutex.Unlock()
 
@@ -183,11 +191,23 @@ func StartWs(hub *WsHub, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var isBot bool
+	mapstructure.Decode(r.Context().Value(ContextKey("bot")), &isBot)
+	if isBot != external {
+		RejectWs(w, r, 403, "E747: Bots must connect to the bot endpoint")
+		return
+	}
+
 	if _, found := hub.BannedUsers.Load(username); found {
 		RejectWs(w, r, 403, "E745: The host has banned you from this game")
 		return
 	}
 
+	if hub.Match != nil && !hub.Game.Initialized && !hub.Match.HasUser(id) {
+		RejectWs(w, r, 403, "E748: This game is reserved for the players of a match")
+		return
+	}
+
 	playerNumber := hub.DisconnectOtherClients(username, "You have connected from another device or browser tab.")
 	if !hub.Game.Initialized &&
 		(playerNumber < 0 ||
@@ -209,12 +229,22 @@ func StartWs(hub *WsHub, w http.ResponseWriter, r *http.Request) {
 	mapstructure.Decode(userDetails["started"], &gamesStarted)
 	mapstructure.Decode(userDetails["finished"], &gamesFinished)
 
+	var ratings []*entities.Rating
+	if !external {
+		ratings, err = hub.Game.Store.ReadRatings(id)
+		if err != nil {
+			log.Println("error reading ratings for client: ", err)
+		}
+	}
+
 	client := &WsClient{
 		Hub:           hub,
 		Disconnect:    make(chan bool),
 		GamesStarted:  gamesStarted,
 		GamesFinished: gamesFinished,
-		ChatEnabled:   true,
+		Ratings:       ratings,
+		ChatEnabled:   !external,
+		External:      external,
 	}
 	player, err := entities.NewPlayer(
 		entities.Base,
@@ -251,6 +281,11 @@ func StartWs(hub *WsHub, w http.ResponseWriter, r *http.Request) {
 	client.Conn = conn
 	client.Hub.Register(client)
 
+	// Matches start as soon as everyone is in the lobby
+	if hub.Match != nil && !hub.Game.Initialized && hub.isMatchComplete() {
+		go hub.startMatch()
+	}
+
 	// Allow collection of memory referenced by the caller by doing all work in
 	// new goroutines.
 	go client.WritePump()
@@ -3,7 +3,6 @@ package server
 import (
 	"imperials/entities"
 	"imperials/game"
-	"imperials/mango"
 	"imperials/maps"
 	"log"
 	"sync"
@@ -42,6 +41,21 @@ type WsHub struct {
 
 	// List of banned users
 	BannedUsers sync.Map
+
+	// Id of the lobby host, who approves undo once the game started
+	HostId string
+
+	// Pending request to undo an action, protected by the game mutex
+	undoRequest *UndoRequest
+
+	// Set for games made by the matchmaking queue
+	Match *Match
+}
+
+// Request of the current player to take back their last action
+type UndoRequest struct {
+	Order        uint16
+	JournalIndex int
 }
 
 func (s *Server) NewWsHub(id string) *WsHub {
@@ -51,7 +65,7 @@ func (s *Server) NewWsHub(id string) *WsHub {
 		Game: game.Game{
 			ID:          id,
 			Initialized: false,
-			Store:       &mango.MangoStore{},
+			Store:       s.store,
 			Settings: entities.GameSettings{
 				Mode:          entities.Base,
 				MapName:       "Base",
@@ -115,6 +129,7 @@ func (h *WsHub) Tick(tickerPeriod int) bool {
 			val := atomic.AddInt32(&p.InactiveSeconds, int32(tickerPeriod))
 			if val > MAX_INACTIVE_PLAYER_SEC && !p.GetIsBot() {
 				p.SetIsBot(true)
+				h.Game.SetAbandoned(p)
 				if p.IsSpectator {
 					h.Game.RemoveSpectator(p)
 				}
@@ -215,6 +230,14 @@ func (h *WsHub) Unregister(client *WsClient) {
 		h.Game.Lock()
 		h.Game.RemoveSpectator(client.Player)
 		h.Game.Unlock()
+	} else if client.External && h.Game.Initialized {
+		// Play for the bot until it reconnects
+		client.Player.SetIsBot(true)
+	} else if h.Game.Initialized && !client.Player.GetIsBot() {
+		id := client.Player.Id
+		time.AfterFunc(MAX_INACTIVE_PLAYER_SEC*time.Second, func() {
+			h.markAbandoned(id)
+		})
 	}
 
 	atomic.AddInt32(&h.NumClients, -1)
@@ -250,6 +273,36 @@ func (h *WsHub) Unregister(client *WsClient) {
 	}
 }
 
+// Mark a player that left the game and did not come back,
+// the game then does not count for ratings
+func (h *WsHub) markAbandoned(id string) {
+	if h.terminating {
+		return
+	}
+
+	connected := false
+	h.Clients.Range(func(key, value interface{}) bool {
+		if key.(*WsClient).Player.Id == id {
+			connected = true
+			return false
+		}
+		return true
+	})
+	if connected {
+		return
+	}
+
+	defer h.Game.Unlock()
+	if !h.Game.Lock() || h.Game.GameOver || h.Game.ReadOnly {
+		return
+	}
+	for _, p := range h.Game.Players {
+		if p.Id == id {
+			h.Game.SetAbandoned(p)
+		}
+	}
+}
+
 // Get username of player with order 0
 // Returns blank string if game is initialized
 func (h *WsHub) GetHostUsername() string {
@@ -285,16 +338,38 @@ func (h *WsHub) StoreSettings() {
 	h.Mutex.Lock()
 	defer h.Mutex.Unlock()
 
+	changed := false
+	if h.Game.Settings.MapName == maps.GeneratedMapName {
+		defn := h.Game.Settings.MapDefn
+		if defn == nil || defn.Name != maps.GeneratedMapName || !maps.FitsPlayers(defn, h.Game.Settings.MaxPlayers) {
+			h.Game.Settings.MapDefn = maps.GenerateMap(h.Game.Settings.MaxPlayers, entities.NewRand(entities.NewSeed()))
+			h.Game.Settings.MapVersion = 0
+			changed = true
+		}
+	}
+
 	if h.Game.Settings.MapDefn == nil || h.Game.Settings.MapDefn.Name != h.Game.Settings.MapName {
-		defn := h.Game.Store.GetMap(h.Game.Settings.MapName)
+		info := h.Game.Store.GetMapInfo(h.Game.Settings.MapName)
 
 		// Get base map as default
-		if defn == nil {
+		if info == nil || info.Defn == nil {
 			h.Game.Settings.MapName = "Base"
-			defn = maps.GetBaseMap()
-			h.BroadcastLobbyMessage(h.GetLobbySettingsMessage())
+			info = &entities.MapInfo{Defn: maps.GetBaseMap()}
+			changed = true
 		}
-		h.Game.Settings.MapDefn = defn
+		h.Game.Settings.MapDefn = info.Defn
+		if info.Version != h.Game.Settings.MapVersion {
+			h.Game.Settings.MapVersion = info.Version
+			changed = true
+		}
+	}
+
+	if balance := maps.GetMapBalance(h.Game.Settings.MapDefn); balance != h.Game.Settings.MapBalance {
+		h.Game.Settings.MapBalance = balance
+		changed = true
+	}
+	if changed {
+		h.BroadcastLobbyMessage(h.GetLobbySettingsMessage())
 	}
 
 	h.Game.Store.WriteGamePrivacy(h.Game.ID, h.Game.Settings.Private)
@@ -309,3 +384,42 @@ func (h *WsHub) StoreSettings() {
 		}
 	}
 }
+
+// The host approves undo, the first seat if the game was restored
+func (h *WsHub) isHost(p *entities.Player) bool {
+	if h.HostId == "" {
+		return p.Order == 0
+	}
+	return p.Id == h.HostId
+}
+
+// Undo the last action of the player
+// Game mutex must be locked
+func (h *WsHub) undo(p *entities.Player) error {
+	if err := h.Game.Undo(p); err != nil {
+		return err
+	}
+	h.undoRequest = nil
+
+	h.BroadcastChat(p, "Undid the last action of "+p.Username)
+	return nil
+}
+
+// Send a message from the server to the chat of the running game
+func (h *WsHub) BroadcastChat(p *entities.Player, text string) {
+	msg := &entities.Message{
+		Type: entities.MessageTypeChat,
+		Data: map[string]string{
+			"color": p.Color,
+			"text":  text,
+		},
+	}
+
+	h.Clients.Range(func(key, value interface{}) bool {
+		client := key.(*WsClient)
+		if client.ChatEnabled {
+			client.Player.SendMessage(msg)
+		}
+		return true
+	})
+}
@@ -16,7 +16,12 @@ import {
     SOCKET_STATE,
     WsMessage,
 } from "../src/sock";
-import { lobbyReducer, getInitialLobbyState, GAME_MODE } from "../src/lobby";
+import {
+    lobbyReducer,
+    getInitialLobbyState,
+    GAME_MODE,
+    GENERATED_MAP_NAME,
+} from "../src/lobby";
 import Error from "next/error";
 import Pixi from "./pixi";
 import PlayerList from "./playerList";
@@ -25,7 +30,12 @@ import { useGameServer } from "../hooks/gameServer";
 import { white as spinner } from "./spinner";
 import { useRouter } from "next/router";
 import Header from "./header";
-import { IAdvancedSettings, IGameSettings } from "../tsg";
+import {
+    BuildingCosts,
+    IAdvancedSettings,
+    IBuildingCosts,
+    IGameSettings,
+} from "../tsg";
 import { capitalizeFirstLetter, toggleFullscreen } from "../utils";
 import { Combobox, Transition } from "@headlessui/react";
 import { CheckIcon, ChevronUpDownIcon } from "@heroicons/react/24/solid";
@@ -131,6 +141,16 @@ const Game: FunctionComponent<{ gameId: string }> = ({ gameId }) => {
         }
     };
 
+    const rerollMap = () => {
+        if (socket.current != null) {
+            const msg: WsMessage = {
+                l: MSG_LOCATION_TYPE.LOBBY,
+                t: MSG_TYPE.REROLL_MAP,
+            };
+            sendMessage(socket.current, msg);
+        }
+    };
+
     const sendAdvancedSettings = () => {
         if (socket.current != null) {
             const msg: WsMessage = {
@@ -142,11 +162,14 @@ const Game: FunctionComponent<{ gameId: string }> = ({ gameId }) => {
         }
     };
 
+    const [botDifficulty, setBotDifficulty] = useState("medium");
+
     const botAdd = () => {
         if (socket.current != null) {
             const msg: WsMessage = {
                 l: MSG_LOCATION_TYPE.LOBBY,
                 t: MSG_TYPE.BOT_ADD,
+                difficulty: botDifficulty,
             };
             sendMessage(socket.current, msg);
         }
@@ -335,6 +358,86 @@ const Game: FunctionComponent<{ gameId: string }> = ({ gameId }) => {
         );
     }
 
+    function getAdvancedSelect(
+        text: string,
+        setting: keyof IAdvancedSettings,
+        values: number[],
+    ) {
+        const changeVal: ChangeEventHandler<HTMLSelectElement> = (event) => {
+            lobbyState.advanced[setting] = Number(event.target.value) as never;
+            sendAdvancedSettings();
+        };
+
+        return (
+            <div className="p-1 basis-full lg:basis-1/3">
+                <label
+                    className="block text-white text-lg mb-1"
+                    htmlFor={setting}
+                >
+                    {text}
+                </label>
+                <select
+                    className={selectClasses}
+                    aria-label={text}
+                    id={setting}
+                    onChange={changeVal}
+                    disabled={lobbyState.order !== 0}
+                    value={(lobbyState.advanced[setting] as number) || 0}
+                >
+                    <option value={0}>Standard</option>
+                    {values.map((n: number) => (
+                        <option key={n} value={n}>
+                            {n}
+                        </option>
+                    ))}
+                </select>
+            </div>
+        );
+    }
+
+    function getAdvancedCost(text: string, cost: keyof IBuildingCosts) {
+        const current = lobbyState.advanced.Costs?.[cost];
+
+        // Wood, brick, wool, wheat and ore separated by commas
+        const changeVal: ChangeEventHandler<HTMLInputElement> = (event) => {
+            const vals = event.target.value
+                .split(",")
+                .map((v) => Number(v.trim()));
+            if (
+                event.target.value.trim() !== "" &&
+                (vals.length !== 5 || vals.some((v) => isNaN(v)))
+            ) {
+                return;
+            }
+
+            lobbyState.advanced.Costs = new BuildingCosts({
+                ...lobbyState.advanced.Costs,
+                [cost]: event.target.value.trim() === "" ? [] : vals,
+            });
+            sendAdvancedSettings();
+        };
+
+        return (
+            <div className="p-1 basis-full lg:basis-1/3">
+                <label
+                    className="block text-white text-lg mb-1"
+                    htmlFor={"cost" + cost}
+                >
+                    {text}
+                </label>
+                <input
+                    className={selectClasses}
+                    aria-label={text}
+                    id={"cost" + cost}
+                    placeholder="Standard"
+                    disabled={lobbyState.order !== 0}
+                    defaultValue={current?.length ? current.join(",") : ""}
+                    onBlur={changeVal as any}
+                />
+            </div>
+        );
+    }
+
     return (
         <>
             <Header />
@@ -390,6 +493,40 @@ const Game: FunctionComponent<{ gameId: string }> = ({ gameId }) => {
                                                 >
                                                     Wonders &amp; Warriors
                                                 </option>
+                                                <option
+                                                    value={GAME_MODE.Seafarers}
+                                                >
+                                                    Seas &amp; Islands
+                                                </option>
+                                                <option
+                                                    value={GAME_MODE.Fishermen}
+                                                >
+                                                    Fishermen
+                                                </option>
+                                                <option
+                                                    value={GAME_MODE.Rivers}
+                                                >
+                                                    Rivers
+                                                </option>
+                                                <option
+                                                    value={GAME_MODE.Caravans}
+                                                >
+                                                    Caravans
+                                                </option>
+                                                <option
+                                                    value={
+                                                        GAME_MODE.BarbarianAttack
+                                                    }
+                                                >
+                                                    Barbarian Attack
+                                                </option>
+                                                <option
+                                                    value={
+                                                        GAME_MODE.TradersAndBarbarians
+                                                    }
+                                                >
+                                                    Traders &amp; Barbarians
+                                                </option>
                                             </select>
                                         </div>
                                     </div>
@@ -519,6 +656,29 @@ const Game: FunctionComponent<{ gameId: string }> = ({ gameId }) => {
                                                 </div>
                                             </Combobox>
                                         </div>
+                                        {lobbyState.settings.MapBalance > 0 && (
+                                            <div className="flex items-center justify-between mt-1 text-white">
+                                                <span>
+                                                    Balance{" "}
+                                                    {
+                                                        lobbyState.settings
+                                                            .MapBalance
+                                                    }
+                                                    /100
+                                                </span>
+                                                {lobbyState.order == 0 &&
+                                                    lobbyState.settings
+                                                        .MapName ==
+                                                        GENERATED_MAP_NAME && (
+                                                        <button
+                                                            className="px-3 rounded-md bg-indigo-700 hover:bg-indigo-800"
+                                                            onClick={rerollMap}
+                                                        >
+                                                            Re-roll
+                                                        </button>
+                                                    )}
+                                            </div>
+                                        )}
                                     </div>
                                 </div>
                             </div>
@@ -682,6 +842,93 @@ const Game: FunctionComponent<{ gameId: string }> = ({ gameId }) => {
                                             "Re-roll on 7",
                                             "RerollOn7",
                                         )}
+                                        {getAdvancedCheckBox(
+                                            "No 7 in the first round",
+                                            "NoSevensFirstRound",
+                                        )}
+                                        {getAdvancedCheckBox(
+                                            "Open hands",
+                                            "OpenHands",
+                                        )}
+                                    </div>
+                                    <div className="flex flex-col lg:flex-row md:mt-2">
+                                        {getAdvancedCheckBox(
+                                            "Balanced dice",
+                                            "BalancedDice",
+                                        )}
+                                        {getAdvancedCheckBox(
+                                            "Fewer repeated rolls",
+                                            "BalancedDiceDampenRepeats",
+                                        )}
+                                        {getAdvancedCheckBox(
+                                            "Fewer 7s for the leader",
+                                            "BalancedDiceLeaderPenalty",
+                                        )}
+                                    </div>
+                                    <div className="flex flex-col lg:flex-row md:mt-2">
+                                        {getAdvancedCheckBox(
+                                            "Event cards",
+                                            "EventCards",
+                                        )}
+                                        {getAdvancedCheckBox(
+                                            "Events for everyone",
+                                            "EventCardEvents",
+                                        )}
+                                    </div>
+                                    <div className="flex flex-col lg:flex-row md:mt-2">
+                                        {getAdvancedSelect(
+                                            "Dice rolls before shuffling",
+                                            "BalancedDiceReshuffle",
+                                            [12, 18, 24, 30, 36],
+                                        )}
+                                    </div>
+                                    <div className="flex flex-col lg:flex-row md:mt-2">
+                                        {getAdvancedSelect(
+                                            "Friendly robber (points)",
+                                            "FriendlyRobber",
+                                            [2, 3, 4, 5],
+                                        )}
+                                        {getAdvancedSelect(
+                                            "Action cards per turn",
+                                            "MaxDevelopmentCardsPerTurn",
+                                            [1, 2, 3],
+                                        )}
+                                    </div>
+                                    <div className="flex flex-col lg:flex-row md:mt-2">
+                                        {getAdvancedSelect(
+                                            "Longest road length",
+                                            "LongestRoadLength",
+                                            [4, 5, 6, 7, 8],
+                                        )}
+                                        {getAdvancedSelect(
+                                            "Longest road points",
+                                            "LongestRoadVP",
+                                            [1, 2, 3],
+                                        )}
+                                    </div>
+                                    <div className="flex flex-col lg:flex-row md:mt-2">
+                                        {getAdvancedSelect(
+                                            "Largest army size",
+                                            "LargestArmySize",
+                                            [2, 3, 4, 5],
+                                        )}
+                                        {getAdvancedSelect(
+                                            "Largest army points",
+                                            "LargestArmyVP",
+                                            [1, 2, 3],
+                                        )}
+                                    </div>
+                                    <div className="flex flex-col lg:flex-row md:mt-2">
+                                        {getAdvancedCost("Road cost", "Road")}
+                                        {getAdvancedCost(
+                                            "Settlement cost",
+                                            "Settlement",
+                                        )}
+                                        {getAdvancedCost("City cost", "City")}
+                                        {getAdvancedCost(
+                                            "Action card cost",
+                                            "DevelopmentCard",
+                                        )}
                                     </div>
                                 </>
                             )}
@@ -761,6 +1008,24 @@ const Game: FunctionComponent<{ gameId: string }> = ({ gameId }) => {
                             >
                                 Add Bot
                             </button>
+                            {lobbyState.order == 0 && (
+                                <select
+                                    className="h-11 ml-2 rounded-xl text-black"
+                                    value={botDifficulty}
+                                    onChange={(e) =>
+                                        setBotDifficulty(e.target.value)
+                                    }
+                                >
+                                    {(
+                                        lobbyState.settingsOptions
+                                            ?.BotDifficulty || ["medium"]
+                                    ).map((d) => (
+                                        <option key={d} value={d}>
+                                            {capitalizeFirstLetter(d)}
+                                        </option>
+                                    ))}
+                                </select>
+                            )}
                         </div>
                     </div>
                 </div>
@@ -54,6 +54,18 @@ const PlayerList: FunctionComponent<{
         }
     };
 
+    const setBotStrategy = (username: string, strategy: string) => {
+        if (socket.current != null) {
+            const msg: WsMessage = {
+                l: MSG_LOCATION_TYPE.LOBBY,
+                t: MSG_TYPE.BOT_STRATEGY,
+                username: username,
+                strategy: strategy,
+            };
+            sendMessage(socket.current, msg);
+        }
+    };
+
     return (
         <div className="grid">
             {usernameModal.component}
@@ -81,7 +93,37 @@ const PlayerList: FunctionComponent<{
                             <p>{player.Username}</p>
                             <p className="font-normal text-sm">
                                 {player.GamesFinished}/{player.GamesStarted}
+                                {player.Rating !== undefined &&
+                                    ` · ${player.Rating}`}
+                                {player.BotDifficulty &&
+                                    ` · ${player.BotDifficulty}`}
+                                {player.External && " · external bot"}
                             </p>
+                            {player.BotStrategy &&
+                                (lobbyState.order === 0 ? (
+                                    <select
+                                        className="font-normal text-sm text-black rounded-md mt-1"
+                                        value={player.BotStrategy}
+                                        onChange={(e) =>
+                                            setBotStrategy(
+                                                player.Username,
+                                                e.target.value,
+                                            )
+                                        }
+                                    >
+                                        {lobbyState.settingsOptions?.BotStrategy?.map(
+                                            (s) => (
+                                                <option key={s} value={s}>
+                                                    {s}
+                                                </option>
+                                            ),
+                                        )}
+                                    </select>
+                                ) : (
+                                    <p className="font-normal text-sm">
+                                        {player.BotStrategy}
+                                    </p>
+                                ))}
                         </span>
 
                         {player.Order === lobbyState.order ? (
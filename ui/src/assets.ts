@@ -81,6 +81,7 @@ import tileTex4 from "../public/assets/tile-tex/4.jpg";
 import tileTex5 from "../public/assets/tile-tex/5.jpg";
 import tileTex21 from "../public/assets/tile-tex/21.jpg";
 import tileTexFog from "../public/assets/tile-tex/fog.jpg";
+import tileTexSea from "../public/assets/sea.jpg";
 
 export enum TILE_TEX {
     FOG = 114,
@@ -92,6 +93,8 @@ export const tileTex: { [key: number]: StaticImageData } = {
     3: tileTex3,
     4: tileTex4,
     5: tileTex5,
+    7: tileTexSea,
+    22: tileTexSea,
     21: tileTex21,
 };
 tileTex[TILE_TEX.FOG] = tileTexFog;
@@ -386,6 +389,15 @@ export const buttons: { [key in ButtonType]: StaticImageData } = {
     improve_cloth: btnCityImproveCloth,
     improve_coin: btnCityImproveCoin,
     w: btnWall,
+    ship: btnRoad,
+    ship_move: btnRoad,
+    fish: btnKnightBox,
+    fish_robber: btnKnightRobber,
+    fish_steal: btnKnightRobber,
+    fish_resource: btnCityImprove,
+    fish_road: btnRoad,
+    fish_card: btnDevelopmentCard,
+    gold: btnCityImproveCoin,
     endturn: btnEndTurn,
     specialbuild: btnSpecialBuild,
     edit: btnEdit,
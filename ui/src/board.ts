@@ -6,6 +6,7 @@ import * as windows from "./windows";
 import * as assets from "./assets";
 import {
     CoordStr,
+    EdgePlacementType,
     IBoard,
     IEdgePlacement,
     IVertexPlacement,
@@ -17,6 +18,7 @@ import {
 import { Viewport } from "pixi-viewport";
 import {
     Coordinate,
+    GameState,
     ICoordinate,
     IEdgeCoordinate,
     IPort,
@@ -72,6 +74,7 @@ export function initialize() {
         edges: {},
         ports: new Array<IPort>(),
         robber: undefined,
+        pirate: undefined,
     };
     edgePlacements = {};
     vertexPlacements = {};
@@ -483,14 +486,6 @@ export function renderEdgePlacement(ep: IEdgePlacement, removed = false) {
     const roadContainer: PIXI.Container & anim.Translatable =
         new PIXI.Container();
 
-    // Generate sprite with correct color
-    const roadSprite = new PIXI.Sprite();
-    const color = hexToUrlString(ep.Owner.Color);
-    assets.assignTexture(roadSprite, assets.road[color]);
-    roadSprite.anchor.x = 0.5;
-    roadSprite.anchor.y = 0.5;
-    roadContainer.addChild(roadSprite);
-
     // Center point of vertices
     const fc1 = getDispCoord(ep.Location.C.C1.X, ep.Location.C.C1.Y);
     const fc2 = getDispCoord(ep.Location.C.C2.X, ep.Location.C.C2.Y);
@@ -502,6 +497,19 @@ export function renderEdgePlacement(ep: IEdgePlacement, removed = false) {
     roadContainer.targetY = fc.y;
     roadContainer.y = roadContainer.targetY - 80;
     roadContainer.zIndex = 90;
+
+    // Ships sail on the middle of the edge
+    if (ep.Type == EdgePlacementType.Ship) {
+        roadContainer.addChild(getShipGraphics(ep.Owner.Color));
+        return addEdgePlacement(key, ep, roadContainer);
+    }
+
+    // Generate sprite with correct color
+    const roadSprite = new PIXI.Sprite();
+    const color = hexToUrlString(ep.Owner.Color);
+    assets.assignTexture(roadSprite, assets.road[color]);
+    roadSprite.anchor.x = 0.5;
+    roadSprite.anchor.y = 0.5;
     roadSprite.rotation =
         (((-60 * (1 - ep.Location.Orientation)) % 360) * Math.PI) / 180.0;
 
@@ -516,23 +524,55 @@ export function renderEdgePlacement(ep: IEdgePlacement, removed = false) {
 
     roadContainer.addChild(shadow);
     roadContainer.addChild(roadSprite);
-
-    container.addChild(roadContainer);
     roadContainer.scale.set(25 / roadSprite.texture.width);
 
+    addEdgePlacement(key, ep, roadContainer);
+}
+
+/**
+ * Show a new edge placement and keep track of it
+ * @param key key of the edge
+ * @param ep edge placement
+ * @param c container of the placement
+ */
+function addEdgePlacement(
+    key: string,
+    ep: IEdgePlacement,
+    c: PIXI.Container & anim.Translatable,
+) {
+    container.addChild(c);
+
     if (isInitComplete) {
-        anim.requestTranslationAnimation([roadContainer], 4);
+        anim.requestTranslationAnimation([c], 4);
     } else {
-        roadContainer.y = roadContainer.targetY;
+        c.y = c.targetY!;
     }
 
     // Add to global map
-    ep.container = roadContainer;
+    ep.container = c;
     edgePlacements[key] = ep;
 
     canvas.app.markDirty();
 }
 
+/**
+ * Draw a ship with a sail in the color of the owner
+ * @param color color of the owner
+ */
+function getShipGraphics(color: string) {
+    const ship = new PIXI.Graphics()
+        .lineStyle(1.5, 0x333333)
+        .beginFill(0x8b5a2b)
+        .drawPolygon([-14, 2, 14, 2, 9, 10, -9, 10])
+        .endFill()
+        .beginFill(color)
+        .drawPolygon([-1, 0, -1, -18, -12, 0])
+        .drawPolygon([2, 0, 2, -14, 10, 0])
+        .endFill();
+    ship.y = 4;
+    return ship;
+}
+
 /**
  * Renders ports from IPort object
  * @param port port object
@@ -694,6 +734,19 @@ function renderEdgeHighlightSprite(edge: UIEdge) {
         boardContainer.addChild(b);
         destroyBeforeReinit.push(b);
     }
+
+    // Rivers flow along the edge
+    if (edge.IsRiver) {
+        const s1 = canvas.getScaled(fc1);
+        const s2 = canvas.getScaled(fc2);
+        const river = new PIXI.Graphics()
+            .lineStyle(6, 0x2f7fd0, 0.9)
+            .moveTo(s1.x, s1.y)
+            .lineTo(s2.x, s2.y);
+        river.zIndex = 87;
+        boardContainer.addChild(river);
+        destroyBeforeReinit.push(river);
+    }
 }
 
 /**
@@ -876,6 +929,88 @@ export async function setRobberTile(tile: UITile) {
     canvas.app.markDirty();
 }
 
+/**
+ * Initialize and/or animate the pirate to a different tile
+ * @param tile Tile to move the pirate to
+ */
+export async function setPirateTile(tile?: UITile) {
+    if (!tile) {
+        return;
+    }
+
+    if (!board.pirate) {
+        board.pirate = new PIXI.Sprite();
+        assets.assignTexture(board.pirate, assets.robber);
+        board.pirate.tint = 0x444466;
+        board.pirate.zIndex = 1100;
+        board.pirate.scale.set(70 / board.pirate.width);
+        board.pirate.anchor.x = 0.5;
+        board.pirate.anchor.y = 1;
+        container.addChild(board.pirate);
+        board.pirate.x = 500;
+        board.pirate.y = 500;
+    }
+
+    const fc = canvas.getScaled(getDispCoord(tile.Center));
+    board.pirate.targetX = fc.x;
+    board.pirate.targetY = fc.y + 30;
+    anim.requestTranslationAnimation([board.pirate], 8);
+
+    canvas.app.markDirty();
+}
+
+/**
+ * Show the camels and barbarians of traders & barbarians
+ * @param gs game state
+ */
+export function renderTradersState(gs: GameState) {
+    board.traders?.destroy({ children: true });
+    board.traders = undefined;
+    if (!gs.Camels?.length && !gs.Barbarians?.length) {
+        return;
+    }
+
+    const g = new PIXI.Graphics();
+    g.zIndex = 95;
+
+    // Camels are small brown markers on the edge
+    for (const c of gs.Camels || []) {
+        const fc1 = getDispCoord(c.C1.X, c.C1.Y);
+        const fc2 = getDispCoord(c.C2.X, c.C2.Y);
+        const fc = canvas.getScaled({
+            X: (fc1.X + fc2.X) / 2,
+            Y: (fc1.Y + fc2.Y) / 2,
+        });
+        g.lineStyle(1.5, 0x333333)
+            .beginFill(0xc19a6b)
+            .drawEllipse(fc.x, fc.y, 9, 6)
+            .endFill();
+    }
+
+    // Barbarians are a red token with their count
+    for (const b of gs.Barbarians || []) {
+        const fc = canvas.getScaled(getDispCoord(b.Tile));
+        g.lineStyle(2, 0x333333)
+            .beginFill(0xaa2222)
+            .drawCircle(fc.x + 30, fc.y - 30, 14)
+            .endFill();
+
+        const text = new PIXI.Text(String(b.Count), {
+            fontFamily: "sans-serif",
+            fontSize: 16,
+            fill: 0xffffff,
+        });
+        text.anchor.set(0.5);
+        text.x = fc.x + 30;
+        text.y = fc.y - 30;
+        g.addChild(text);
+    }
+
+    board.traders = g;
+    container.addChild(g);
+    canvas.app.markDirty();
+}
+
 /**
  * Initialize and/or animate the merchant to a different tile
  * @param m merchant from game state
@@ -43,6 +43,17 @@ export let buttons: {
 
     buildWall?: ButtonSprite;
     specialBuild?: ButtonSprite;
+
+    buildShip?: ButtonSprite;
+    moveShip?: ButtonSprite;
+
+    openFishBox?: ButtonSprite;
+    fishBox?: {
+        container: PIXI.Sprite;
+        options: ButtonSprite[];
+    };
+    spendGold?: ButtonSprite;
+    buildWarrior?: ButtonSprite;
 };
 
 export enum ButtonType {
@@ -62,6 +73,15 @@ export enum ButtonType {
     CityImproveCloth = "improve_cloth",
     CityImproveCoin = "improve_coin",
     Wall = "w",
+    Ship = "ship",
+    ShipMove = "ship_move",
+    FishBox = "fish",
+    FishRobber = "fish_robber",
+    FishSteal = "fish_steal",
+    FishResource = "fish_resource",
+    FishRoad = "fish_road",
+    FishCard = "fish_card",
+    Gold = "gold",
     EndTurn = "endturn",
     SpecialBuild = "specialbuild",
     Edit = "edit",
@@ -382,7 +402,7 @@ export function render(commandHub: CommandHub) {
     }
 
     // Buy Development Card
-    if (state.settings.Mode == state.GameMode.Base) {
+    if (state.usesBaseCards(state.settings.Mode)) {
         buttons.buyDevelopmentCard = getButtonSprite(
             ButtonType.DevelopmentCard,
             BUTTON_WIDTH,
@@ -436,12 +456,24 @@ export function render(commandHub: CommandHub) {
         ).setCards([2, 2]);
     }
 
+    // Traders & Barbarians buttons share the second container
+    const tbButtons = [
+        state.hasFish(state.settings.Mode),
+        state.hasRivers(state.settings.Mode),
+        state.hasBarbarianAttack(state.settings.Mode),
+    ].filter((b) => b).length;
+
     // Second container
-    if (state.settings.Mode == state.GameMode.CitiesAndKnights) {
+    if (
+        state.settings.Mode == state.GameMode.CitiesAndKnights ||
+        state.settings.Mode == state.GameMode.Seafarers ||
+        tbButtons > 0
+    ) {
+        const n = Math.max(tbButtons, 2);
         container1 = new PIXI.Container();
         container1.addChild(
             windows.getWindowSprite(
-                BUTTON_X_DELTA * 1 + BUTTON_WIDTH + 2 * BUTTON_Y,
+                BUTTON_X_DELTA * (n - 1) + BUTTON_WIDTH + 2 * BUTTON_Y,
                 C_HEIGHT,
             ),
         );
@@ -722,6 +754,194 @@ export function render(commandHub: CommandHub) {
         }
     }
 
+    // Ships
+    if (state.settings.Mode == state.GameMode.Seafarers) {
+        {
+            // Build ship
+            const b = getButtonSprite(
+                ButtonType.Ship,
+                BUTTON_WIDTH,
+                0,
+                playerColor,
+                rerender,
+            );
+            buttons.buildShip = b;
+            b.interactive = true;
+            b.cursor = "pointer";
+            b.reactDisable = true;
+            b.x = BUTTON_Y;
+            b.y = BUTTON_Y;
+            b.zIndex = 10;
+            b.onClick(rerenderAnd(commandHub.buildShip));
+            container1.addChild(b);
+            const cs = getCountSprite(
+                COUNT_WIDTH,
+                COUNT_HEIGHT,
+                COUNT_FONTSIZE,
+            );
+            buttonCounts[ButtonType.Ship] = cs;
+            cs.sprite.anchor.x = 1;
+            cs.sprite.x = BUTTON_WIDTH;
+            cs.sprite.zIndex = 10;
+            b.sortableChildren = true;
+            b.addChild(cs.sprite);
+            b.tooltip = new windows.TooltipHandler(
+                b,
+                "Build a ship",
+            ).setCards([1, 3]);
+        }
+
+        {
+            // Move ship
+            const b = getButtonSprite(
+                ButtonType.ShipMove,
+                BUTTON_WIDTH,
+                0,
+                playerColor,
+                rerender,
+            );
+            buttons.moveShip = b;
+            b.interactive = true;
+            b.cursor = "pointer";
+            b.reactDisable = true;
+            b.x = BUTTON_Y + BUTTON_X_DELTA;
+            b.y = BUTTON_Y;
+            b.zIndex = 10;
+            b.onClick(rerenderAnd(commandHub.moveShip));
+            container1.addChild(b);
+            b.tooltip = new windows.TooltipHandler(
+                b,
+                "Move the ship at the open end of a shipping route",
+            );
+        }
+    }
+
+    // Next free slot of the second container
+    let tbSlot = 0;
+
+    // Fish
+    if (state.hasFish(state.settings.Mode)) {
+        const b = getButtonSprite(
+            ButtonType.FishBox,
+            BUTTON_WIDTH,
+            0,
+            playerColor,
+            rerender,
+        );
+        buttons.openFishBox = b;
+        b.setEnabled(true);
+        b.x = BUTTON_Y + BUTTON_X_DELTA * tbSlot++;
+        b.y = BUTTON_Y;
+        b.zIndex = 10;
+        b.onClick(() => {
+            if (buttons.fishBox?.container) {
+                buttons.fishBox.container.visible =
+                    !buttons.fishBox.container.visible;
+            }
+            canvas.app.markDirty();
+        });
+        container1.addChild(b);
+        b.tooltip = new windows.TooltipHandler(
+            b,
+            "Trade in fish caught on the lake",
+        );
+
+        const options: [ButtonType, number, string][] = [
+            [ButtonType.FishRobber, 2, "Move the robber back to the lake"],
+            [ButtonType.FishSteal, 3, "Steal a card from a player"],
+            [ButtonType.FishResource, 4, "Take a resource from the bank"],
+            [ButtonType.FishRoad, 5, "Build a free road"],
+            [ButtonType.FishCard, 7, "Take an action card"],
+        ];
+
+        const fbc = windows.getWindowSprite(
+            BUTTON_X_DELTA * (options.length - 1) +
+                BUTTON_WIDTH +
+                2 * BUTTON_Y,
+            C_HEIGHT,
+        );
+        fbc.x = container.x + BUTTON_X_DELTA * 0;
+        fbc.y = container1.y - C_HEIGHT - 10;
+        fbc.zIndex = 1400;
+        fbc.visible = false;
+        canvas.app.stage.addChild(fbc);
+        buttons.fishBox = {
+            container: fbc,
+            options: [],
+        };
+
+        options.forEach(([type, amount, tooltip], i) => {
+            const b = getButtonSprite(
+                type,
+                BUTTON_WIDTH,
+                0,
+                playerColor,
+                rerender,
+            );
+            buttons.fishBox!.options.push(b);
+            b.interactive = true;
+            b.cursor = "pointer";
+            b.reactDisable = true;
+            b.x = BUTTON_Y + BUTTON_X_DELTA * i;
+            b.y = BUTTON_Y;
+            b.zIndex = 10;
+            b.onClick(rerenderAnd(() => commandHub.useFish(amount)));
+            fbc.addChild(b);
+            b.tooltip = new windows.TooltipHandler(
+                b,
+                `${tooltip} (${amount} fish)`,
+            );
+        });
+    }
+
+    // Gold
+    if (state.hasRivers(state.settings.Mode)) {
+        const b = getButtonSprite(
+            ButtonType.Gold,
+            BUTTON_WIDTH,
+            0,
+            playerColor,
+            rerender,
+        );
+        buttons.spendGold = b;
+        b.interactive = true;
+        b.cursor = "pointer";
+        b.reactDisable = true;
+        b.x = BUTTON_Y + BUTTON_X_DELTA * tbSlot++;
+        b.y = BUTTON_Y;
+        b.zIndex = 10;
+        b.onClick(rerenderAnd(commandHub.spendGold));
+        container1.addChild(b);
+        b.tooltip = new windows.TooltipHandler(
+            b,
+            "Buy a resource with 2 gold",
+        );
+    }
+
+    // Warriors
+    if (state.hasBarbarianAttack(state.settings.Mode)) {
+        const b = getButtonSprite(
+            ButtonType.KnightBuild,
+            BUTTON_WIDTH,
+            0,
+            playerColor,
+            rerender,
+        );
+        buttons.buildWarrior = b;
+        b.interactive = true;
+        b.cursor = "pointer";
+        b.reactDisable = true;
+        b.x = BUTTON_Y + BUTTON_X_DELTA * tbSlot++;
+        b.y = BUTTON_Y;
+        b.zIndex = 10;
+        b.onClick(rerenderAnd(commandHub.buildKnight));
+        container1.addChild(b);
+        b.tooltip = new windows.TooltipHandler(
+            b,
+            "Build a warrior to fight the barbarians",
+        ).setCards([3, 5]);
+    }
+
     // End Turn
     {
         buttons.endTurn = getButtonSprite(
@@ -852,6 +1072,14 @@ export function updateButtonsSecretState(state: PlayerSecretState) {
     buttons.openImproveBox?.setEnabled(anyImprove, true);
 
     buttons.buildWall?.setEnabled(state.AllowedActions?.BuildWall);
+    buttons.buildShip?.setEnabled(state.AllowedActions?.BuildShip);
+    buttons.moveShip?.setEnabled(state.AllowedActions?.MoveShip);
+    buttons.fishBox?.options.forEach((b) =>
+        b.setEnabled(state.AllowedActions?.UseFish),
+    );
+    buttons.openFishBox?.setEnabled(state.AllowedActions?.UseFish, true);
+    buttons.spendGold?.setEnabled(state.AllowedActions?.SpendGold);
+    buttons.buildWarrior?.setEnabled(state.AllowedActions?.BuildKnight);
     buttons.specialBuild?.setEnabled(
         Boolean(state.AllowedActions?.SpecialBuild),
     );
@@ -119,6 +119,35 @@ export default class CommandHub {
         });
     };
 
+    public buildShip = () => {
+        this.sendGameMessage({
+            t: socketTypes.MSG_TYPE.BUILD,
+            o: socketTypes.BUILD_OBJECT.SHIP,
+        });
+    };
+
+    public moveShip = () => {
+        this.sendGameMessage({
+            t: socketTypes.MSG_TYPE.BUILD,
+            o: socketTypes.BUILD_OBJECT.MOVE_SHIP,
+        });
+    };
+
+    public useFish = (amount: number) => {
+        this.sendGameMessage({
+            t: socketTypes.MSG_TYPE.BUILD,
+            o: socketTypes.BUILD_OBJECT.FISH,
+            fa: amount,
+        });
+    };
+
+    public spendGold = () => {
+        this.sendGameMessage({
+            t: socketTypes.MSG_TYPE.BUILD,
+            o: socketTypes.BUILD_OBJECT.GOLD,
+        });
+    };
+
     public buildWall = () => {
         this.sendGameMessage({
             t: socketTypes.MSG_TYPE.BUILD,
@@ -11,7 +11,9 @@ export type IBoard = {
     tiles: { [key: string]: UITile };
     ports: IPort[];
     robber?: anim.TranslatableSprite;
+    pirate?: anim.TranslatableSprite;
     merchant?: anim.TranslatableSprite;
+    traders?: Container;
 };
 
 export type UITile = ITile & {
@@ -69,10 +71,11 @@ export enum TileType {
     Ore = 5,
     Desert = 0,
     Fog = 6,
-    // Sea = 7,
+    Sea = 7,
     None = 8,
     Random = 9,
     Gold = 21,
+    Lake = 22,
 }
 
 export enum DevelopmentCardType {
@@ -120,7 +123,8 @@ export enum VertexPlacementType {
 }
 
 export enum EdgePlacementType {
-    Road = 1,
+    Road = 3,
+    Ship = 8,
 }
 
 export enum BuildableType {
@@ -128,4 +132,5 @@ export enum BuildableType {
     City = 2,
     Road = 3,
     Wall = 7,
+    Ship = 8,
 }
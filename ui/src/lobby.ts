@@ -1,17 +1,37 @@
 import { jwtDecode } from "jwt-decode";
-import { IAdvancedSettings, IGameSettings, LobbyPlayerState } from "../tsg";
+import {
+    BuildingCosts,
+    IAdvancedSettings,
+    IGameSettings,
+    LobbyPlayerState,
+} from "../tsg";
 import { MSG_RES_TYPE, WsResponse } from "./sock";
 
 export enum GAME_MODE {
     Base = 1,
     CitiesAndKnights = 2,
+    Seafarers = 3,
+    Fishermen = 4,
+    Rivers = 5,
+    Caravans = 6,
+    BarbarianAttack = 7,
+    TradersAndBarbarians = 8,
 }
 
 export const DISPLAY_GAME_MODE = {
     [GAME_MODE.Base]: "Basic",
     [GAME_MODE.CitiesAndKnights]: "W&W",
+    [GAME_MODE.Seafarers]: "S&I",
+    [GAME_MODE.Fishermen]: "Fish",
+    [GAME_MODE.Rivers]: "Rivers",
+    [GAME_MODE.Caravans]: "Caravans",
+    [GAME_MODE.BarbarianAttack]: "Barbarians",
+    [GAME_MODE.TradersAndBarbarians]: "T&B",
 };
 
+/** Map generated for each game, can be generated again by the host */
+export const GENERATED_MAP_NAME = "Random Map";
+
 export type LobbyState = {
     players: LobbyPlayerState[];
     maxPlayers: number;
@@ -23,6 +43,8 @@ export type LobbyState = {
     advanced: IAdvancedSettings;
     settingsOptions?: {
         MapName: string[];
+        BotStrategy?: string[];
+        BotDifficulty?: string[];
     };
     chatMessages: { id: number; msg: string }[];
 };
@@ -123,9 +145,29 @@ export const getInitialLobbyState = (): LobbyState => ({
         EnableKarma: true,
         Speed: "normal",
         Advanced: false,
+        Seed: 0,
+        MapBalance: 0,
+        MapVersion: 0,
+        Matched: false,
+        Ranked: false,
     },
     advanced: {
         RerollOn7: false,
+        FriendlyRobber: 0,
+        NoSevensFirstRound: false,
+        LongestRoadLength: 0,
+        LongestRoadVP: 0,
+        LargestArmySize: 0,
+        LargestArmyVP: 0,
+        Costs: new BuildingCosts({}),
+        MaxDevelopmentCardsPerTurn: 0,
+        OpenHands: false,
+        BalancedDice: false,
+        BalancedDiceReshuffle: 0,
+        BalancedDiceDampenRepeats: false,
+        BalancedDiceLeaderPenalty: false,
+        EventCards: false,
+        EventCardEvents: false,
     },
     ready: false,
     canStart: false,
@@ -5,6 +5,7 @@ import * as anim from "./animation";
 import * as state from "./state";
 import { sound } from "@pixi/sound";
 import { getCardTexture } from "./hand";
+import { chatMessage } from "./chat";
 
 let devCardSprite:
     | (PIXI.Sprite & { info?: tsg.DevCardUseInfo } & anim.Translatable)
@@ -12,6 +13,12 @@ let devCardSprite:
 
 const CARD_WIDTH = 150;
 
+const EVENT_TEXT: { [event: number]: string } = {
+    1: "Plague! Everyone returns a wool to the bank",
+    2: "Good neighbours! Everyone gives a card to the next player",
+    3: "Trade bonus! Everyone trades 3:1 with the bank this turn",
+};
+
 /**
  * Show a development card while it is being used
  * @param info dev card use info
@@ -87,3 +94,14 @@ function removeCard() {
 
     devCardSprite = undefined;
 }
+
+/**
+ * Announce the event of a drawn event card in the chat
+ * @param info event card info
+ */
+export function showEventCard(info: tsg.EventCardInfo) {
+    const text = EVENT_TEXT[info.Card?.Event];
+    if (text) {
+        chatMessage({ text: text, color: "black" });
+    }
+}
@@ -29,7 +29,7 @@ export function initialize(s: GameSettings) {
     settingsContainer.cursor = "pointer";
 
     settingDetailsContainer = new PIXI.Container();
-    settingDetailsContainer.addChild(windows.getWindowSprite(170, 240));
+    settingDetailsContainer.addChild(windows.getWindowSprite(170, 280));
     settingDetailsContainer.x = 15;
     settingDetailsContainer.y = 65;
     settingDetailsContainer.zIndex = 20000;
@@ -64,6 +64,8 @@ export function initialize(s: GameSettings) {
     addSettingsText(`Karma: ${settings.EnableKarma ? "Yes" : "No"}`, 8);
     addSettingsText(`Speed: ${capitalizeFirstLetter(settings.Speed)}`, 9);
     addSettingsText(`Advanced Mode: ${settings.Advanced ? "Yes" : "No"}`, 10);
+    addSettingsText(`Seed: ${settings.Seed || "Hidden"}`, 11);
+    addSettingsText(`Ranked: ${settings.Ranked ? "Yes" : "No"}`, 12);
 
     settingsContainer.on("pointerdown", (e) => {
         settingDetailsContainer.visible = !settingDetailsContainer.visible;
@@ -29,10 +29,12 @@ export enum MSG_TYPE {
     SET_SETTINGS = "ss",
     SET_ADVANCED_SETTINGS = "sas",
     BOT_ADD = "bot_a",
+    BOT_STRATEGY = "bot_s",
     KICK = "k",
     READY = "r",
     START_GAME = "sg",
     SINGLE_PLAYER = "sp",
+    REROLL_MAP = "rm",
 
     // CHAT MESSAGE
     CHAT = "c",
@@ -64,6 +66,12 @@ export enum BUILD_OBJECT {
     MOVE_KNIGHT = "km",
     IMPROVEMENT = "i",
     WALL = "w",
+
+    SHIP = "sh",
+    MOVE_SHIP = "ms",
+
+    FISH = "fi",
+    GOLD = "go",
 }
 
 /**
@@ -100,12 +108,15 @@ export interface WsDataMessage {
     // Actions
     ar_data?: any; // Action Response Data
     dct?: number; // Development card type to use
+    fa?: number; // Fish to spend
 
     // Lobby params
     username?: string;
     settings?: any;
     advanced?: any;
     ready?: boolean;
+    strategy?: string;
+    difficulty?: string;
 
     // Chat message
     cmsg?: string;
@@ -136,6 +147,7 @@ export enum MSG_RES_TYPE {
     TRADE_CLOSE_OFFER = "tco",
 
     DICE = "d",
+    EVENT_CARD = "ev",
 
     ACTION_EXPECTED = "a",
 
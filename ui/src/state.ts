@@ -27,7 +27,11 @@ type InitializableSprite = PIXI.Sprite & { initialized?: boolean };
 
 // Players
 let container: PIXI.Container;
-type IconText = { img: PIXI.Sprite; text: PIXI.Text };
+type IconText = {
+    img: PIXI.Sprite;
+    text: PIXI.Text;
+    tooltip: windows.TooltipHandler;
+};
 export let players: {
     bg: PIXI.Graphics;
 
@@ -39,6 +43,7 @@ export let players: {
     dcard: IconText;
     knights: IconText;
     bot: PIXI.Sprite;
+    botDifficulty: PIXI.Text;
     improvements: { [key: number]: PIXI.Sprite[] };
 }[];
 
@@ -68,6 +73,38 @@ let WINDOW_SCALE = 1.08;
 export enum GameMode {
     Base = 1,
     CitiesAndKnights = 2,
+    Seafarers = 3,
+    Fishermen = 4,
+    Rivers = 5,
+    Caravans = 6,
+    BarbarianAttack = 7,
+    TradersAndBarbarians = 8,
+}
+
+/** Modes that play with the base development cards */
+export function usesBaseCards(mode: GameMode) {
+    return mode != GameMode.CitiesAndKnights;
+}
+
+export function hasFish(mode: GameMode) {
+    return (
+        mode == GameMode.Fishermen || mode == GameMode.TradersAndBarbarians
+    );
+}
+
+export function hasRivers(mode: GameMode) {
+    return mode == GameMode.Rivers || mode == GameMode.TradersAndBarbarians;
+}
+
+export function hasCaravans(mode: GameMode) {
+    return mode == GameMode.Caravans || mode == GameMode.TradersAndBarbarians;
+}
+
+export function hasBarbarianAttack(mode: GameMode) {
+    return (
+        mode == GameMode.BarbarianAttack ||
+        mode == GameMode.TradersAndBarbarians
+    );
 }
 
 // Game settings
@@ -269,7 +306,7 @@ export function renderGameState(gs: GameState, commandHub: CommandHub) {
                 imgc.interactive = true;
                 imgc.x = x;
                 imgc.y = y + offset;
-                new windows.TooltipHandler(imgc, title);
+                const tooltip = new windows.TooltipHandler(imgc, title);
                 container.addChild(imgc);
 
                 const img = new PIXI.Sprite();
@@ -288,7 +325,7 @@ export function renderGameState(gs: GameState, commandHub: CommandHub) {
                 text.anchor.y = 0.5;
                 text.y = 1;
                 imgc.addChild(text);
-                return { img, text };
+                return { img, text, tooltip };
             };
 
             // User name
@@ -313,6 +350,16 @@ export function renderGameState(gs: GameState, commandHub: CommandHub) {
             spriteset.bot.visible = false;
             container.addChild(spriteset.bot);
 
+            spriteset.botDifficulty = new PIXI.Text("", {
+                fontFamily: "sans-serif",
+                fontSize: 11,
+                fill: 0x444444,
+                align: "left",
+            });
+            spriteset.botDifficulty.x = spriteset.bot.x + 20;
+            spriteset.botDifficulty.y = spriteset.name.y + 1;
+            container.addChild(spriteset.botDifficulty);
+
             const baseX = -5;
             spriteset.victoryPoint = createText(
                 baseX + 75,
@@ -389,9 +436,21 @@ export function renderGameState(gs: GameState, commandHub: CommandHub) {
         p.road.text.text = `${state.LongestRoad}`;
         p.knights.text.text = `${state.Knights}`;
         p.cards.text.text = `${state.NumCards}`;
+
+        // Open hands show the actual cards
+        if (state.Cards) {
+            const cards: number[] = [];
+            Object.entries(state.Cards).forEach(([t, q]) => {
+                for (let i = 0; i < (q ?? 0); i++) {
+                    cards.push(Number(t));
+                }
+            });
+            p.cards.tooltip.setCards(cards);
+        }
         p.dcard.text.text = `${state.NumDevelopmentCards}`;
         p.bg.visible = state.Current;
         p.bot.visible = !!state.IsBot;
+        p.botDifficulty.text = state.IsBot ? state.BotDifficulty ?? "" : "";
 
         // Highlight extra points
         if (state.HasLongestRoad) {
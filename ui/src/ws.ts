@@ -114,7 +114,9 @@ export function handleResponse(msg: WsResponse) {
             );
             state.renderGameState(gs, commandHub);
             board.setRobberTile(gs.Robber.Tile);
+            board.setPirateTile(gs.Pirate?.Tile);
             board.setMerchantTile(gs.Merchant);
+            board.renderTradersState(gs);
             return;
 
         case MSG_RES_TYPE.SECRET_STATE:
@@ -133,6 +135,10 @@ export function handleResponse(msg: WsResponse) {
             dice.handleMessage(new tsg.DieRollState(msg.data));
             return;
 
+        case MSG_RES_TYPE.EVENT_CARD:
+            notif.showEventCard(new tsg.EventCardInfo(msg.data));
+            return;
+
         case MSG_RES_TYPE.CARD_MOVE:
             state.addPendingCardMoves([new tsg.CardMoveInfo(msg?.data)]);
             return;
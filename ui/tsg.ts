@@ -11,6 +11,11 @@ export type IGameSettings = {
     EnableKarma: boolean;
     Speed: string;
     Advanced: boolean;
+    Seed: number;
+    MapBalance: number;
+    MapVersion: number;
+    Matched: boolean;
+    Ranked: boolean;
 };
 
 export class GameSettings implements IGameSettings {
@@ -24,6 +29,11 @@ export class GameSettings implements IGameSettings {
     public EnableKarma: boolean;
     public Speed: string;
     public Advanced: boolean;
+    public Seed: number;
+    public MapBalance: number;
+    public MapVersion: number;
+    public Matched: boolean;
+    public Ranked: boolean;
 
     constructor(input: any) {
         this.Mode = input.Mode;
@@ -36,6 +46,11 @@ export class GameSettings implements IGameSettings {
         this.EnableKarma = input.EnableKarma;
         this.Speed = input.Speed;
         this.Advanced = input.Advanced;
+        this.Seed = input.Seed;
+        this.MapBalance = input.MapBalance;
+        this.MapVersion = input.MapVersion;
+        this.Matched = input.Matched;
+        this.Ranked = input.Ranked;
     }
 
     public encode() {
@@ -50,6 +65,11 @@ export class GameSettings implements IGameSettings {
         out.EnableKarma = this.EnableKarma;
         out.Speed = this.Speed;
         out.Advanced = this.Advanced;
+        out.Seed = this.Seed;
+        out.MapBalance = this.MapBalance;
+        out.MapVersion = this.MapVersion;
+        out.Matched = this.Matched;
+        out.Ranked = this.Ranked;
         return out;
     }
 }
@@ -58,18 +78,112 @@ export type GameMode = number;
 export type IGameMode = number;
 export type IAdvancedSettings = {
     RerollOn7: boolean;
+    FriendlyRobber: number;
+    NoSevensFirstRound: boolean;
+    LongestRoadLength: number;
+    LongestRoadVP: number;
+    LargestArmySize: number;
+    LargestArmyVP: number;
+    Costs: BuildingCosts /* entities.BuildingCosts */;
+    MaxDevelopmentCardsPerTurn: number;
+    OpenHands: boolean;
+    BalancedDice: boolean;
+    BalancedDiceReshuffle: number;
+    BalancedDiceDampenRepeats: boolean;
+    BalancedDiceLeaderPenalty: boolean;
+    EventCards: boolean;
+    EventCardEvents: boolean;
 };
 
 export class AdvancedSettings implements IAdvancedSettings {
     public RerollOn7: boolean;
+    public FriendlyRobber: number;
+    public NoSevensFirstRound: boolean;
+    public LongestRoadLength: number;
+    public LongestRoadVP: number;
+    public LargestArmySize: number;
+    public LargestArmyVP: number;
+    public Costs: BuildingCosts /* entities.BuildingCosts */;
+    public MaxDevelopmentCardsPerTurn: number;
+    public OpenHands: boolean;
+    public BalancedDice: boolean;
+    public BalancedDiceReshuffle: number;
+    public BalancedDiceDampenRepeats: boolean;
+    public BalancedDiceLeaderPenalty: boolean;
+    public EventCards: boolean;
+    public EventCardEvents: boolean;
 
     constructor(input: any) {
         this.RerollOn7 = input.RerollOn7;
+        this.FriendlyRobber = input.FriendlyRobber;
+        this.NoSevensFirstRound = input.NoSevensFirstRound;
+        this.LongestRoadLength = input.LongestRoadLength;
+        this.LongestRoadVP = input.LongestRoadVP;
+        this.LargestArmySize = input.LargestArmySize;
+        this.LargestArmyVP = input.LargestArmyVP;
+        this.Costs = input.Costs ? new BuildingCosts(input.Costs) : input.Costs;
+        this.MaxDevelopmentCardsPerTurn = input.MaxDevelopmentCardsPerTurn;
+        this.OpenHands = input.OpenHands;
+        this.BalancedDice = input.BalancedDice;
+        this.BalancedDiceReshuffle = input.BalancedDiceReshuffle;
+        this.BalancedDiceDampenRepeats = input.BalancedDiceDampenRepeats;
+        this.BalancedDiceLeaderPenalty = input.BalancedDiceLeaderPenalty;
+        this.EventCards = input.EventCards;
+        this.EventCardEvents = input.EventCardEvents;
     }
 
     public encode() {
         const out: any = {};
         out.RerollOn7 = this.RerollOn7;
+        out.FriendlyRobber = this.FriendlyRobber;
+        out.NoSevensFirstRound = this.NoSevensFirstRound;
+        out.LongestRoadLength = this.LongestRoadLength;
+        out.LongestRoadVP = this.LongestRoadVP;
+        out.LargestArmySize = this.LargestArmySize;
+        out.LargestArmyVP = this.LargestArmyVP;
+        out.Costs = this.Costs?.encode?.();
+        out.MaxDevelopmentCardsPerTurn = this.MaxDevelopmentCardsPerTurn;
+        out.OpenHands = this.OpenHands;
+        out.BalancedDice = this.BalancedDice;
+        out.BalancedDiceReshuffle = this.BalancedDiceReshuffle;
+        out.BalancedDiceDampenRepeats = this.BalancedDiceDampenRepeats;
+        out.BalancedDiceLeaderPenalty = this.BalancedDiceLeaderPenalty;
+        out.EventCards = this.EventCards;
+        out.EventCardEvents = this.EventCardEvents;
+        return out;
+    }
+}
+
+export type IBuildingCosts = {
+    Road: number[];
+    Settlement: number[];
+    City: number[];
+    Ship: number[];
+    DevelopmentCard: number[];
+};
+
+export class BuildingCosts implements IBuildingCosts {
+    public Road: number[];
+    public Settlement: number[];
+    public City: number[];
+    public Ship: number[];
+    public DevelopmentCard: number[];
+
+    constructor(input: any) {
+        this.Road = input.Road;
+        this.Settlement = input.Settlement;
+        this.City = input.City;
+        this.Ship = input.Ship;
+        this.DevelopmentCard = input.DevelopmentCard;
+    }
+
+    public encode() {
+        const out: any = {};
+        out.Road = this.Road;
+        out.Settlement = this.Settlement;
+        out.City = this.City;
+        out.Ship = this.Ship;
+        out.DevelopmentCard = this.DevelopmentCard;
         return out;
     }
 }
@@ -78,27 +192,36 @@ export type IGameState = {
     CurrentPlayerOrder: number;
     NeedDice: boolean;
     Robber: Robber /* entities.Robber */;
+    Pirate?: Robber /* entities.Robber */;
     PlayerStates: PlayerState /* []*entities.PlayerState */[];
     BarbarianPosition: number;
     BarbarianStrength: number;
     BarbarianKnights: number;
     Merchant: Merchant /* entities.Merchant */;
+    Camels?: EdgeCoordinate /* []entities.EdgeCoordinate */[];
+    Barbarians?: TileBarbarians /* []*entities.TileBarbarians */[];
+    Event?: number;
 };
 
 export class GameState implements IGameState {
     public CurrentPlayerOrder: number;
     public NeedDice: boolean;
     public Robber: Robber /* entities.Robber */;
+    public Pirate?: Robber /* entities.Robber */;
     public PlayerStates: PlayerState /* []*entities.PlayerState */[];
     public BarbarianPosition: number;
     public BarbarianStrength: number;
     public BarbarianKnights: number;
     public Merchant: Merchant /* entities.Merchant */;
+    public Camels?: EdgeCoordinate /* []entities.EdgeCoordinate */[];
+    public Barbarians?: TileBarbarians /* []*entities.TileBarbarians */[];
+    public Event?: number;
 
     constructor(input: any) {
         this.CurrentPlayerOrder = input.c;
         this.NeedDice = input.d;
         this.Robber = input.r ? new Robber(input.r) : input.r;
+        this.Pirate = input.pi ? new Robber(input.pi) : input.pi;
         this.PlayerStates = input.p?.map((v: any) =>
             v ? new PlayerState(v) : undefined,
         );
@@ -106,6 +229,13 @@ export class GameState implements IGameState {
         this.BarbarianStrength = input.bs;
         this.BarbarianKnights = input.bk;
         this.Merchant = input.tm ? new Merchant(input.tm) : input.tm;
+        this.Camels = input.cm?.map((v: any) =>
+            v ? new EdgeCoordinate(v) : undefined,
+        );
+        this.Barbarians = input.ba?.map((v: any) =>
+            v ? new TileBarbarians(v) : undefined,
+        );
+        this.Event = input.ev;
     }
 
     public encode() {
@@ -113,11 +243,37 @@ export class GameState implements IGameState {
         out.c = this.CurrentPlayerOrder;
         out.d = this.NeedDice;
         out.r = this.Robber?.encode?.();
+        out.pi = this.Pirate?.encode?.();
         out.p = this.PlayerStates?.map((v: any) => v?.encode?.());
         out.bp = this.BarbarianPosition;
         out.bs = this.BarbarianStrength;
         out.bk = this.BarbarianKnights;
         out.tm = this.Merchant?.encode?.();
+        out.cm = this.Camels?.map((v: any) => v?.encode?.());
+        out.ba = this.Barbarians?.map((v: any) => v?.encode?.());
+        out.ev = this.Event;
+        return out;
+    }
+}
+
+export type ITileBarbarians = {
+    Tile: Coordinate /* entities.Coordinate */;
+    Count: number;
+};
+
+export class TileBarbarians implements ITileBarbarians {
+    public Tile: Coordinate /* entities.Coordinate */;
+    public Count: number;
+
+    constructor(input: any) {
+        this.Tile = input.t ? new Coordinate(input.t) : input.t;
+        this.Count = input.n;
+    }
+
+    public encode() {
+        const out: any = {};
+        out.t = this.Tile?.encode?.();
+        out.n = this.Count;
         return out;
     }
 }
@@ -211,9 +367,14 @@ export type IPlayerState = {
     Improvements: { [key: int]: int | undefined };
     DiscardLimit: number;
     IsBot?: boolean;
+    BotDifficulty?: string;
     HasLongestRoad?: boolean;
     HasLargestArmy?: boolean;
     DevCardVp?: number;
+    Fish?: number;
+    Gold?: number;
+    CapturedBarbarians?: number;
+    Cards?: { [key: CardType]: int | undefined };
 };
 
 export class PlayerState implements IPlayerState {
@@ -233,9 +394,14 @@ export class PlayerState implements IPlayerState {
     public Improvements: { [key: int]: int | undefined };
     public DiscardLimit: number;
     public IsBot?: boolean;
+    public BotDifficulty?: string;
     public HasLongestRoad?: boolean;
     public HasLargestArmy?: boolean;
     public DevCardVp?: number;
+    public Fish?: number;
+    public Gold?: number;
+    public CapturedBarbarians?: number;
+    public Cards?: { [key: CardType]: int | undefined };
 
     constructor(input: any) {
         this.Id = input.id;
@@ -254,9 +420,14 @@ export class PlayerState implements IPlayerState {
         this.Improvements = input.ci;
         this.DiscardLimit = input.l;
         this.IsBot = input.b;
+        this.BotDifficulty = input.bd;
         this.HasLongestRoad = input.lr;
         this.HasLargestArmy = input.la;
         this.DevCardVp = input.dv;
+        this.Fish = input.fi;
+        this.Gold = input.go;
+        this.CapturedBarbarians = input.ba;
+        this.Cards = input.oh;
     }
 
     public encode() {
@@ -277,9 +448,14 @@ export class PlayerState implements IPlayerState {
         out.ci = this.Improvements;
         out.l = this.DiscardLimit;
         out.b = this.IsBot;
+        out.bd = this.BotDifficulty;
         out.lr = this.HasLongestRoad;
         out.la = this.HasLargestArmy;
         out.dv = this.DevCardVp;
+        out.fi = this.Fish;
+        out.go = this.Gold;
+        out.ba = this.CapturedBarbarians;
+        out.oh = this.Cards;
         return out;
     }
 }
@@ -356,17 +532,20 @@ export type IEdge = {
     C: EdgeCoordinate /* entities.EdgeCoordinate */;
     IsBeach: boolean;
     Orientation: number;
+    IsRiver?: boolean;
 };
 
 export class Edge implements IEdge {
     public C: EdgeCoordinate /* entities.EdgeCoordinate */;
     public IsBeach: boolean;
     public Orientation: number;
+    public IsRiver?: boolean;
 
     constructor(input: any) {
         this.C = input.c ? new EdgeCoordinate(input.c) : input.c;
         this.IsBeach = input.b;
         this.Orientation = input.o;
+        this.IsRiver = input.r;
     }
 
     public encode() {
@@ -374,6 +553,7 @@ export class Edge implements IEdge {
         out.c = this.C?.encode?.();
         out.b = this.IsBeach;
         out.o = this.Orientation;
+        out.r = this.IsRiver;
         return out;
     }
 }
@@ -557,6 +737,10 @@ export type IAllowedActionsMap = {
     ImprovePaper?: boolean;
     ImproveCloth?: boolean;
     ImproveCoin?: boolean;
+    BuildShip?: boolean;
+    MoveShip?: boolean;
+    UseFish?: boolean;
+    SpendGold?: boolean;
     SpecialBuild?: boolean;
 };
 
@@ -575,6 +759,10 @@ export class AllowedActionsMap implements IAllowedActionsMap {
     public ImprovePaper?: boolean;
     public ImproveCloth?: boolean;
     public ImproveCoin?: boolean;
+    public BuildShip?: boolean;
+    public MoveShip?: boolean;
+    public UseFish?: boolean;
+    public SpendGold?: boolean;
     public SpecialBuild?: boolean;
 
     constructor(input: any) {
@@ -592,6 +780,10 @@ export class AllowedActionsMap implements IAllowedActionsMap {
         this.ImprovePaper = input.ip;
         this.ImproveCloth = input.il;
         this.ImproveCoin = input.ic;
+        this.BuildShip = input.sh;
+        this.MoveShip = input.ms;
+        this.UseFish = input.fi;
+        this.SpendGold = input.go;
         this.SpecialBuild = input.sb;
     }
 
@@ -611,6 +803,10 @@ export class AllowedActionsMap implements IAllowedActionsMap {
         out.ip = this.ImprovePaper;
         out.il = this.ImproveCloth;
         out.ic = this.ImproveCoin;
+        out.sh = this.BuildShip;
+        out.ms = this.MoveShip;
+        out.fi = this.UseFish;
+        out.go = this.SpendGold;
         out.sb = this.SpecialBuild;
         return out;
     }
@@ -652,6 +848,54 @@ export class DieRollState implements IDieRollState {
     }
 }
 
+export type IEventCard = {
+    RedRoll: number;
+    WhiteRoll: number;
+    Event: number;
+};
+
+export class EventCard implements IEventCard {
+    public RedRoll: number;
+    public WhiteRoll: number;
+    public Event: number;
+
+    constructor(input: any) {
+        this.RedRoll = input.r;
+        this.WhiteRoll = input.w;
+        this.Event = input.e;
+    }
+
+    public encode() {
+        const out: any = {};
+        out.r = this.RedRoll;
+        out.w = this.WhiteRoll;
+        out.e = this.Event;
+        return out;
+    }
+}
+
+export type IEventCardInfo = {
+    Card: EventCard /* entities.EventCard */;
+    Left: number;
+};
+
+export class EventCardInfo implements IEventCardInfo {
+    public Card: EventCard /* entities.EventCard */;
+    public Left: number;
+
+    constructor(input: any) {
+        this.Card = input.c ? new EventCard(input.c) : input.c;
+        this.Left = input.l;
+    }
+
+    public encode() {
+        const out: any = {};
+        out.c = this.Card?.encode?.();
+        out.l = this.Left;
+        return out;
+    }
+}
+
 export type ICardMoveInfo = {
     Tile: Tile /* entities.Tile */;
     GainerOrder: number;
@@ -829,23 +1073,221 @@ export class TradeOfferDetails implements ITradeOfferDetails {
 export type IGameOverMessage = {
     Players: PlayerState /* []*entities.PlayerState */[];
     Winner: number;
+    Report?: GameReport /* *entities.GameReport */;
+    Seed: number;
 };
 
 export class GameOverMessage implements IGameOverMessage {
     public Players: PlayerState /* []*entities.PlayerState */[];
     public Winner: number;
+    public Report?: GameReport /* *entities.GameReport */;
+    public Seed: number;
 
     constructor(input: any) {
         this.Players = input.p?.map((v: any) =>
             v ? new PlayerState(v) : undefined,
         );
         this.Winner = input.w;
+        this.Report = input.r ? new GameReport(input.r) : input.r;
+        this.Seed = input.s;
     }
 
     public encode() {
         const out: any = {};
         out.p = this.Players?.map((v: any) => v?.encode?.());
         out.w = this.Winner;
+        out.r = this.Report?.encode?.();
+        out.s = this.Seed;
+        return out;
+    }
+}
+
+export type IGameReport = {
+    ID: string;
+    Winner: int;
+    Turns: int;
+    Players: PlayerStats /* []*entities.PlayerStats */[];
+    Tiles: TileStats /* []*entities.TileStats */[];
+    Dice?: DiceStats /* *entities.DiceStats */;
+};
+
+export class GameReport implements IGameReport {
+    public ID: string;
+    public Winner: int;
+    public Turns: int;
+    public Players: PlayerStats /* []*entities.PlayerStats */[];
+    public Tiles: TileStats /* []*entities.TileStats */[];
+    public Dice?: DiceStats /* *entities.DiceStats */;
+
+    constructor(input: any) {
+        this.ID = input.id;
+        this.Winner = input.w;
+        this.Turns = input.n;
+        this.Players = input.p?.map((v: any) =>
+            v ? new PlayerStats(v) : undefined,
+        );
+        this.Tiles = input.t?.map((v: any) =>
+            v ? new TileStats(v) : undefined,
+        );
+        this.Dice = input.d ? new DiceStats(input.d) : input.d;
+    }
+
+    public encode() {
+        const out: any = {};
+        out.id = this.ID;
+        out.w = this.Winner;
+        out.n = this.Turns;
+        out.p = this.Players?.map((v: any) => v?.encode?.());
+        out.t = this.Tiles?.map((v: any) => v?.encode?.());
+        out.d = this.Dice?.encode?.();
+        return out;
+    }
+}
+
+export type IPlayerStats = {
+    Order: number;
+    Username: string;
+    Produced: int /* [9]int */[];
+    Gold: int;
+    Blocked: int;
+    Expected: number;
+    Stolen: int;
+    Robbed: int;
+    Discarded: int;
+    Trades: int /* []int */[];
+    TradeVolume: int /* []int */[];
+    CardsGiven: int;
+    CardsTaken: int;
+    BankTrades: int;
+    BankGiven: int;
+    BankTaken: int;
+    CardsPlayed: { [key: DevelopmentCardType]: int | undefined };
+    VictoryPoints: int /* []int */[];
+    Openings: Coordinate /* []entities.Coordinate */[];
+};
+
+export class PlayerStats implements IPlayerStats {
+    public Order: number;
+    public Username: string;
+    public Produced: int /* [9]int */[];
+    public Gold: int;
+    public Blocked: int;
+    public Expected: number;
+    public Stolen: int;
+    public Robbed: int;
+    public Discarded: int;
+    public Trades: int /* []int */[];
+    public TradeVolume: int /* []int */[];
+    public CardsGiven: int;
+    public CardsTaken: int;
+    public BankTrades: int;
+    public BankGiven: int;
+    public BankTaken: int;
+    public CardsPlayed: { [key: DevelopmentCardType]: int | undefined };
+    public VictoryPoints: int /* []int */[];
+    public Openings: Coordinate /* []entities.Coordinate */[];
+
+    constructor(input: any) {
+        this.Order = input.o;
+        this.Username = input.u;
+        this.Produced = input.pr;
+        this.Gold = input.au;
+        this.Blocked = input.bl;
+        this.Expected = input.ex;
+        this.Stolen = input.st;
+        this.Robbed = input.rb;
+        this.Discarded = input.di;
+        this.Trades = input.tr;
+        this.TradeVolume = input.tv;
+        this.CardsGiven = input.cg;
+        this.CardsTaken = input.ct;
+        this.BankTrades = input.bt;
+        this.BankGiven = input.bg;
+        this.BankTaken = input.bk;
+        this.CardsPlayed = input.cp;
+        this.VictoryPoints = input.vp;
+        this.Openings = input.op?.map((v: any) =>
+            v ? new Coordinate(v) : undefined,
+        );
+    }
+
+    public encode() {
+        const out: any = {};
+        out.o = this.Order;
+        out.u = this.Username;
+        out.pr = this.Produced;
+        out.au = this.Gold;
+        out.bl = this.Blocked;
+        out.ex = this.Expected;
+        out.st = this.Stolen;
+        out.rb = this.Robbed;
+        out.di = this.Discarded;
+        out.tr = this.Trades;
+        out.tv = this.TradeVolume;
+        out.cg = this.CardsGiven;
+        out.ct = this.CardsTaken;
+        out.bt = this.BankTrades;
+        out.bg = this.BankGiven;
+        out.bk = this.BankTaken;
+        out.cp = this.CardsPlayed;
+        out.vp = this.VictoryPoints;
+        out.op = this.Openings?.map((v: any) => v?.encode?.());
+        return out;
+    }
+}
+
+export type ITileStats = {
+    Tile: Coordinate /* entities.Coordinate */;
+    Type: TileType /* entities.TileType */;
+    Number: number;
+    Produced: int;
+    Blocked: int;
+};
+
+export class TileStats implements ITileStats {
+    public Tile: Coordinate /* entities.Coordinate */;
+    public Type: TileType /* entities.TileType */;
+    public Number: number;
+    public Produced: int;
+    public Blocked: int;
+
+    constructor(input: any) {
+        this.Tile = input.c ? new Coordinate(input.c) : input.c;
+        this.Type = input.t;
+        this.Number = input.n;
+        this.Produced = input.p;
+        this.Blocked = input.b;
+    }
+
+    public encode() {
+        const out: any = {};
+        out.c = this.Tile?.encode?.();
+        out.t = this.Type;
+        out.n = this.Number;
+        out.p = this.Produced;
+        out.b = this.Blocked;
+        return out;
+    }
+}
+
+export type IDiceStats = {
+    Rolls: int /* [12]int */[];
+    EventRolls: int /* [6]int */[];
+};
+
+export class DiceStats implements IDiceStats {
+    public Rolls: int /* [12]int */[];
+    public EventRolls: int /* [6]int */[];
+
+    constructor(input: any) {
+        this.Rolls = input.r;
+        this.EventRolls = input.e;
+    }
+
+    public encode() {
+        const out: any = {};
+        out.r = this.Rolls;
+        out.e = this.EventRolls;
         return out;
     }
 }
@@ -1001,6 +1443,10 @@ export type ILobbyPlayerState = {
     Ready: boolean;
     GamesStarted: number;
     GamesFinished: number;
+    BotStrategy?: string;
+    BotDifficulty?: string;
+    External?: boolean;
+    Rating?: number;
 };
 
 export class LobbyPlayerState implements ILobbyPlayerState {
@@ -1010,6 +1456,10 @@ export class LobbyPlayerState implements ILobbyPlayerState {
     public Ready: boolean;
     public GamesStarted: number;
     public GamesFinished: number;
+    public BotStrategy?: string;
+    public BotDifficulty?: string;
+    public External?: boolean;
+    public Rating?: number;
 
     constructor(input: any) {
         this.Username = input.u;
@@ -1018,6 +1468,10 @@ export class LobbyPlayerState implements ILobbyPlayerState {
         this.Ready = input.r;
         this.GamesStarted = input.s;
         this.GamesFinished = input.f;
+        this.BotStrategy = input.bs;
+        this.BotDifficulty = input.bd;
+        this.External = input.x;
+        this.Rating = input.rt;
     }
 
     public encode() {
@@ -1028,6 +1482,10 @@ export class LobbyPlayerState implements ILobbyPlayerState {
         out.r = this.Ready;
         out.s = this.GamesStarted;
         out.f = this.GamesFinished;
+        out.bs = this.BotStrategy;
+        out.bd = this.BotDifficulty;
+        out.x = this.External;
+        out.rt = this.Rating;
         return out;
     }
 }